@@ -41,13 +41,8 @@ func InitCloudinary() {
 }
 
 func UploadImage(ctx context.Context, file interface{}, folder string) (string, error) {
-	uploadResult, err := Cld.Upload.Upload(ctx, file, uploader.UploadParams{
-		Folder: folder,
-	})
-	if err != nil {
-		return "", err
-	}
-	return uploadResult.SecureURL, nil
+	url, _, err := UploadImageWithPublicID(ctx, file, folder)
+	return url, err
 }
 
 func UploadImageFromPath(ctx context.Context, filePath string, folder string) (string, error) {
@@ -65,4 +60,16 @@ func DeleteImage(ctx context.Context, publicID string) error {
 		PublicID: publicID,
 	})
 	return err
-}
\ No newline at end of file
+}
+
+// UploadImageWithPublicID sama seperti UploadImage, tetapi juga mengembalikan public ID
+// yang dibutuhkan DeleteImage untuk menghapus file dari Cloudinary
+func UploadImageWithPublicID(ctx context.Context, file interface{}, folder string) (string, string, error) {
+	uploadResult, err := Cld.Upload.Upload(ctx, file, uploader.UploadParams{
+		Folder: folder,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return uploadResult.SecureURL, uploadResult.PublicID, nil
+}
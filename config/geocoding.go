@@ -0,0 +1,157 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Geocoder mengubah alamat teks menjadi koordinat. Provider bisa diganti lewat GEOCODER_PROVIDER.
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (lat float64, lng float64, err error)
+}
+
+var Geo Geocoder
+
+var ErrAddressNotFound = errors.New("address not found")
+
+// InitGeocoder memilih provider geocoding:
+//   - "nominatim": OpenStreetMap Nominatim (GEOCODER_URL dan GEOCODER_USER_AGENT opsional)
+//   - "stub": lookup lokal kota-kota besar, hanya untuk development tanpa akses internet
+//   - "none" / kosong: geocoding dimatikan
+//
+// Nama provider lain dianggap salah konfigurasi dan menghentikan aplikasi.
+func InitGeocoder() {
+	provider := strings.ToLower(strings.TrimSpace(os.Getenv("GEOCODER_PROVIDER")))
+
+	switch provider {
+	case "nominatim":
+		baseURL := os.Getenv("GEOCODER_URL")
+		if baseURL == "" {
+			baseURL = "https://nominatim.openstreetmap.org/search"
+		}
+		userAgent := os.Getenv("GEOCODER_USER_AGENT")
+		if userAgent == "" {
+			userAgent = "tikeria-backend"
+		}
+		Geo = &NominatimGeocoder{
+			BaseURL:   baseURL,
+			UserAgent: userAgent,
+			Client:    &http.Client{Timeout: 5 * time.Second},
+		}
+	case "stub":
+		Geo = StubGeocoder{}
+	case "", "none":
+		provider = "none"
+		Geo = nil
+	default:
+		log.Fatal("Unknown GEOCODER_PROVIDER: ", provider)
+	}
+
+	log.Println("Geocoder provider:", provider)
+}
+
+// Geocode memakai provider aktif, mengembalikan ErrAddressNotFound jika geocoding dimatikan
+func Geocode(ctx context.Context, address string) (float64, float64, error) {
+	if Geo == nil {
+		return 0, 0, ErrAddressNotFound
+	}
+	return Geo.Geocode(ctx, address)
+}
+
+type NominatimGeocoder struct {
+	BaseURL   string
+	UserAgent string
+	Client    *http.Client
+}
+
+func (g *NominatimGeocoder) Geocode(ctx context.Context, address string) (float64, float64, error) {
+	params := url.Values{}
+	params.Set("q", address)
+	params.Set("format", "json")
+	params.Set("limit", "1")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.BaseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("User-Agent", g.UserAgent)
+
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("geocoder returned status %d", resp.StatusCode)
+	}
+
+	var results []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return 0, 0, err
+	}
+	if len(results) == 0 {
+		return 0, 0, ErrAddressNotFound
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	lng, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return lat, lng, nil
+}
+
+// StubGeocoder mencocokkan nama kota di alamat dengan koordinat pusat kota
+type StubGeocoder struct{}
+
+// Urutan penting: kota yang lebih spesifik dicek lebih dulu
+var stubCityCoordinates = []struct {
+	city     string
+	lat, lng float64
+}{
+	{"jakarta", -6.2088, 106.8456},
+	{"bandung", -6.9175, 107.6191},
+	{"surabaya", -7.2575, 112.7521},
+	{"yogyakarta", -7.7956, 110.3695},
+	{"semarang", -6.9667, 110.4167},
+	{"malang", -7.9666, 112.6326},
+	{"denpasar", -8.6705, 115.2126},
+	{"bali", -8.4095, 115.1889},
+	{"medan", 3.5952, 98.6722},
+	{"makassar", -5.1477, 119.4327},
+	{"palembang", -2.9761, 104.7754},
+	{"balikpapan", -1.2379, 116.8529},
+	{"manado", 1.4748, 124.8421},
+	{"jayapura", -2.5337, 140.7181},
+	{"bogor", -6.5950, 106.8166},
+	{"depok", -6.4025, 106.7942},
+	{"tangerang", -6.1783, 106.6319},
+	{"bekasi", -6.2383, 106.9756},
+	{"solo", -7.5755, 110.8243},
+}
+
+func (StubGeocoder) Geocode(_ context.Context, address string) (float64, float64, error) {
+	lower := strings.ToLower(address)
+	for _, entry := range stubCityCoordinates {
+		if strings.Contains(lower, entry.city) {
+			return entry.lat, entry.lng, nil
+		}
+	}
+	return 0, 0, ErrAddressNotFound
+}
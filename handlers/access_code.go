@@ -0,0 +1,427 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AccessCodeRequest struct {
+	Code              string   `json:"code"`
+	UsageType         string   `json:"usage_type"` // single, multi
+	UsageLimit        *uint    `json:"usage_limit"`
+	ExpiresAt         string   `json:"expires_at"`
+	IsActive          *bool    `json:"is_active"`
+	TicketCategoryIDs []string `json:"ticket_category_ids"`
+}
+
+var (
+	errAccessCodeRequired = errors.New("access code is required for this ticket category")
+	errAccessCodeInvalid  = errors.New("access code is invalid")
+	errAccessCodeExpired  = errors.New("access code has expired")
+	errAccessCodeUsedUp   = errors.New("access code usage limit reached")
+	errAccessCodeCategory = errors.New("access code does not unlock this ticket category")
+)
+
+// resolveAccessCode memvalidasi kode akses untuk ticket category tertentu.
+// Category yang tidak hidden tidak butuh kode, sehingga mengembalikan nil tanpa error.
+func resolveAccessCode(db *gorm.DB, ticketCategory models.TicketCategory, code string) (*models.AccessCode, error) {
+	if !ticketCategory.IsHidden {
+		return nil, nil
+	}
+
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, errAccessCodeRequired
+	}
+
+	var accessCode models.AccessCode
+	if err := db.Preload("TicketCategories").
+		Where("code = ? AND event_id = ?", strings.ToUpper(code), ticketCategory.EventID).
+		First(&accessCode).Error; err != nil {
+		return nil, errAccessCodeInvalid
+	}
+
+	if err := checkAccessCodeUsable(accessCode); err != nil {
+		return nil, err
+	}
+
+	for _, tc := range accessCode.TicketCategories {
+		if tc.TicketCategoryID == ticketCategory.TicketCategoryID {
+			return &accessCode, nil
+		}
+	}
+
+	return nil, errAccessCodeCategory
+}
+
+func resolveAccessCodeByID(db *gorm.DB, ticketCategory models.TicketCategory, accessCodeID string) (*models.AccessCode, error) {
+	if !ticketCategory.IsHidden {
+		return nil, nil
+	}
+	if accessCodeID == "" {
+		return nil, errAccessCodeRequired
+	}
+
+	var accessCode models.AccessCode
+	if err := db.Where("access_code_id = ?", accessCodeID).First(&accessCode).Error; err != nil {
+		return nil, errAccessCodeInvalid
+	}
+	return resolveAccessCode(db, ticketCategory, accessCode.Code)
+}
+
+func checkAccessCodeUsable(accessCode models.AccessCode) error {
+	if !accessCode.IsActive {
+		return errAccessCodeInvalid
+	}
+	if accessCode.ExpiresAt != nil && accessCode.ExpiresAt.Before(time.Now()) {
+		return errAccessCodeExpired
+	}
+	if accessCode.UsageLimit > 0 && accessCode.UsedCount >= accessCode.UsageLimit {
+		return errAccessCodeUsedUp
+	}
+	return nil
+}
+
+// consumeAccessCode menambah used_count secara atomik, gagal jika limit sudah tercapai
+func consumeAccessCode(tx *gorm.DB, accessCodeID string) error {
+	result := tx.Model(&models.AccessCode{}).
+		Where("access_code_id = ? AND is_active = ? AND (usage_limit = 0 OR used_count < usage_limit)", accessCodeID, true).
+		UpdateColumn("used_count", gorm.Expr("used_count + ?", 1))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errAccessCodeUsedUp
+	}
+	return nil
+}
+
+// releaseAccessCode mengembalikan satu pemakaian kode ketika pembayaran gagal
+func releaseAccessCode(tx *gorm.DB, accessCodeID string) error {
+	return tx.Model(&models.AccessCode{}).
+		Where("access_code_id = ? AND used_count > 0", accessCodeID).
+		UpdateColumn("used_count", gorm.Expr("used_count - ?", 1)).Error
+}
+
+// unlockedCategoryIDs mengembalikan ticket category hidden yang dibuka oleh kode akses pada event
+func unlockedCategoryIDs(db *gorm.DB, eventID, code string) []string {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil
+	}
+
+	var accessCode models.AccessCode
+	if err := db.Preload("TicketCategories").
+		Where("code = ? AND event_id = ?", strings.ToUpper(code), eventID).
+		First(&accessCode).Error; err != nil {
+		return nil
+	}
+	if checkAccessCodeUsable(accessCode) != nil {
+		return nil
+	}
+
+	var ids []string
+	for _, tc := range accessCode.TicketCategories {
+		ids = append(ids, tc.TicketCategoryID)
+	}
+	return ids
+}
+
+// publicTicketCategories dipakai untuk Preload pada endpoint publik agar category hidden tidak terlihat
+func publicTicketCategories(db *gorm.DB) *gorm.DB {
+	return db.Where("is_hidden = ?", false)
+}
+
+func CreateAccessCode(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	eventID := c.Params("id")
+
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if !canAccessEvent(config.DB, user, event, permEventManage) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to manage access codes for this event",
+		})
+	}
+
+	var req AccessCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+
+	if len(req.TicketCategoryIDs) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "At least one ticket category is required",
+		})
+	}
+
+	accessCode := models.AccessCode{
+		AccessCodeID: utils.GenerateAccessCodeID(),
+		EventID:      event.EventID,
+		Code:         strings.ToUpper(strings.TrimSpace(req.Code)),
+		IsActive:     true,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	if accessCode.Code == "" {
+		accessCode.Code = utils.GenerateAccessCode()
+	}
+
+	if err := applyAccessCodeUsage(&accessCode, req, utils.TimeZoneOrDefault(event.TimeZone)); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var ticketCategories []models.TicketCategory
+	if err := config.DB.Where("event_id = ? AND ticket_category_id IN ?", event.EventID, req.TicketCategoryIDs).
+		Find(&ticketCategories).Error; err != nil || len(ticketCategories) != len(req.TicketCategoryIDs) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "One or more ticket categories not found in this event",
+		})
+	}
+	accessCode.TicketCategories = ticketCategories
+
+	var existing models.AccessCode
+	if err := config.DB.Where("code = ?", accessCode.Code).First(&existing).Error; err == nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Access code already exists",
+		})
+	}
+
+	if err := config.DB.Create(&accessCode).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create access code: " + err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":     "Access code created successfully",
+		"access_code": accessCode,
+	})
+}
+
+func GetAccessCodes(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	eventID := c.Params("id")
+
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if !canAccessEvent(config.DB, user, event, permEventView) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to view access codes for this event",
+		})
+	}
+
+	var accessCodes []models.AccessCode
+	if err := config.DB.Preload("TicketCategories").
+		Where("event_id = ?", event.EventID).
+		Order("created_at DESC").
+		Find(&accessCodes).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch access codes",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":      "Access codes retrieved successfully",
+		"access_codes": accessCodes,
+	})
+}
+
+func UpdateAccessCode(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	eventID := c.Params("id")
+	accessCodeID := c.Params("code_id")
+
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if !canAccessEvent(config.DB, user, event, permEventManage) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to manage access codes for this event",
+		})
+	}
+
+	var accessCode models.AccessCode
+	if err := config.DB.Where("access_code_id = ? AND event_id = ?", accessCodeID, event.EventID).
+		First(&accessCode).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Access code not found",
+		})
+	}
+
+	var req AccessCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+
+	if req.UsageType == "" {
+		req.UsageType = accessCode.UsageType
+	}
+	if err := applyAccessCodeUsage(&accessCode, req, utils.TimeZoneOrDefault(event.TimeZone)); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if req.IsActive != nil {
+		accessCode.IsActive = *req.IsActive
+	}
+	accessCode.UpdatedAt = time.Now()
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	if err := tx.Save(&accessCode).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update access code",
+		})
+	}
+
+	if len(req.TicketCategoryIDs) > 0 {
+		var ticketCategories []models.TicketCategory
+		if err := tx.Where("event_id = ? AND ticket_category_id IN ?", event.EventID, req.TicketCategoryIDs).
+			Find(&ticketCategories).Error; err != nil || len(ticketCategories) != len(req.TicketCategoryIDs) {
+			tx.Rollback()
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "One or more ticket categories not found in this event",
+			})
+		}
+
+		if err := tx.Model(&accessCode).Association("TicketCategories").Replace(ticketCategories); err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update access code categories",
+			})
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	config.DB.Preload("TicketCategories").First(&accessCode, "access_code_id = ?", accessCode.AccessCodeID)
+
+	return c.JSON(fiber.Map{
+		"message":     "Access code updated successfully",
+		"access_code": accessCode,
+	})
+}
+
+func DeleteAccessCode(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	eventID := c.Params("id")
+	accessCodeID := c.Params("code_id")
+
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if !canAccessEvent(config.DB, user, event, permEventManage) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to manage access codes for this event",
+		})
+	}
+
+	var accessCode models.AccessCode
+	if err := config.DB.Where("access_code_id = ? AND event_id = ?", accessCodeID, event.EventID).
+		First(&accessCode).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Access code not found",
+		})
+	}
+
+	// Kode yang sudah pernah dipakai cukup dinonaktifkan agar histori transaksi tetap utuh
+	if accessCode.UsedCount > 0 {
+		if err := config.DB.Model(&accessCode).Updates(map[string]interface{}{
+			"is_active":  false,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to deactivate access code",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"message": "Access code has been used and was deactivated instead of deleted",
+		})
+	}
+
+	if err := config.DB.Model(&accessCode).Association("TicketCategories").Clear(); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete access code",
+		})
+	}
+
+	if err := config.DB.Delete(&accessCode).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete access code",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Access code deleted successfully",
+	})
+}
+
+func applyAccessCodeUsage(accessCode *models.AccessCode, req AccessCodeRequest, loc *time.Location) error {
+	switch req.UsageType {
+	case "single":
+		accessCode.UsageType = "single"
+		accessCode.UsageLimit = 1
+	case "multi", "":
+		accessCode.UsageType = "multi"
+		if req.UsageLimit != nil {
+			accessCode.UsageLimit = *req.UsageLimit
+		}
+	default:
+		return errors.New("usage_type must be either 'single' or 'multi'")
+	}
+
+	if req.ExpiresAt != "" {
+		// Tanpa offset diartikan sebagai jam lokal zona event
+		expiresAt, err := utils.ParseEventTime(req.ExpiresAt, loc)
+		if err != nil {
+			return errors.New("invalid expires_at format: " + err.Error())
+		}
+		accessCode.ExpiresAt = &expiresAt
+	}
+	return nil
+}
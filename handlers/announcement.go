@@ -0,0 +1,379 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxAnnouncementLength = 5000
+
+type AnnouncementRequest struct {
+	Title             string   `json:"title"`
+	Message           string   `json:"message"`
+	TicketCategoryIDs []string `json:"ticket_category_ids"` // kosong = semua pemegang tiket
+	Notify            *bool    `json:"notify"`              // default true saat membuat, false saat mengedit
+}
+
+type announcementResponse struct {
+	AnnouncementID string    `json:"announcement_id"`
+	Title          string    `json:"title"`
+	Message        string    `json:"message"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// announcementRecipientIDs mengembalikan pemegang tiket aktif, opsional dibatasi ticket category
+func announcementRecipientIDs(db *gorm.DB, announcement models.EventAnnouncement) ([]string, error) {
+	query := db.Model(&models.Ticket{}).
+		Where("event_id = ? AND status = ?", announcement.EventID, "active")
+	if len(announcement.TicketCategories) > 0 {
+		categoryIDs := make([]string, 0, len(announcement.TicketCategories))
+		for _, tc := range announcement.TicketCategories {
+			categoryIDs = append(categoryIDs, tc.TicketCategoryID)
+		}
+		query = query.Where("ticket_category_id IN ?", categoryIDs)
+	}
+
+	var ownerIDs []string
+	err := query.Distinct().Pluck("owner_id", &ownerIDs).Error
+	return ownerIDs, err
+}
+
+// deliverAnnouncement mengirim notifikasi ke penerima dan mencatat jumlahnya
+func deliverAnnouncement(db *gorm.DB, announcement *models.EventAnnouncement, event models.Event) error {
+	recipients, err := announcementRecipientIDs(db, *announcement)
+	if err != nil {
+		return err
+	}
+
+	notifyUsers(db, recipients, "event_announcement", event.Name+": "+announcement.Title, announcement.Message, event.EventID)
+
+	now := time.Now()
+	announcement.RecipientCount = uint(len(recipients))
+	announcement.NotifiedAt = &now
+	return db.Model(announcement).UpdateColumns(map[string]interface{}{
+		"recipient_count": announcement.RecipientCount,
+		"notified_at":     now,
+	}).Error
+}
+
+// ticketAnnouncements memuat pengumuman untuk sekumpulan event, dikelompokkan per event
+func ticketAnnouncements(db *gorm.DB, eventIDs []string) (map[string][]models.EventAnnouncement, error) {
+	grouped := map[string][]models.EventAnnouncement{}
+	if len(eventIDs) == 0 {
+		return grouped, nil
+	}
+
+	var announcements []models.EventAnnouncement
+	if err := db.Preload("TicketCategories").
+		Where("event_id IN ?", eventIDs).
+		Order("created_at DESC").
+		Find(&announcements).Error; err != nil {
+		return nil, err
+	}
+	for _, announcement := range announcements {
+		grouped[announcement.EventID] = append(grouped[announcement.EventID], announcement)
+	}
+	return grouped, nil
+}
+
+// announcementsForCategory menyaring pengumuman yang berlaku untuk satu ticket category
+func announcementsForCategory(announcements []models.EventAnnouncement, ticketCategoryID string) []announcementResponse {
+	result := []announcementResponse{}
+	for _, announcement := range announcements {
+		applies := len(announcement.TicketCategories) == 0
+		for _, tc := range announcement.TicketCategories {
+			if tc.TicketCategoryID == ticketCategoryID {
+				applies = true
+				break
+			}
+		}
+		if !applies {
+			continue
+		}
+		result = append(result, announcementResponse{
+			AnnouncementID: announcement.AnnouncementID,
+			Title:          announcement.Title,
+			Message:        announcement.Message,
+			CreatedAt:      announcement.CreatedAt,
+			UpdatedAt:      announcement.UpdatedAt,
+		})
+	}
+	return result
+}
+
+func validateAnnouncementRequest(req *AnnouncementRequest) string {
+	req.Title = strings.TrimSpace(req.Title)
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Title == "" || req.Message == "" {
+		return "title and message are required"
+	}
+	if len(req.Title) > 150 {
+		return "title must be at most 150 characters"
+	}
+	if len(req.Message) > maxAnnouncementLength {
+		return "message must be at most 5000 characters"
+	}
+	return ""
+}
+
+// loadAnnouncementCategories memastikan semua ticket category milik event
+func loadAnnouncementCategories(db *gorm.DB, eventID string, ids []string) ([]models.TicketCategory, bool) {
+	if len(ids) == 0 {
+		return nil, true
+	}
+	var ticketCategories []models.TicketCategory
+	if err := db.Where("event_id = ? AND ticket_category_id IN ?", eventID, ids).
+		Find(&ticketCategories).Error; err != nil || len(ticketCategories) != len(ids) {
+		return nil, false
+	}
+	return ticketCategories, true
+}
+
+// CreateAnnouncement - Organizer memposting pengumuman dan mengirimkannya ke pemegang tiket aktif
+func CreateAnnouncement(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", c.Params("id")).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if !canAccessEvent(config.DB, user, event, permEventManage) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to post announcements for this event",
+		})
+	}
+
+	var req AnnouncementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if msg := validateAnnouncementRequest(&req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	ticketCategories, ok := loadAnnouncementCategories(config.DB, event.EventID, req.TicketCategoryIDs)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "One or more ticket categories not found in this event",
+		})
+	}
+
+	announcement := models.EventAnnouncement{
+		AnnouncementID:   utils.GenerateAnnouncementID(),
+		EventID:          event.EventID,
+		AuthorID:         user.UserID,
+		Title:            req.Title,
+		Message:          req.Message,
+		TicketCategories: ticketCategories,
+	}
+	if err := config.DB.Create(&announcement).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create announcement",
+		})
+	}
+
+	if req.Notify == nil || *req.Notify {
+		if err := deliverAnnouncement(config.DB, &announcement, event); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Announcement saved but failed to notify ticket holders",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":      "Announcement posted successfully",
+		"announcement": announcement,
+	})
+}
+
+// GetAnnouncements - Semua pengumuman event untuk tim organizer
+func GetAnnouncements(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", c.Params("id")).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if !canAccessEvent(config.DB, user, event, permEventView) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to view announcements for this event",
+		})
+	}
+
+	var announcements []models.EventAnnouncement
+	if err := config.DB.Preload("TicketCategories").
+		Where("event_id = ?", event.EventID).
+		Order("created_at DESC").
+		Find(&announcements).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch announcements",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":       "Announcements retrieved successfully",
+		"announcements": announcements,
+	})
+}
+
+// UpdateAnnouncement - Edit pengumuman. Pemegang tiket hanya dinotifikasi ulang jika notify=true.
+func UpdateAnnouncement(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", c.Params("id")).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if !canAccessEvent(config.DB, user, event, permEventManage) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to update announcements for this event",
+		})
+	}
+
+	var announcement models.EventAnnouncement
+	if err := config.DB.Where("announcement_id = ? AND event_id = ?", c.Params("announcement_id"), event.EventID).
+		First(&announcement).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Announcement not found",
+		})
+	}
+
+	var req AnnouncementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if msg := validateAnnouncementRequest(&req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	ticketCategories, ok := loadAnnouncementCategories(config.DB, event.EventID, req.TicketCategoryIDs)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "One or more ticket categories not found in this event",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	if err := tx.Model(&announcement).Updates(map[string]interface{}{
+		"title":   req.Title,
+		"message": req.Message,
+	}).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update announcement",
+		})
+	}
+	if err := tx.Model(&announcement).Association("TicketCategories").Replace(ticketCategories); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update announcement categories",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+	announcement.TicketCategories = ticketCategories
+
+	if req.Notify != nil && *req.Notify {
+		if err := deliverAnnouncement(config.DB, &announcement, event); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Announcement updated but failed to notify ticket holders",
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message":      "Announcement updated successfully",
+		"announcement": announcement,
+	})
+}
+
+// DeleteAnnouncement - Menghapus pengumuman dari event dan tampilan tiket
+func DeleteAnnouncement(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", c.Params("id")).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if !canAccessEvent(config.DB, user, event, permEventManage) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to delete announcements for this event",
+		})
+	}
+
+	var announcement models.EventAnnouncement
+	if err := config.DB.Where("announcement_id = ? AND event_id = ?", c.Params("announcement_id"), event.EventID).
+		First(&announcement).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Announcement not found",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	if err := tx.Model(&announcement).Association("TicketCategories").Clear(); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete announcement categories",
+		})
+	}
+	if err := tx.Delete(&announcement).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete announcement",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Announcement deleted successfully",
+	})
+}
@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+const attendeeFormatJSON = "json"
+
+// attendeeTicketStatuses adalah status tiket yang dianggap peserta (sudah dibayar/diklaim)
+var attendeeTicketStatuses = []string{"active", "used"}
+
+var attendeeColumns = []string{
+	"ticket_id", "ticket_code", "ticket_category", "tag", "is_complimentary", "status",
+	"holder_name", "holder_email", "purchased_at", "checked_in", "checked_in_at",
+}
+
+// AttendeeRecord adalah satu tiket peserta pada ekspor
+type AttendeeRecord struct {
+	TicketID         string `json:"ticket_id"`
+	TicketCode       string `json:"ticket_code"`
+	TicketCategoryID string `json:"ticket_category_id"`
+	TicketCategory   string `json:"ticket_category"`
+	Tag              string `json:"tag"`
+	IsComplimentary  bool   `json:"is_complimentary"`
+	Status           string `json:"status"`
+	HolderName       string `json:"holder_name"`
+	HolderEmail      string `json:"holder_email"`
+	PurchasedAt      string `json:"purchased_at"`
+	CheckedIn        bool   `json:"checked_in"`
+	CheckedInAt      string `json:"checked_in_at,omitempty"`
+
+	Answers []AttendeeAnswer `json:"answers"`
+}
+
+// AttendeeAnswer adalah jawaban pendaftaran pemegang tiket pada ekspor
+type AttendeeAnswer struct {
+	QuestionID string `json:"question_id"`
+	Label      string `json:"label"`
+	Value      string `json:"value"`
+}
+
+// row membentuk satu baris CSV/XLSX; jawaban pendaftaran mengikuti urutan questions
+func (r AttendeeRecord) row(questions []models.RegistrationQuestion) []string {
+	values := []string{
+		r.TicketID, r.TicketCode, r.TicketCategory, r.Tag, strconv.FormatBool(r.IsComplimentary), r.Status,
+		r.HolderName, r.HolderEmail, r.PurchasedAt, strconv.FormatBool(r.CheckedIn), r.CheckedInAt,
+	}
+	answers := make(map[string]string, len(r.Answers))
+	for _, answer := range r.Answers {
+		answers[answer.QuestionID] = answer.Value
+	}
+	for _, question := range questions {
+		values = append(values, answers[question.QuestionID])
+	}
+	return values
+}
+
+func attendeeHeader(questions []models.RegistrationQuestion) []string {
+	header := append([]string{}, attendeeColumns...)
+	for _, question := range questions {
+		header = append(header, question.Label)
+	}
+	return header
+}
+
+// spreadsheetSafe mencegah formula injection di CSV: nilai yang diawali karakter formula diberi
+// awalan ' supaya dibaca sebagai teks oleh Excel/Sheets. XLSX tidak perlu karena sel ditulis
+// sebagai string.
+func spreadsheetSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func csvSafeRow(values []string) []string {
+	for i, value := range values {
+		values[i] = spreadsheetSafe(value)
+	}
+	return values
+}
+
+func writeAttendeesCSV(w io.Writer, questions []models.RegistrationQuestion, records []AttendeeRecord) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvSafeRow(attendeeHeader(questions))); err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := writer.Write(csvSafeRow(record.row(questions))); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeAttendeesXLSX(w io.Writer, questions []models.RegistrationQuestion, records []AttendeeRecord) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	const sheet = "Peserta"
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		return err
+	}
+	columns := attendeeHeader(questions)
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	if err := f.SetSheetRow(sheet, "A1", &header); err != nil {
+		return err
+	}
+	headerStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	lastColumn, _ := excelize.ColumnNumberToName(len(columns))
+	f.SetCellStyle(sheet, "A1", lastColumn+"1", headerStyle)
+
+	for i, record := range records {
+		values := make([]interface{}, 0, len(columns))
+		for _, value := range record.row(questions) {
+			values = append(values, value)
+		}
+		if err := f.SetSheetRow(sheet, fmt.Sprintf("A%d", i+2), &values); err != nil {
+			return err
+		}
+	}
+	f.SetColWidth(sheet, "A", lastColumn, 20)
+	f.SetPanes(sheet, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"})
+	if len(records) > 0 {
+		f.AutoFilter(sheet, fmt.Sprintf("A1:%s%d", lastColumn, len(records)+1), nil)
+	}
+
+	_, err = f.WriteTo(w)
+	return err
+}
+
+// ExportEventAttendees - Ekspor daftar peserta event (CSV, XLSX, atau JSON) beserta jawaban pendaftaran.
+// Query: format=csv|xlsx|json, ticket_category_id (boleh dipisah koma), checked_in=true|false.
+// Setiap ekspor dicatat di audit log karena berisi data pribadi pemegang tiket.
+func ExportEventAttendees(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var event models.Event
+	if err := config.DB.Preload("TicketCategories").Where("event_id = ?", c.Params("id")).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+	if !canAccessEvent(config.DB, user, event, permAttendeeExport) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to export attendees of this event",
+		})
+	}
+
+	format := strings.ToLower(c.Query("format", reportFormatCSV))
+	if format != reportFormatCSV && format != reportFormatXLSX && format != attendeeFormatJSON {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "format must be one of 'csv', 'xlsx' or 'json'",
+		})
+	}
+
+	categoryNames := make(map[string]string)
+	for _, tc := range event.TicketCategories {
+		categoryNames[tc.TicketCategoryID] = tc.Name
+	}
+
+	query := config.DB.Preload("Owner").
+		Where("event_id = ? AND status IN ?", event.EventID, attendeeTicketStatuses)
+
+	filters := fiber.Map{}
+	var categoryIDs []string
+	for _, id := range strings.Split(c.Query("ticket_category_id"), ",") {
+		if id = strings.TrimSpace(id); id == "" {
+			continue
+		}
+		if _, ok := categoryNames[id]; !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Ticket category not found in this event: " + id,
+			})
+		}
+		categoryIDs = append(categoryIDs, id)
+	}
+	if len(categoryIDs) > 0 {
+		query = query.Where("ticket_category_id IN ?", categoryIDs)
+		filters["ticket_category_id"] = categoryIDs
+	}
+	if value := c.Query("checked_in"); value != "" {
+		checkedIn, err := strconv.ParseBool(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "checked_in must be true or false",
+			})
+		}
+		if checkedIn {
+			query = query.Where("status = ?", "used")
+		} else {
+			query = query.Where("status <> ?", "used")
+		}
+		filters["checked_in"] = checkedIn
+	}
+
+	var tickets []models.Ticket
+	if err := query.Order("ticket_category_id ASC, created_at ASC").Find(&tickets).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch attendees",
+		})
+	}
+
+	questions, err := loadRegistrationQuestions(config.DB, event.EventID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch registration questions",
+		})
+	}
+	labels := make(map[string]string, len(questions))
+	for _, question := range questions {
+		labels[question.QuestionID] = question.Label
+	}
+	answersByTicket := make(map[string][]AttendeeAnswer)
+	if len(questions) > 0 && len(tickets) > 0 {
+		ticketIDs := make([]string, 0, len(tickets))
+		for _, ticket := range tickets {
+			ticketIDs = append(ticketIDs, ticket.TicketID)
+		}
+		var answers []models.RegistrationAnswer
+		if err := config.DB.Where("ticket_id IN ?", ticketIDs).Find(&answers).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch registration answers",
+			})
+		}
+		for _, answer := range answers {
+			label, ok := labels[answer.QuestionID]
+			if !ok {
+				continue
+			}
+			answersByTicket[answer.TicketID] = append(answersByTicket[answer.TicketID], AttendeeAnswer{
+				QuestionID: answer.QuestionID,
+				Label:      label,
+				Value:      answer.Value,
+			})
+		}
+	}
+
+	loc := utils.TimeZoneOrDefault(event.TimeZone)
+	records := make([]AttendeeRecord, 0, len(tickets))
+	for _, ticket := range tickets {
+		record := AttendeeRecord{
+			TicketID:         ticket.TicketID,
+			TicketCode:       ticket.Code,
+			TicketCategoryID: ticket.TicketCategoryID,
+			TicketCategory:   categoryNames[ticket.TicketCategoryID],
+			Tag:              ticket.Tag,
+			IsComplimentary:  ticket.IsComplimentary,
+			Status:           ticket.Status,
+			HolderName:       ticket.Owner.Name,
+			HolderEmail:      ticket.Owner.Email,
+			PurchasedAt:      utils.FormatLocal(ticket.CreatedAt, loc),
+			CheckedIn:        ticket.Status == "used",
+			Answers:          answersByTicket[ticket.TicketID],
+		}
+		if record.Answers == nil {
+			record.Answers = []AttendeeAnswer{}
+		}
+		if record.CheckedIn {
+			// Check-in sebelum kolom checked_in_at ada memakai updated_at
+			checkedInAt := ticket.UpdatedAt
+			if ticket.CheckedInAt != nil {
+				checkedInAt = *ticket.CheckedInAt
+			}
+			record.CheckedInAt = utils.FormatLocal(checkedInAt, loc)
+		}
+		records = append(records, record)
+	}
+
+	// Ekspor tidak dikirim jika tidak bisa dicatat
+	if err := recordAudit(config.DB, c, user.UserID, "event.attendees_export", "event", event.EventID, fiber.Map{
+		"format":  format,
+		"filters": filters,
+		"count":   len(records),
+	}); err != nil {
+		log.Printf("Failed to record attendee export audit log: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record export",
+		})
+	}
+
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", eventExportFilename("peserta", event, format)))
+	c.Set("Cache-Control", "no-store")
+	if format == attendeeFormatJSON {
+		return c.JSON(fiber.Map{
+			"event_id":     event.EventID,
+			"generated_at": utils.FormatLocal(time.Now(), loc),
+			"total":        len(records),
+			"questions":    questions,
+			"attendees":    records,
+		})
+	}
+
+	var buf bytes.Buffer
+	if format == reportFormatXLSX {
+		err = writeAttendeesXLSX(&buf, questions, records)
+	} else {
+		err = writeAttendeesCSV(&buf, questions, records)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate export",
+		})
+	}
+	c.Set("Content-Type", reportContentTypes[format])
+	return c.Send(buf.Bytes())
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// recordAudit menyimpan audit log. Dipanggil di dalam transaksi yang sama dengan aksinya
+// supaya aksi tanpa audit log tidak pernah ter-commit.
+func recordAudit(db *gorm.DB, c *fiber.Ctx, actorID, action, entityType, entityID string, details interface{}) error {
+	data, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+
+	entry := models.AuditLog{
+		AuditLogID: utils.GenerateAuditLogID(),
+		ActorID:    actorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Details:    string(data),
+		CreatedAt:  time.Now(),
+	}
+	if c != nil {
+		entry.IPAddress = c.IP()
+	}
+
+	return db.Create(&entry).Error
+}
+
+// GetAuditLogs - Daftar audit log untuk admin. Filter: entity_type, entity_id, actor_id, action, limit
+func GetAuditLogs(c *fiber.Ctx) error {
+	query := config.DB.Model(&models.AuditLog{})
+
+	for param, column := range map[string]string{
+		"entity_type": "entity_type",
+		"entity_id":   "entity_id",
+		"actor_id":    "actor_id",
+		"action":      "action",
+	} {
+		if value := c.Query(param); value != "" {
+			query = query.Where(column+" = ?", value)
+		}
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil || limit < 1 || limit > 500 {
+		limit = 100
+	}
+
+	var logs []models.AuditLog
+	if err := query.Order("created_at DESC").Limit(limit).Find(&logs).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch audit logs",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":    "Audit logs retrieved successfully",
+		"audit_logs": logs,
+	})
+}
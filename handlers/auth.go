@@ -136,12 +136,48 @@ func Register(c *fiber.Ctx) error {
 		UpdatedAt:               time.Now(),
 	}
 
-	if err := config.DB.Create(&user).Error; err != nil {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	if err := tx.Create(&user).Error; err != nil {
+		tx.Rollback()
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create user",
 		})
 	}
 
+	// Organizer baru masuk antrian moderasi admin
+	if user.Role == "organizer" {
+		if err := enqueueModeration(tx, moderationEntityOrganizer, user.UserID, user.UserID); err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to queue organizer for review",
+			})
+		}
+	}
+
+	// Klaim tiket komplimen yang sudah dikirim ke email ini sebelum akun dibuat, dalam transaksi
+	// yang sama supaya tidak ada claim yang setengah ter-redeem
+	redeemed, skipped, err := redeemCompClaims(tx, user)
+	if err != nil {
+		tx.Rollback()
+		log.Printf("Failed to redeem complimentary claims for %s: %v", user.Email, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to redeem complimentary tickets",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"message": "User registered successfully",
 		"user": fiber.Map{
@@ -152,6 +188,10 @@ func Register(c *fiber.Ctx) error {
 			"role":            user.Role,
 			"register_status": user.RegisterStatus,
 		},
+		"complimentary": fiber.Map{
+			"redeemed": redeemed,
+			"skipped":  skipped,
+		},
 	})
 }
 
@@ -28,6 +28,7 @@ func AddToCart(c *fiber.Ctx) error {
 	var cartData struct {
 		TicketCategoryID string `json:"ticket_category_id"`
 		Quantity         uint   `json:"quantity"`
+		AccessCode       string `json:"access_code"`
 	}
 
 	if err := c.BodyParser(&cartData); err != nil {
@@ -55,6 +56,24 @@ func AddToCart(c *fiber.Ctx) error {
 		})
 	}
 
+	if err := checkSaleWindow(config.DB, ticketCategory); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// Category hidden hanya bisa dibeli dengan kode akses yang valid
+	var accessCodeID string
+	if ticketCategory.IsHidden {
+		accessCode, err := resolveAccessCode(config.DB, ticketCategory, cartData.AccessCode)
+		if err != nil {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Access code rejected: " + err.Error(),
+			})
+		}
+		accessCodeID = accessCode.AccessCodeID
+	}
+
 	// Cek apakah item dengan ticket category yang sama sudah ada di cart user
 	var existingCart models.Cart
 	err := config.DB.
@@ -78,6 +97,9 @@ func AddToCart(c *fiber.Ctx) error {
 		existingCart.Quantity = newQuantity
 		existingCart.PriceTotal = newPriceTotal
 		existingCart.UpdatedAt = time.Now()
+		if accessCodeID != "" {
+			existingCart.AccessCodeID = accessCodeID
+		}
 
 		if err := config.DB.Save(&existingCart).Error; err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -152,6 +174,7 @@ func AddToCart(c *fiber.Ctx) error {
 		CartID:           utils.GenerateCartID(),
 		TicketCategoryID: ticketCategory.TicketCategoryID,
 		OwnerID:          user.UserID,
+		AccessCodeID:     accessCodeID,
 		Quantity:         cartData.Quantity,
 		PriceTotal:       priceTotal,
 		CreatedAt:        time.Now(),
@@ -381,6 +404,13 @@ func UpdateCart(c *fiber.Ctx) error {
 		})
 	}
 
+	// Kode akses untuk category hidden bisa saja sudah habis / dinonaktifkan sejak item dimasukkan
+	if _, err := resolveAccessCodeByID(config.DB, ticketCategory, cart.AccessCodeID); err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access code rejected: " + err.Error(),
+		})
+	}
+
 	// Cek ketersediaan kuota
 	availableQuota := ticketCategory.Quota - ticketCategory.Sold
 	if updateData.Quantity > availableQuota {
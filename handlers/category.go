@@ -0,0 +1,1029 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+var errInvalidEventCategory = errors.New("invalid event category")
+
+type EventCategoryRequest struct {
+	Name      string `json:"name"`
+	Icon      string `json:"icon"`
+	SortOrder *int   `json:"sort_order"`
+	IsActive  *bool  `json:"is_active"`
+	ParentID  string `json:"parent_id"` // hanya untuk sub kategori: pindah ke kategori induk lain
+}
+
+type EventCategoryOrderRequest struct {
+	ParentID string   `json:"parent_id"` // kosong = urutan kategori utama
+	IDs      []string `json:"ids"`
+}
+
+// childCategoryOrder mengurutkan sub kategori sesuai sort_order lalu nama
+func childCategoryOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("sort_order ASC").Order("child_event_category_name ASC")
+}
+
+// validateEventCategoryRequest merapikan nama dan ikon kategori
+func validateEventCategoryRequest(req *EventCategoryRequest, requireName bool) error {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Icon = strings.TrimSpace(req.Icon)
+	if requireName && req.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(req.Name) > 50 {
+		return errors.New("name must be at most 50 characters")
+	}
+	if len(req.Icon) > 255 {
+		return errors.New("icon must be at most 255 characters")
+	}
+	return nil
+}
+
+// categoryNameTaken mengecek nama kategori (case-insensitive) di level yang sama
+func categoryNameTaken(db *gorm.DB, name, parentID, excludeID string) (bool, error) {
+	var count int64
+	var err error
+	if parentID == "" {
+		err = db.Model(&models.EventCategory{}).
+			Where("LOWER(event_category_name) = ? AND event_category_id <> ?", strings.ToLower(name), excludeID).
+			Count(&count).Error
+	} else {
+		err = db.Model(&models.ChildEventCategory{}).
+			Where("parent_category_id = ? AND LOWER(child_event_category_name) = ? AND child_event_category_id <> ?", parentID, strings.ToLower(name), excludeID).
+			Count(&count).Error
+	}
+	return count > 0, err
+}
+
+// findEventCategory mencari kategori utama berdasarkan ID atau nama (case-insensitive)
+func findEventCategory(db *gorm.DB, value string) (*models.EventCategory, error) {
+	var category models.EventCategory
+	err := db.Where("event_category_id = ? OR LOWER(event_category_name) = ?", value, strings.ToLower(value)).
+		First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: category %q not found", errInvalidEventCategory, value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// findChildEventCategory mencari sub kategori berdasarkan ID atau nama. Jika parentID kosong,
+// nama sub kategori harus unik di semua kategori.
+func findChildEventCategory(db *gorm.DB, value, parentID string) (*models.ChildEventCategory, error) {
+	query := db.Where("(child_event_category_id = ? OR LOWER(child_event_category_name) = ?)", value, strings.ToLower(value))
+	if parentID != "" {
+		query = query.Where("parent_category_id = ?", parentID)
+	}
+
+	var children []models.ChildEventCategory
+	if err := query.Limit(2).Find(&children).Error; err != nil {
+		return nil, err
+	}
+	switch len(children) {
+	case 0:
+		if parentID != "" {
+			return nil, fmt.Errorf("%w: child category %q does not belong to the selected category", errInvalidEventCategory, value)
+		}
+		return nil, fmt.Errorf("%w: child category %q not found", errInvalidEventCategory, value)
+	case 1:
+		return &children[0], nil
+	default:
+		return nil, fmt.Errorf("%w: child category %q is ambiguous, select the category as well", errInvalidEventCategory, value)
+	}
+}
+
+// resolveEventCategory memvalidasi kategori pilihan event. Nilai boleh berupa ID atau nama
+// (kompatibel dengan client lama). Kategori nonaktif ditolak kecuali sudah dipakai oleh current.
+// Jika hanya sub kategori yang diisi, kategori induk diambil dari sub kategori tersebut.
+func resolveEventCategory(db *gorm.DB, category, childCategory string, current *models.Event) (*models.EventCategory, *models.ChildEventCategory, error) {
+	category, childCategory = strings.TrimSpace(category), strings.TrimSpace(childCategory)
+	if category == "" && childCategory == "" {
+		return nil, nil, nil
+	}
+
+	var parent *models.EventCategory
+	var child *models.ChildEventCategory
+	var err error
+	if category != "" {
+		if parent, err = findEventCategory(db, category); err != nil {
+			return nil, nil, err
+		}
+	}
+	if childCategory != "" {
+		parentID := ""
+		if parent != nil {
+			parentID = parent.EventCategoryID
+		}
+		if child, err = findChildEventCategory(db, childCategory, parentID); err != nil {
+			return nil, nil, err
+		}
+		if parent == nil {
+			if parent, err = findEventCategory(db, child.ParentCategoryID); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	var currentCategoryID, currentChildID *string
+	if current != nil {
+		currentCategoryID, currentChildID = current.CategoryID, current.ChildCategoryID
+	}
+	inUse := func(id string, currentID *string) bool {
+		return currentID != nil && *currentID == id
+	}
+	if !parent.IsActive && !inUse(parent.EventCategoryID, currentCategoryID) {
+		return nil, nil, fmt.Errorf("%w: category %q is inactive", errInvalidEventCategory, parent.EventCategoryName)
+	}
+	if child != nil && !child.IsActive && !inUse(child.ChildEventCategoryID, currentChildID) {
+		return nil, nil, fmt.Errorf("%w: child category %q is inactive", errInvalidEventCategory, child.ChildEventCategoryName)
+	}
+	return parent, child, nil
+}
+
+// resolveEventUpdateCategory menggabungkan input edit dengan kategori event saat ini. Jika hanya
+// sub kategori yang diisi, kategori induk tetap; sub kategori lama dipertahankan selama induknya sama.
+func resolveEventUpdateCategory(db *gorm.DB, category, childCategory string, event models.Event) (*models.EventCategory, *models.ChildEventCategory, error) {
+	if category == "" && event.CategoryID != nil {
+		category = *event.CategoryID
+	}
+	parent, child, err := resolveEventCategory(db, category, childCategory, &event)
+	if err != nil || parent == nil {
+		return parent, child, err
+	}
+	if child == nil && childCategory == "" && event.ChildCategoryID != nil &&
+		event.CategoryID != nil && *event.CategoryID == parent.EventCategoryID {
+		if kept, err := findChildEventCategory(db, *event.ChildCategoryID, parent.EventCategoryID); err == nil {
+			child = kept
+		}
+	}
+	return parent, child, nil
+}
+
+// eventCategoryColumns mengisi kolom FK kategori beserta salinan namanya
+func eventCategoryColumns(parent *models.EventCategory, child *models.ChildEventCategory) map[string]interface{} {
+	columns := map[string]interface{}{
+		"category_id":       nil,
+		"category":          "",
+		"child_category_id": nil,
+		"child_category":    "",
+	}
+	if parent != nil {
+		columns["category_id"] = parent.EventCategoryID
+		columns["category"] = parent.EventCategoryName
+	}
+	if child != nil {
+		columns["child_category_id"] = child.ChildEventCategoryID
+		columns["child_category"] = child.ChildEventCategoryName
+	}
+	return columns
+}
+
+// applyEventCategory menyalin hasil resolveEventCategory ke struct event
+func applyEventCategory(event *models.Event, parent *models.EventCategory, child *models.ChildEventCategory) {
+	event.CategoryID, event.Category = nil, ""
+	event.ChildCategoryID, event.ChildCategory = nil, ""
+	if parent != nil {
+		event.CategoryID, event.Category = &parent.EventCategoryID, parent.EventCategoryName
+	}
+	if child != nil {
+		event.ChildCategoryID, event.ChildCategory = &child.ChildEventCategoryID, child.ChildEventCategoryName
+	}
+}
+
+// GetEventCategories - Daftar kategori aktif beserta sub kategorinya, sesuai urutan admin
+func GetEventCategories(c *fiber.Ctx) error {
+	var allCategory []models.EventCategory
+	if err := config.DB.Preload("ChildEventCategory", func(db *gorm.DB) *gorm.DB {
+		return childCategoryOrder(db.Where("is_active = ?", true))
+	}).
+		Where("is_active = ?", true).
+		Order("sort_order ASC").Order("event_category_name ASC").
+		Find(&allCategory).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch event categories",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"event_category": allCategory,
+	})
+}
+
+// GetAllEventCategories - Admin: semua kategori termasuk yang nonaktif, dengan jumlah event per kategori
+func GetAllEventCategories(c *fiber.Ctx) error {
+	var allCategory []models.EventCategory
+	if err := config.DB.Preload("ChildEventCategory", childCategoryOrder).
+		Order("sort_order ASC").Order("event_category_name ASC").
+		Find(&allCategory).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch event categories",
+		})
+	}
+
+	type usageRow struct {
+		ID    string
+		Total int64
+	}
+	var categoryUsage, childUsage []usageRow
+	config.DB.Model(&models.Event{}).Select("category_id AS id, COUNT(*) AS total").
+		Where("category_id IS NOT NULL").Group("category_id").Scan(&categoryUsage)
+	config.DB.Model(&models.Event{}).Select("child_category_id AS id, COUNT(*) AS total").
+		Where("child_category_id IS NOT NULL").Group("child_category_id").Scan(&childUsage)
+
+	eventCounts := make(map[string]int64)
+	for _, row := range append(categoryUsage, childUsage...) {
+		eventCounts[row.ID] = row.Total
+	}
+
+	return c.JSON(fiber.Map{
+		"event_category": allCategory,
+		"event_counts":   eventCounts,
+	})
+}
+
+// CreateEventCategory - Admin menambahkan kategori utama
+func CreateEventCategory(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req EventCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if err := validateEventCategoryRequest(&req, true); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if taken, err := categoryNameTaken(config.DB, req.Name, "", ""); err != nil || taken {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Category name already exists",
+		})
+	}
+
+	category := models.EventCategory{
+		EventCategoryID:   utils.GenerateEventCategoryID(),
+		EventCategoryName: req.Name,
+		Icon:              req.Icon,
+		IsActive:          true,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+	if req.SortOrder != nil {
+		category.SortOrder = *req.SortOrder
+	} else {
+		var maxOrder int
+		config.DB.Model(&models.EventCategory{}).Select("COALESCE(MAX(sort_order), 0)").Scan(&maxOrder)
+		category.SortOrder = maxOrder + 1
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	if err := tx.Create(&category).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to add event category",
+		})
+	}
+	// Kolom is_active punya default true, jadi status nonaktif disimpan setelah insert
+	if req.IsActive != nil && !*req.IsActive {
+		category.IsActive = false
+		if err := tx.Model(&category).UpdateColumn("is_active", false).Error; err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to add event category",
+			})
+		}
+	}
+	if err := recordAudit(tx, c, user.UserID, "category.create", "event_category", category.EventCategoryID, req); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":        "Event category created successfully",
+		"event_category": category,
+	})
+}
+
+// UpdateEventCategory - Admin mengganti nama, ikon, urutan, atau status aktif kategori utama.
+// Nama baru ikut disalin ke event, series, dan sub kategorinya.
+func UpdateEventCategory(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req EventCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if err := validateEventCategoryRequest(&req, false); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var category models.EventCategory
+	if err := config.DB.Where("event_category_id = ?", c.Params("id")).First(&category).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event category not found",
+		})
+	}
+
+	renamed := req.Name != "" && req.Name != category.EventCategoryName
+	if renamed {
+		if taken, err := categoryNameTaken(config.DB, req.Name, "", category.EventCategoryID); err != nil || taken {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Category name already exists",
+			})
+		}
+	}
+
+	updateData := map[string]interface{}{"updated_at": time.Now()}
+	if renamed {
+		updateData["event_category_name"] = req.Name
+	}
+	if req.Icon != "" {
+		updateData["icon"] = req.Icon
+	}
+	if req.SortOrder != nil {
+		updateData["sort_order"] = *req.SortOrder
+	}
+	if req.IsActive != nil {
+		updateData["is_active"] = *req.IsActive
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	if err := tx.Model(&category).Updates(updateData).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update event category",
+		})
+	}
+	if renamed {
+		if err := tx.Model(&models.ChildEventCategory{}).Where("parent_category_id = ?", category.EventCategoryID).
+			UpdateColumn("parent_category_name", req.Name).Error; err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update child categories",
+			})
+		}
+		if err := tx.Model(&models.Event{}).Where("category_id = ?", category.EventCategoryID).
+			UpdateColumn("category", req.Name).Error; err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update events in this category",
+			})
+		}
+		if err := tx.Model(&models.EventSeries{}).Where("category_id = ?", category.EventCategoryID).
+			UpdateColumn("category", req.Name).Error; err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update series in this category",
+			})
+		}
+	}
+	if err := recordAudit(tx, c, user.UserID, "category.update", "event_category", category.EventCategoryID, fiber.Map{
+		"previous_name": category.EventCategoryName,
+		"changes":       req,
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	config.DB.Preload("ChildEventCategory", childCategoryOrder).Where("event_category_id = ?", category.EventCategoryID).First(&category)
+
+	return c.JSON(fiber.Map{
+		"message":        "Event category updated successfully",
+		"event_category": category,
+	})
+}
+
+// DeleteEventCategory - Admin menghapus kategori utama yang tidak punya sub kategori dan tidak dipakai event.
+// Kategori yang sudah dipakai cukup dinonaktifkan.
+func DeleteEventCategory(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var category models.EventCategory
+	if err := config.DB.Preload("ChildEventCategory").Where("event_category_id = ?", c.Params("id")).First(&category).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event category not found",
+		})
+	}
+	if len(category.ChildEventCategory) > 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "You have to delete the child categories first",
+		})
+	}
+
+	var eventCount, seriesCount int64
+	config.DB.Model(&models.Event{}).Where("category_id = ?", category.EventCategoryID).Count(&eventCount)
+	config.DB.Model(&models.EventSeries{}).Where("category_id = ?", category.EventCategoryID).Count(&seriesCount)
+	if eventCount > 0 || seriesCount > 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Category is used by events. Deactivate it instead",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	if err := tx.Delete(&category).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete category",
+		})
+	}
+	if err := recordAudit(tx, c, user.UserID, "category.delete", "event_category", category.EventCategoryID, fiber.Map{
+		"name": category.EventCategoryName,
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Event category deleted successfully",
+	})
+}
+
+// CreateChildEventCategory - Admin menambahkan sub kategori ke kategori :id
+func CreateChildEventCategory(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req EventCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if err := validateEventCategoryRequest(&req, true); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var parent models.EventCategory
+	if err := config.DB.Where("event_category_id = ?", c.Params("id")).First(&parent).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Main category not found",
+		})
+	}
+	if taken, err := categoryNameTaken(config.DB, req.Name, parent.EventCategoryID, ""); err != nil || taken {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Child category name already exists in this category",
+		})
+	}
+
+	child := models.ChildEventCategory{
+		ChildEventCategoryID:   utils.GenerateChildEventCategoryID(),
+		ParentCategoryID:       parent.EventCategoryID,
+		ParentCategoryName:     parent.EventCategoryName,
+		ChildEventCategoryName: req.Name,
+		Icon:                   req.Icon,
+		IsActive:               true,
+		CreatedAt:              time.Now(),
+		UpdatedAt:              time.Now(),
+	}
+	if req.SortOrder != nil {
+		child.SortOrder = *req.SortOrder
+	} else {
+		var maxOrder int
+		config.DB.Model(&models.ChildEventCategory{}).Where("parent_category_id = ?", parent.EventCategoryID).
+			Select("COALESCE(MAX(sort_order), 0)").Scan(&maxOrder)
+		child.SortOrder = maxOrder + 1
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	if err := tx.Create(&child).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to add child event category",
+		})
+	}
+	if req.IsActive != nil && !*req.IsActive {
+		child.IsActive = false
+		if err := tx.Model(&child).UpdateColumn("is_active", false).Error; err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to add child event category",
+			})
+		}
+	}
+	if err := recordAudit(tx, c, user.UserID, "category.child_create", "child_event_category", child.ChildEventCategoryID, req); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":              "Child event category created successfully",
+		"child_event_category": child,
+	})
+}
+
+// UpdateChildEventCategory - Admin mengganti nama, ikon, urutan, status aktif, atau kategori induk sub kategori
+func UpdateChildEventCategory(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req EventCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if err := validateEventCategoryRequest(&req, false); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var child models.ChildEventCategory
+	if err := config.DB.Where("child_event_category_id = ?", c.Params("id")).First(&child).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Child event category not found",
+		})
+	}
+
+	parent := models.EventCategory{EventCategoryID: child.ParentCategoryID, EventCategoryName: child.ParentCategoryName}
+	moved := req.ParentID != "" && req.ParentID != child.ParentCategoryID
+	if moved {
+		if err := config.DB.Where("event_category_id = ?", req.ParentID).First(&parent).Error; err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Main category not found",
+			})
+		}
+	}
+
+	name := firstNonEmpty(req.Name, child.ChildEventCategoryName)
+	renamed := name != child.ChildEventCategoryName
+	if renamed || moved {
+		if taken, err := categoryNameTaken(config.DB, name, parent.EventCategoryID, child.ChildEventCategoryID); err != nil || taken {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Child category name already exists in this category",
+			})
+		}
+	}
+
+	updateData := map[string]interface{}{"updated_at": time.Now()}
+	if renamed {
+		updateData["child_event_category_name"] = name
+	}
+	if moved {
+		updateData["parent_category_id"] = parent.EventCategoryID
+		updateData["parent_category_name"] = parent.EventCategoryName
+	}
+	if req.Icon != "" {
+		updateData["icon"] = req.Icon
+	}
+	if req.SortOrder != nil {
+		updateData["sort_order"] = *req.SortOrder
+	}
+	if req.IsActive != nil {
+		updateData["is_active"] = *req.IsActive
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	if err := tx.Model(&child).Updates(updateData).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update child event category",
+		})
+	}
+	// Event dan series yang memakai sub kategori ini mengikuti nama dan induk barunya
+	if renamed || moved {
+		for _, model := range []interface{}{&models.Event{}, &models.EventSeries{}} {
+			if err := tx.Model(model).Where("child_category_id = ?", child.ChildEventCategoryID).Updates(map[string]interface{}{
+				"child_category": name,
+				"category_id":    parent.EventCategoryID,
+				"category":       parent.EventCategoryName,
+			}).Error; err != nil {
+				tx.Rollback()
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "Failed to update events in this category",
+				})
+			}
+		}
+	}
+	if err := recordAudit(tx, c, user.UserID, "category.child_update", "child_event_category", child.ChildEventCategoryID, fiber.Map{
+		"previous_name":      child.ChildEventCategoryName,
+		"previous_parent_id": child.ParentCategoryID,
+		"changes":            req,
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	config.DB.Where("child_event_category_id = ?", child.ChildEventCategoryID).First(&child)
+
+	return c.JSON(fiber.Map{
+		"message":              "Child event category updated successfully",
+		"child_event_category": child,
+	})
+}
+
+// DeleteChildEventCategory - Admin menghapus sub kategori yang tidak dipakai event
+func DeleteChildEventCategory(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var child models.ChildEventCategory
+	if err := config.DB.Where("child_event_category_id = ?", c.Params("id")).First(&child).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Child event category not found",
+		})
+	}
+
+	var eventCount, seriesCount int64
+	config.DB.Model(&models.Event{}).Where("child_category_id = ?", child.ChildEventCategoryID).Count(&eventCount)
+	config.DB.Model(&models.EventSeries{}).Where("child_category_id = ?", child.ChildEventCategoryID).Count(&seriesCount)
+	if eventCount > 0 || seriesCount > 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Child category is used by events. Deactivate it instead",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	if err := tx.Delete(&child).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete category",
+		})
+	}
+	if err := recordAudit(tx, c, user.UserID, "category.child_delete", "child_event_category", child.ChildEventCategoryID, fiber.Map{
+		"name":      child.ChildEventCategoryName,
+		"parent_id": child.ParentCategoryID,
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Child event category deleted successfully",
+	})
+}
+
+// ReorderEventCategories - Admin menyusun ulang urutan kategori utama, atau sub kategori jika parent_id diisi.
+// ids harus berisi semua kategori di level tersebut.
+func ReorderEventCategories(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req EventCategoryOrderRequest
+	if err := c.BodyParser(&req); err != nil || len(req.IDs) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ids is required",
+		})
+	}
+
+	var existing []string
+	if req.ParentID == "" {
+		config.DB.Model(&models.EventCategory{}).Pluck("event_category_id", &existing)
+	} else {
+		config.DB.Model(&models.ChildEventCategory{}).Where("parent_category_id = ?", req.ParentID).Pluck("child_event_category_id", &existing)
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range req.IDs {
+		if seen[id] || !containsString(existing, id) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "ids contains an unknown or duplicate category: " + id,
+			})
+		}
+		seen[id] = true
+	}
+	if len(req.IDs) != len(existing) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ids must list every category at this level",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	for i, id := range req.IDs {
+		var err error
+		if req.ParentID == "" {
+			err = tx.Model(&models.EventCategory{}).Where("event_category_id = ?", id).UpdateColumn("sort_order", i+1).Error
+		} else {
+			err = tx.Model(&models.ChildEventCategory{}).Where("child_event_category_id = ?", id).UpdateColumn("sort_order", i+1).Error
+		}
+		if err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to reorder categories",
+			})
+		}
+	}
+	if err := recordAudit(tx, c, user.UserID, "category.reorder", "event_category", req.ParentID, req); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Categories reordered successfully",
+	})
+}
+
+// InitEventCategoryReferences menghubungkan nilai kategori teks bebas pada event dan series lama
+// ke master kategori. Nama yang tidak dikenal dibuat sebagai kategori nonaktif supaya data tidak hilang
+// dan admin bisa mengganti nama, menggabungkan, atau mengaktifkannya.
+func InitEventCategoryReferences(db *gorm.DB) error {
+	linked := 0
+	for _, model := range []interface{}{&models.Event{}, &models.EventSeries{}} {
+		type categoryPair struct {
+			Category      string
+			ChildCategory string
+		}
+		var pairs []categoryPair
+		if err := db.Model(model).Distinct("category", "child_category").
+			Where("category_id IS NULL AND (category <> '' OR child_category <> '')").
+			Scan(&pairs).Error; err != nil {
+			return err
+		}
+
+		for _, pair := range pairs {
+			parent, child, err := migrateCategoryPair(db, strings.TrimSpace(pair.Category), strings.TrimSpace(pair.ChildCategory))
+			if err != nil {
+				return err
+			}
+			result := db.Model(model).
+				Where("category_id IS NULL AND category = ? AND child_category = ?", pair.Category, pair.ChildCategory).
+				Updates(eventCategoryColumns(parent, child))
+			if result.Error != nil {
+				return result.Error
+			}
+			linked += int(result.RowsAffected)
+		}
+	}
+
+	if linked > 0 {
+		log.Printf(" --  Event categories: %d events and series linked to category IDs", linked)
+	}
+	return nil
+}
+
+// migrateCategoryPair mencari atau membuat kategori untuk satu pasangan nilai teks lama
+func migrateCategoryPair(db *gorm.DB, categoryName, childName string) (*models.EventCategory, *models.ChildEventCategory, error) {
+	var parent *models.EventCategory
+	var child *models.ChildEventCategory
+	var err error
+
+	if categoryName != "" {
+		parent, err = findEventCategory(db, categoryName)
+	} else if childName != "" {
+		// Hanya sub kategori yang terisi: pakai induknya jika namanya unik
+		if child, err = findChildEventCategory(db, childName, ""); err == nil {
+			parent, err = findEventCategory(db, child.ParentCategoryID)
+		}
+		if err != nil && errors.Is(err, errInvalidEventCategory) {
+			categoryName, err = "Lainnya", nil
+		}
+	}
+	if err != nil && !errors.Is(err, errInvalidEventCategory) {
+		return nil, nil, err
+	}
+
+	if parent == nil {
+		created := models.EventCategory{
+			EventCategoryID:   utils.GenerateEventCategoryID(),
+			EventCategoryName: categoryName,
+			CreatedAt:         time.Now(),
+			UpdatedAt:         time.Now(),
+		}
+		if err := db.Create(&created).Error; err != nil {
+			return nil, nil, err
+		}
+		if err := db.Model(&created).UpdateColumn("is_active", false).Error; err != nil {
+			return nil, nil, err
+		}
+		created.IsActive = false
+		parent = &created
+		log.Printf("Created inactive category from legacy value: %s", categoryName)
+	}
+
+	if childName == "" || child != nil {
+		return parent, child, nil
+	}
+	child, err = findChildEventCategory(db, childName, parent.EventCategoryID)
+	if err == nil {
+		return parent, child, nil
+	}
+	if !errors.Is(err, errInvalidEventCategory) {
+		return nil, nil, err
+	}
+
+	created := models.ChildEventCategory{
+		ChildEventCategoryID:   utils.GenerateChildEventCategoryID(),
+		ParentCategoryID:       parent.EventCategoryID,
+		ParentCategoryName:     parent.EventCategoryName,
+		ChildEventCategoryName: childName,
+		CreatedAt:              time.Now(),
+		UpdatedAt:              time.Now(),
+	}
+	if err := db.Create(&created).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := db.Model(&created).UpdateColumn("is_active", false).Error; err != nil {
+		return nil, nil, err
+	}
+	created.IsActive = false
+	log.Printf("Created inactive child category from legacy value: %s -> %s", parent.EventCategoryName, childName)
+	return parent, &created, nil
+}
+
+func InitializeDefaultCategories() error {
+	categories := map[string][]string{
+		"Hiburan":              {"Musik", "Konser", "Festival", "Stand Up Comedy", "Film", "Teater", "K-Pop", "Dance Performance"},
+		"Teknologi":            {"Konferensi Teknologi", "Workshop IT", "Startup", "Software Development", "Artificial Intelligence", "Data Science", "Cybersecurity", "Gaming & Esports"},
+		"Edukasi":              {"Seminar", "Workshop", "Pelatihan", "Webinar", "Bootcamp", "Kelas Online", "Literasi Digital", "Kelas Bisnis"},
+		"Olahraga":             {"Marathon", "Fun Run", "Sepak Bola", "Badminton", "Gym & Fitness", "Yoga", "Esport", "Cycling Event", "Horse Race"},
+		"Bisnis & Profesional": {"Konferensi Bisnis", "Networking", "Karir", "Entrepreneurship", "Leadership", "Startup Meetup", "Investor & Pitching"},
+		"Seni & Budaya":        {"Pameran Seni", "Pentas Budaya", "Fotografi", "Seni Rupa", "Crafting", "Pameran Museum", "Fashion Show"},
+		"Komunitas":            {"Kegiatan Relawan", "Kegiatan Sosial", "Gathering Komunitas", "Komunitas Hobi", "Meetup", "Charity Event"},
+		"Kuliner":              {"Festival Kuliner", "Food Tasting", "Workshop Memasak", "Street Food Event"},
+		"Kesehatan":            {"Seminar Kesehatan", "Medical Check Event", "Workshop Kesehatan Mental", "Donor Darah"},
+		"Agama & Spiritual":    {"Kajian", "Retreat", "Pengajian", "Event Keagamaan", "Meditasi"},
+		"Travel & Outdoor":     {"Camping", "Hiking", "Trip Wisata", "Outdoor Gathering", "Photography Trip"},
+		"Keluarga & Anak":      {"Family Gathering", "Event Anak", "Workshop Parenting", "Pentas Anak"},
+		"Fashion & Beauty":     {"Fashion Expo", "Beauty Class", "Makeup Workshop", "Brand Launching"},
+	}
+
+	var count int64
+	if err := config.DB.Model(&models.EventCategory{}).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check existing categories: %w", err)
+	}
+
+	if count > 0 {
+		log.Println("Default event categories already exist")
+		return nil
+	}
+
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Urutan awal mengikuti abjad; admin bisa menyusun ulang lewat ReorderEventCategories
+	categoryNames := make([]string, 0, len(categories))
+	for categoryName := range categories {
+		categoryNames = append(categoryNames, categoryName)
+	}
+	sort.Strings(categoryNames)
+
+	for i, categoryName := range categoryNames {
+		subcategories := categories[categoryName]
+
+		var existingCategory models.EventCategory
+		err := tx.Where("event_category_name = ?", categoryName).First(&existingCategory).Error
+
+		var categoryID string
+
+		if err != nil {
+
+			categoryID = utils.GenerateEventCategoryID()
+			eventCategory := models.EventCategory{
+				EventCategoryID:   categoryID,
+				EventCategoryName: categoryName,
+				SortOrder:         i + 1,
+				IsActive:          true,
+			}
+
+			if err := tx.Create(&eventCategory).Error; err != nil {
+				tx.Rollback()
+				return fmt.Errorf("failed to create category %s: %w", categoryName, err)
+			}
+			log.Printf("Created main category: %s", categoryName)
+		} else {
+
+			categoryID = existingCategory.EventCategoryID
+			log.Printf("Category already exists: %s", categoryName)
+		}
+
+		for j, subcategoryName := range subcategories {
+
+			var existingSubcategory models.ChildEventCategory
+			err := tx.Where("child_event_category_name = ? AND parent_category_id = ?", subcategoryName, categoryID).First(&existingSubcategory).Error
+
+			if err != nil {
+
+				childEventCategory := models.ChildEventCategory{
+					ChildEventCategoryID:   utils.GenerateChildEventCategoryID(),
+					ParentCategoryID:       categoryID,
+					ParentCategoryName:     categoryName,
+					ChildEventCategoryName: subcategoryName,
+					SortOrder:              j + 1,
+					IsActive:               true,
+				}
+
+				if err := tx.Create(&childEventCategory).Error; err != nil {
+					tx.Rollback()
+					return fmt.Errorf("failed to create subcategory %s for category %s: %w", subcategoryName, categoryName, err)
+				}
+				log.Printf("Created subcategory: %s -> %s", categoryName, subcategoryName)
+			} else {
+				log.Printf("Subcategory already exists: %s -> %s", categoryName, subcategoryName)
+			}
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Println("Successfully initialized all default categories and subcategories")
+	return nil
+}
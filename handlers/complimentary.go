@@ -0,0 +1,457 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type compRecipientResult struct {
+	Recipient string `json:"recipient"`
+	UserID    string `json:"user_id,omitempty"`
+	ClaimID   string `json:"claim_id,omitempty"`
+	Status    string `json:"status"` // issued, pending_claim, skipped, failed
+	Reason    string `json:"reason,omitempty"`
+}
+
+// IssueCompTickets - Organizer membagikan tiket komplimen (guest list / sponsor) secara massal.
+// Penerima bisa dikirim lewat field "recipients" (dipisah koma / baris baru) atau file CSV "recipients_csv"
+// yang kolom pertamanya berisi email atau username.
+func IssueCompTickets(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	eventID := c.Params("id")
+
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	// Check ownership
+	if !canAccessEvent(config.DB, user, event, permEventManage) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to issue tickets for this event",
+		})
+	}
+
+	ticketCategoryID := c.FormValue("ticket_category_id")
+	if ticketCategoryID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Ticket category ID is required",
+		})
+	}
+
+	quantity := 1
+	if quantityStr := c.FormValue("quantity"); quantityStr != "" {
+		parsed, err := strconv.Atoi(quantityStr)
+		if err != nil || parsed < 1 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Quantity must be at least 1",
+			})
+		}
+		quantity = parsed
+	}
+	note := c.FormValue("note")
+
+	recipients := splitRecipients(c.FormValue("recipients"))
+
+	csvFile, err := c.FormFile("recipients_csv")
+	if err == nil {
+		file, err := csvFile.Open()
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to open recipients file",
+			})
+		}
+		defer file.Close()
+
+		fromCSV, err := readRecipientsCSV(file)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid recipients CSV: " + err.Error(),
+			})
+		}
+		recipients = append(recipients, fromCSV...)
+	}
+
+	recipients = uniqueRecipients(recipients)
+	if len(recipients) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "At least one recipient (email or username) is required",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	var ticketCategory models.TicketCategory
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("ticket_category_id = ? AND event_id = ?", ticketCategoryID, event.EventID).
+		First(&ticketCategory).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Ticket category not found",
+		})
+	}
+
+	// Resolve penerima terlebih dahulu supaya kuota komplimen bisa dicek sekaligus
+	type resolvedRecipient struct {
+		raw  string
+		user *models.User
+	}
+	var resolved []resolvedRecipient
+	var results []compRecipientResult
+
+	// Penerima yang sama bisa ditulis sebagai email dan username, jadi duplikat dicek setelah resolve
+	seen := make(map[string]string)
+	addResolved := func(key string, recipient resolvedRecipient) {
+		if first, ok := seen[key]; ok {
+			results = append(results, compRecipientResult{
+				Recipient: recipient.raw,
+				Status:    "skipped",
+				Reason:    "Same recipient as " + first,
+			})
+			return
+		}
+		seen[key] = recipient.raw
+		resolved = append(resolved, recipient)
+	}
+
+	for _, recipient := range recipients {
+		var recipientUser models.User
+		if err := tx.Where("email = ? OR username = ?", recipient, recipient).First(&recipientUser).Error; err == nil {
+			addResolved("user:"+recipientUser.UserID, resolvedRecipient{raw: recipient, user: &recipientUser})
+			continue
+		}
+
+		if !strings.Contains(recipient, "@") {
+			results = append(results, compRecipientResult{
+				Recipient: recipient,
+				Status:    "failed",
+				Reason:    "Username not found",
+			})
+			continue
+		}
+
+		// Email belum punya akun, akan dibuatkan pending claim
+		addResolved("email:"+strings.ToLower(recipient), resolvedRecipient{raw: recipient})
+	}
+
+	needed := uint(len(resolved) * quantity)
+	if ticketCategory.CompIssued+needed > ticketCategory.CompQuota {
+		tx.Rollback()
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":       "Not enough complimentary quota available",
+			"comp_quota":  ticketCategory.CompQuota,
+			"comp_issued": ticketCategory.CompIssued,
+			"requested":   needed,
+			"results":     results,
+		})
+	}
+
+	for _, recipient := range resolved {
+		if recipient.user != nil {
+			if err := createCompTickets(tx, ticketCategory, recipient.user.UserID, uint(quantity)); err != nil {
+				tx.Rollback()
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "Failed to create complimentary ticket: " + err.Error(),
+				})
+			}
+
+			results = append(results, compRecipientResult{
+				Recipient: recipient.raw,
+				UserID:    recipient.user.UserID,
+				Status:    "issued",
+			})
+			continue
+		}
+
+		claim := models.CompTicketClaim{
+			ClaimID:          utils.GenerateCompClaimID(),
+			EventID:          event.EventID,
+			TicketCategoryID: ticketCategory.TicketCategoryID,
+			IssuedBy:         user.UserID,
+			Email:            strings.ToLower(recipient.raw),
+			Quantity:         uint(quantity),
+			Note:             note,
+			Status:           "pending",
+			CreatedAt:        time.Now(),
+			UpdatedAt:        time.Now(),
+		}
+
+		if err := tx.Create(&claim).Error; err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to create pending claim: " + err.Error(),
+			})
+		}
+
+		results = append(results, compRecipientResult{
+			Recipient: recipient.raw,
+			ClaimID:   claim.ClaimID,
+			Status:    "pending_claim",
+		})
+	}
+
+	// Pending claim juga memakai alokasi komplimen agar tidak over-issue
+	if err := tx.Model(&models.TicketCategory{}).
+		Where("ticket_category_id = ?", ticketCategory.TicketCategoryID).
+		UpdateColumn("comp_issued", gorm.Expr("comp_issued + ?", needed)).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update complimentary allocation",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction: " + err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":         "Complimentary tickets processed",
+		"ticket_category": ticketCategory.Name,
+		"comp_quota":      ticketCategory.CompQuota,
+		"comp_issued":     ticketCategory.CompIssued + needed,
+		"results":         results,
+	})
+}
+
+// GetCompTickets - Daftar tiket komplimen dan pending claim untuk sebuah event
+func GetCompTickets(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	eventID := c.Params("id")
+
+	var event models.Event
+	if err := config.DB.Preload("TicketCategories").Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if !canAccessEvent(config.DB, user, event, permEventView) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to view complimentary tickets for this event",
+		})
+	}
+
+	var tickets []models.Ticket
+	if err := config.DB.Preload("Owner").
+		Where("event_id = ? AND is_complimentary = ?", event.EventID, true).
+		Order("created_at DESC").
+		Find(&tickets).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch complimentary tickets",
+		})
+	}
+
+	var claims []models.CompTicketClaim
+	if err := config.DB.Where("event_id = ?", event.EventID).
+		Order("created_at DESC").
+		Find(&claims).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch complimentary claims",
+		})
+	}
+
+	allocations := make([]fiber.Map, 0)
+	for _, tc := range event.TicketCategories {
+		allocations = append(allocations, fiber.Map{
+			"ticket_category_id": tc.TicketCategoryID,
+			"name":               tc.Name,
+			"comp_quota":         tc.CompQuota,
+			"comp_issued":        tc.CompIssued,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":     "Complimentary tickets retrieved successfully",
+		"allocations": allocations,
+		"tickets":     tickets,
+		"claims":      claims,
+	})
+}
+
+// RedeemCompClaims - User mengklaim tiket komplimen yang dikirim ke email-nya sebelum punya akun
+func RedeemCompClaims(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	redeemed, skipped, err := redeemCompClaims(tx, user)
+	if err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to redeem complimentary tickets: " + err.Error(),
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":  "Complimentary claims redeemed",
+		"redeemed": redeemed,
+		"skipped":  skipped,
+	})
+}
+
+// redeemCompClaims mengubah semua pending claim milik email user menjadi tiket aktif.
+// Alokasi komplimen sudah dipotong saat claim dibuat, jadi tidak diubah lagi di sini.
+// Claim yang ticket category-nya sudah tidak ada tetap pending dan dikembalikan sebagai skipped.
+func redeemCompClaims(tx *gorm.DB, user models.User) (int, []fiber.Map, error) {
+	var claims []models.CompTicketClaim
+	if err := tx.Where("email = ? AND status = ?", strings.ToLower(user.Email), "pending").
+		Find(&claims).Error; err != nil {
+		return 0, nil, err
+	}
+
+	redeemed := 0
+	skipped := make([]fiber.Map, 0)
+	for _, claim := range claims {
+		var ticketCategory models.TicketCategory
+		if err := tx.First(&ticketCategory, "ticket_category_id = ?", claim.TicketCategoryID).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return redeemed, skipped, err
+			}
+			skipped = append(skipped, fiber.Map{
+				"claim_id":           claim.ClaimID,
+				"event_id":           claim.EventID,
+				"ticket_category_id": claim.TicketCategoryID,
+				"quantity":           claim.Quantity,
+				"reason":             "ticket category no longer exists",
+			})
+			continue
+		}
+
+		if err := createCompTickets(tx, ticketCategory, user.UserID, claim.Quantity); err != nil {
+			return redeemed, skipped, err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.CompTicketClaim{}).
+			Where("claim_id = ?", claim.ClaimID).
+			Updates(map[string]interface{}{
+				"status":     "claimed",
+				"claimed_by": user.UserID,
+				"claimed_at": &now,
+				"updated_at": now,
+			}).Error; err != nil {
+			return redeemed, skipped, err
+		}
+		redeemed++
+	}
+
+	return redeemed, skipped, nil
+}
+
+func createCompTickets(tx *gorm.DB, ticketCategory models.TicketCategory, ownerID string, quantity uint) error {
+	for i := 0; i < int(quantity); i++ {
+		ticket := models.Ticket{
+			TicketID:         utils.GenerateTicketID(),
+			EventID:          ticketCategory.EventID,
+			TicketCategoryID: ticketCategory.TicketCategoryID,
+			OwnerID:          ownerID,
+			Status:           "active",
+			Code:             utils.GenerateTicketCode(),
+			CreatedAt:        time.Now(),
+			UpdatedAt:        time.Now(),
+			ExpiresAt:        time.Now().Add(1 * time.Minute),
+			Tag:              "Complimentary",
+			IsComplimentary:  true,
+		}
+
+		if err := tx.Create(&ticket).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitRecipients(raw string) []string {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n' || r == '\r'
+	})
+
+	var recipients []string
+	for _, field := range fields {
+		if trimmed := strings.TrimSpace(field); trimmed != "" {
+			recipients = append(recipients, trimmed)
+		}
+	}
+	return recipients
+}
+
+func readRecipientsCSV(r io.Reader) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var recipients []string
+	for row := 0; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) == 0 {
+			continue
+		}
+
+		value := strings.TrimSpace(record[0])
+		// Lewati header
+		if row == 0 {
+			switch strings.ToLower(value) {
+			case "email", "username", "recipient":
+				continue
+			}
+		}
+		if value != "" {
+			recipients = append(recipients, value)
+		}
+	}
+	return recipients, nil
+}
+
+func uniqueRecipients(recipients []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, recipient := range recipients {
+		key := strings.ToLower(recipient)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, recipient)
+	}
+	return unique
+}
@@ -3,9 +3,10 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -17,12 +18,15 @@ import (
 )
 
 type TicketCategoryRequest struct {
-	Name          string  `json:"name"`
-	Price         float64 `json:"price"`
-	Quota         uint    `json:"quota"`
-	Description   string  `json:"description"`
-	DateTimeStart string  `json:"date_time_start"`
-	DateTimeEnd   string  `json:"date_time_end"`
+	TicketCategoryID string  `json:"ticket_category_id"` // diisi saat edit untuk update in-place
+	Name             string  `json:"name"`
+	Price            float64 `json:"price"`
+	Quota            uint    `json:"quota"`
+	CompQuota        uint    `json:"comp_quota"`
+	IsHidden         bool    `json:"is_hidden"`
+	Description      string  `json:"description"`
+	DateTimeStart    string  `json:"date_time_start"`
+	DateTimeEnd      string  `json:"date_time_end"`
 }
 
 func CreateEvent(c *fiber.Ctx) error {
@@ -35,8 +39,8 @@ func CreateEvent(c *fiber.Ctx) error {
 	district := c.FormValue("district")
 	description := c.FormValue("description")
 	rules := c.FormValue("rules") // Tambahkan rules
-	category := c.FormValue("category")
-	childCategory := c.FormValue("child_category")
+	category := firstNonEmpty(c.FormValue("category_id"), c.FormValue("category"))
+	childCategory := firstNonEmpty(c.FormValue("child_category_id"), c.FormValue("child_category"))
 	ticketCategoriesJSON := c.FormValue("ticket_categories")
 
 	var ticketCategories []TicketCategoryRequest
@@ -48,6 +52,21 @@ func CreateEvent(c *fiber.Ctx) error {
 		}
 	}
 
+	// Venue dari direktori mengisi venue, location, district, dan koordinat
+	var venueID *string
+	var latitude, longitude *float64
+	if id := c.FormValue("venue_id"); id != "" {
+		directoryVenue, status, err := resolveEventVenue(config.DB, user, id)
+		if err != nil {
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		venueID = &directoryVenue.VenueID
+		venue, location, district = directoryVenue.Name, directoryVenue.Address, directoryVenue.District
+		latitude, longitude = directoryVenue.Latitude, directoryVenue.Longitude
+	}
+
 	// Validasi required fields
 	if name == "" || dateStartStr == "" || dateEndStr == "" || location == "" || venue == "" || district == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -55,18 +74,55 @@ func CreateEvent(c *fiber.Ctx) error {
 		})
 	}
 
+	// Kategori harus ada di master kategori dan masih aktif
+	parentCategory, childEventCategory, err := resolveEventCategory(config.DB, category, childCategory, nil)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// Zona waktu event (IANA), default WIB. Tanggal tanpa offset diartikan sebagai jam lokal zona ini.
+	loc, err := utils.LoadTimeZone(c.FormValue("time_zone"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
 	// Parse dates
-	dateStart, err := time.Parse(time.RFC3339, dateStartStr)
+	dateStart, err := utils.ParseEventTime(dateStartStr, loc)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid date_start format. Use RFC3339 format (e.g., 2024-07-01T18:00:00Z)",
+			"error": "Invalid date_start format: " + err.Error(),
 		})
 	}
 
-	dateEnd, err := time.Parse(time.RFC3339, dateEndStr)
+	dateEnd, err := utils.ParseEventTime(dateEndStr, loc)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid date_end format. Use RFC3339 format (e.g., 2024-07-01T23:00:00Z)",
+			"error": "Invalid date_end format: " + err.Error(),
+		})
+	}
+
+	// Koordinat opsional, jika kosong dicoba lewat geocoding
+	if venueID == nil {
+		latitude, longitude, err = parseCoordinates(c.FormValue("latitude"), c.FormValue("longitude"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if latitude == nil {
+			latitude, longitude = geocodeAddress(venue, location, district)
+		}
+	}
+
+	// Event dimiliki organisasi; default ke organisasi pribadi pembuatnya
+	organizationID, status, err := resolveEventOrganization(config.DB, user, c.FormValue("organization_id"))
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
@@ -113,25 +169,29 @@ func CreateEvent(c *fiber.Ctx) error {
 
 	// Create event
 	event := models.Event{
-		EventID:       utils.GenerateEventID(),
-		Name:          name,
-		OwnerID:       user.UserID,
-		Status:        "pending",
-		DateStart:     dateStart,
-		DateEnd:       dateEnd,
-		Location:      location,
-		Venue:         venue,
-		District:      district,
-		Description:   description,
-		Rules:         rules,
-		TotalLikes:    0,
-		Image:         imageURL,
-		Flyer:         flyerURL,
-		Category:      category,
-		ChildCategory: childCategory,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
-	}
+		EventID:        utils.GenerateEventID(),
+		Name:           name,
+		OwnerID:        user.UserID,
+		OrganizationID: &organizationID,
+		Status:         "pending",
+		DateStart:      dateStart,
+		DateEnd:        dateEnd,
+		TimeZone:       loc.String(),
+		Location:       location,
+		Venue:          venue,
+		VenueID:        venueID,
+		District:       district,
+		Latitude:       latitude,
+		Longitude:      longitude,
+		Description:    description,
+		Rules:          rules,
+		TotalLikes:     0,
+		Image:          imageURL,
+		Flyer:          flyerURL,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	applyEventCategory(&event, parentCategory, childEventCategory)
 
 	if err := tx.Create(&event).Error; err != nil {
 		tx.Rollback()
@@ -144,7 +204,7 @@ func CreateEvent(c *fiber.Ctx) error {
 	var createdTicketCategories []models.TicketCategory
 	if len(ticketCategories) > 0 {
 		for _, tcReq := range ticketCategories {
-			dateTimeStart, err := time.Parse(time.RFC3339, tcReq.DateTimeStart)
+			dateTimeStart, err := utils.ParseEventTime(tcReq.DateTimeStart, loc)
 			if err != nil {
 				tx.Rollback()
 				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -152,7 +212,7 @@ func CreateEvent(c *fiber.Ctx) error {
 				})
 			}
 
-			dateTimeEnd, err := time.Parse(time.RFC3339, tcReq.DateTimeEnd)
+			dateTimeEnd, err := utils.ParseEventTime(tcReq.DateTimeEnd, loc)
 			if err != nil {
 				tx.Rollback()
 				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -173,6 +233,8 @@ func CreateEvent(c *fiber.Ctx) error {
 				Name:             tcReq.Name,
 				Price:            tcReq.Price,
 				Quota:            tcReq.Quota,
+				CompQuota:        tcReq.CompQuota,
+				IsHidden:         tcReq.IsHidden,
 				Description:      tcReq.Description,
 				DateTimeStart:    dateTimeStart,
 				DateTimeEnd:      dateTimeEnd,
@@ -192,6 +254,21 @@ func CreateEvent(c *fiber.Ctx) error {
 		}
 	}
 
+	if err := assignEventSlug(tx, event.EventID); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate event slug",
+		})
+	}
+
+	// Event baru masuk antrian moderasi admin
+	if err := enqueueModeration(tx, moderationEntityEvent, event.EventID, user.UserID); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to queue event for review",
+		})
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		tx.Rollback()
@@ -209,8 +286,6 @@ func CreateEvent(c *fiber.Ctx) error {
 		})
 	}
 
-	// ScheduleEventEnd(config.DB, event)
-
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"message": "Event created successfully",
 		"event":   eventWithOwner,
@@ -229,16 +304,17 @@ func UpdateEvent(c *fiber.Ctx) error {
 	}
 
 	// Check ownership
-	if event.OwnerID != user.UserID && user.Role != "admin" {
+	if !canAccessEvent(config.DB, user, event, permEventManage) {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 			"error": "Not authorized to update this event",
 		})
 	}
 
 	// Check if event can be edited (only pending or rejected)
+	// Event yang sudah live diubah lewat change request (POST /api/events/:id/change-requests)
 	if event.Status != "pending" && event.Status != "rejected" {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Event can only be edited when status is pending or rejected",
+			"error": "Event can only be edited when status is pending or rejected. Submit a change request for live events",
 		})
 	}
 
@@ -251,10 +327,26 @@ func UpdateEvent(c *fiber.Ctx) error {
 	district := c.FormValue("district")
 	description := c.FormValue("description")
 	rules := c.FormValue("rules") // Tambahkan rules
-	category := c.FormValue("category")
-	childCategory := c.FormValue("child_category")
+	category := firstNonEmpty(c.FormValue("category_id"), c.FormValue("category"))
+	childCategory := firstNonEmpty(c.FormValue("child_category_id"), c.FormValue("child_category"))
 	ticketCategoriesJSON := c.FormValue("ticket_categories")
 
+	// Koordinat: pakai nilai dari form, atau geocode ulang jika alamat berubah.
+	// Geocode dilakukan sebelum transaksi dibuka karena memanggil layanan eksternal.
+	latitude, longitude, err := parseCoordinates(c.FormValue("latitude"), c.FormValue("longitude"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if latitude == nil && c.FormValue("venue_id") == "" && (location != "" || venue != "" || district != "") {
+		latitude, longitude = geocodeAddress(
+			firstNonEmpty(venue, event.Venue),
+			firstNonEmpty(location, event.Location),
+			firstNonEmpty(district, event.District),
+		)
+	}
+
 	// Mulai transaction
 	tx := config.DB.Begin()
 	if tx.Error != nil {
@@ -284,6 +376,28 @@ func UpdateEvent(c *fiber.Ctx) error {
 		updateData["district"] = district
 		event.District = district
 	}
+	// Alamat teks bebas melepas event dari venue direktori
+	var venueLatitude, venueLongitude *float64
+	if location != "" || venue != "" || district != "" {
+		updateData["venue_id"] = nil
+		event.VenueID = nil
+	}
+	if id := c.FormValue("venue_id"); id != "" {
+		directoryVenue, status, err := resolveEventVenue(tx, user, id)
+		if err != nil {
+			tx.Rollback()
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		updateData["venue_id"] = directoryVenue.VenueID
+		updateData["venue"] = directoryVenue.Name
+		updateData["location"] = directoryVenue.Address
+		updateData["district"] = directoryVenue.District
+		event.VenueID = &directoryVenue.VenueID
+		event.Venue, event.Location, event.District = directoryVenue.Name, directoryVenue.Address, directoryVenue.District
+		venueLatitude, venueLongitude = directoryVenue.Latitude, directoryVenue.Longitude
+	}
 	if description != "" {
 		updateData["description"] = description
 		event.Description = description
@@ -292,18 +406,47 @@ func UpdateEvent(c *fiber.Ctx) error {
 		updateData["rules"] = rules // Tambahkan rules
 		event.Rules = rules
 	}
-	if category != "" {
-		updateData["category"] = category
-		event.Category = category
+	if category != "" || childCategory != "" {
+		parentCategory, childEventCategory, err := resolveEventUpdateCategory(tx, category, childCategory, event)
+		if err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		for column, value := range eventCategoryColumns(parentCategory, childEventCategory) {
+			updateData[column] = value
+		}
+		applyEventCategory(&event, parentCategory, childEventCategory)
 	}
-	if childCategory != "" {
-		updateData["child_category"] = childCategory
-		event.ChildCategory = childCategory
+
+	if latitude == nil && venueLatitude != nil {
+		latitude, longitude = venueLatitude, venueLongitude
+	}
+	if latitude != nil {
+		updateData["latitude"] = *latitude
+		updateData["longitude"] = *longitude
+		event.Latitude = latitude
+		event.Longitude = longitude
+	}
+
+	// Zona waktu bisa diganti; tanggal tanpa offset diartikan dalam zona yang berlaku
+	if timeZone := c.FormValue("time_zone"); timeZone != "" {
+		loc, err := utils.LoadTimeZone(timeZone)
+		if err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		updateData["time_zone"] = loc.String()
+		event.TimeZone = loc.String()
 	}
+	loc := utils.TimeZoneOrDefault(event.TimeZone)
 
 	// Parse dates if provided
 	if dateStartStr != "" {
-		dateStart, err := time.Parse(time.RFC3339, dateStartStr)
+		dateStart, err := utils.ParseEventTime(dateStartStr, loc)
 		if err != nil {
 			tx.Rollback()
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -315,7 +458,7 @@ func UpdateEvent(c *fiber.Ctx) error {
 	}
 
 	if dateEndStr != "" {
-		dateEnd, err := time.Parse(time.RFC3339, dateEndStr)
+		dateEnd, err := utils.ParseEventTime(dateEndStr, loc)
 		if err != nil {
 			tx.Rollback()
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -366,6 +509,11 @@ func UpdateEvent(c *fiber.Ctx) error {
 
 	updateData["status"] = "pending"
 
+	// Event yang diedit sendiri tidak lagi ikut perubahan "future" dari series-nya
+	if event.SeriesID != nil {
+		updateData["series_exception"] = true
+	}
+
 	// Update event
 	if err := tx.Model(&event).Updates(updateData).Error; err != nil {
 		tx.Rollback()
@@ -374,6 +522,22 @@ func UpdateEvent(c *fiber.Ctx) error {
 		})
 	}
 
+	// Slug lama tetap tersimpan sebagai riwayat jika nama event berubah
+	if err := assignEventSlug(tx, event.EventID); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update event slug",
+		})
+	}
+
+	// Event yang diedit kembali pending dan diajukan ulang ke antrian moderasi
+	if err := enqueueModeration(tx, moderationEntityEvent, event.EventID, user.UserID); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to queue event for review",
+		})
+	}
+
 	// Handle ticket categories update if provided
 	if ticketCategoriesJSON != "" {
 		var ticketCategories []TicketCategoryRequest
@@ -384,51 +548,17 @@ func UpdateEvent(c *fiber.Ctx) error {
 			})
 		}
 
-		// Delete existing ticket categories
-		if err := tx.Where("event_id = ?", event.EventID).Delete(&models.TicketCategory{}).Error; err != nil {
+		// Update in-place supaya cart dan tiket yang sudah ada tidak kehilangan referensi
+		if err := syncTicketCategories(tx, event.EventID, ticketCategories, loc); err != nil {
 			tx.Rollback()
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to delete existing ticket categories",
-			})
-		}
-
-		// Create new ticket categories
-		for _, tcReq := range ticketCategories {
-			dateTimeStart, err := time.Parse(time.RFC3339, tcReq.DateTimeStart)
-			if err != nil {
-				tx.Rollback()
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error": "Invalid date_time_start format",
-				})
-			}
-
-			dateTimeEnd, err := time.Parse(time.RFC3339, tcReq.DateTimeEnd)
-			if err != nil {
-				tx.Rollback()
+			if errors.Is(err, errInvalidTicketCategory) {
 				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error": "Invalid date_time_end format",
-				})
-			}
-
-			ticketCategory := models.TicketCategory{
-				TicketCategoryID: utils.GenerateTicketCategoryID(),
-				EventID:          event.EventID,
-				Name:             tcReq.Name,
-				Price:            tcReq.Price,
-				Quota:            tcReq.Quota,
-				Description:      tcReq.Description,
-				DateTimeStart:    dateTimeStart,
-				DateTimeEnd:      dateTimeEnd,
-				CreatedAt:        time.Now(),
-				UpdatedAt:        time.Now(),
-			}
-
-			if err := tx.Create(&ticketCategory).Error; err != nil {
-				tx.Rollback()
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": "Failed to create ticket category",
+					"error": err.Error(),
 				})
 			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update ticket categories",
+			})
 		}
 	}
 
@@ -440,6 +570,9 @@ func UpdateEvent(c *fiber.Ctx) error {
 		})
 	}
 
+	// Event kembali pending, job start/end yang sempat terjadwal dibatalkan
+	SyncEventLifecycleJobs(config.DB, event.EventID)
+
 	// Reload event with relationships
 	var updatedEvent models.Event
 	if err := config.DB.Preload("Owner").Preload("TicketCategories").
@@ -456,23 +589,38 @@ func UpdateEvent(c *fiber.Ctx) error {
 	})
 }
 
+// GetApprovedEvents - Listing event publik dengan search, filter, sort dan pagination.
+// Query: q, category, child_category, district, date_from, date_to, price_min, price_max, free,
+// status, lat, lng, radius, bbox, sort (date|price|popularity|recent|distance), order (asc|desc),
+// page, limit, cursor
 func GetApprovedEvents(c *fiber.Ctx) error {
-	var events []models.Event
-	if err := config.DB.Preload("Owner").Preload("TicketCategories").Where("status IN (?)", []string{"active", "approved", "ended"}).Find(&events).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch events",
+	query, err := parseEventListQuery(c, "date")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
-	return c.JSON(events)
+	return respondEventList(c, query)
 }
 
 func GetMyEvents(c *fiber.Ctx) error {
 	user := c.Locals("user").(models.User)
 
+	organizationIDs, err := memberOrganizationIDs(config.DB, user.UserID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch your organizations",
+		})
+	}
+
+	query := ownedByUserOrOrganizations(config.DB, user.UserID, organizationIDs)
+	if organizationID := c.Query("organization_id"); organizationID != "" {
+		query = query.Where("organization_id = ?", organizationID)
+	}
+
 	var events []models.Event
-	if err := config.DB.Preload("Owner").Preload("TicketCategories").
-		Where("owner_id = ?", user.UserID).
+	if err := query.Preload("Owner").Preload("TicketCategories").
 		Order("created_at DESC").
 		Find(&events).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -498,10 +646,25 @@ func GetEvents(c *fiber.Ctx) error {
 }
 
 func GetEvent(c *fiber.Ctx) error {
-	eventID := c.Params("id")
+	return respondEventDetail(c, c.Params("id"))
+}
+
+// respondEventDetail mengirim detail event, dipakai oleh GetEvent dan GetEventBySlug
+func respondEventDetail(c *fiber.Ctx, eventID string) error {
+	// Category hidden hanya ditampilkan jika kode akses yang valid dikirim lewat query access_code
+	unlocked := unlockedCategoryIDs(config.DB, eventID, c.Query("access_code"))
 
 	var event models.Event
-	if err := config.DB.Preload("Owner").Preload("TicketCategories").
+	if err := config.DB.Preload("Owner").
+		Preload("TicketCategories", func(db *gorm.DB) *gorm.DB {
+			if len(unlocked) > 0 {
+				return db.Where("is_hidden = ? OR ticket_category_id IN ?", false, unlocked)
+			}
+			return publicTicketCategories(db)
+		}).
+		Preload("Gallery", galleryOrder).
+		Preload("Organization").
+		Preload("VenueDetail").
 		Where("event_id = ?", eventID).
 		First(&event).Error; err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -509,10 +672,21 @@ func GetEvent(c *fiber.Ctx) error {
 		})
 	}
 
+	if containsString(publicEventStatuses, event.Status) {
+		recordEventView(c, event.EventID)
+	}
+
+	if err := localizeEvent(config.DB, c, &event); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load translations",
+		})
+	}
+
 	return c.JSON(event)
 }
 
 func VerifyEvent(c *fiber.Ctx) error {
+	admin := c.Locals("user").(models.User)
 	eventID := c.Params("id")
 
 	var event models.Event
@@ -525,6 +699,7 @@ func VerifyEvent(c *fiber.Ctx) error {
 	var req struct {
 		Status          string `json:"status"`
 		ApprovalComment string `json:"approval_comment,omitempty"`
+		ReasonCode      string `json:"reason_code,omitempty"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -533,15 +708,49 @@ func VerifyEvent(c *fiber.Ctx) error {
 		})
 	}
 
-	event.Status = req.Status
-	event.ApprovalComment = req.ApprovalComment
+	// Client lama belum mengirim reason_code, anggap "other" dengan approval_comment sebagai alasan
+	if req.Status == "rejected" && req.ReasonCode == "" {
+		req.ReasonCode = "other"
+	}
+	status, err := validateModerationDecision(req.Status, req.ReasonCode, req.ApprovalComment)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// Keputusan lewat endpoint ini tetap tercatat di antrian moderasi
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
 
-	if err := config.DB.Save(&event).Error; err != nil {
+	item, err := lockOpenModerationItem(tx, moderationEntityEvent, event.EventID, event.OwnerID)
+	if err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load moderation item",
+		})
+	}
+
+	if err := decideModeration(tx, c, item, admin.UserID, status, req.ReasonCode, req.ApprovalComment); err != nil {
+		tx.Rollback()
+		if errors.Is(err, errModerationAssigned) {
+			return moderationAssignedResponse(c, item)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to verify event",
 		})
 	}
 
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
 	var eventWithOwner models.Event
 	if err := config.DB.Preload("Owner").Preload("TicketCategories").
 		Where("event_id = ?", event.EventID).
@@ -551,13 +760,13 @@ func VerifyEvent(c *fiber.Ctx) error {
 		})
 	}
 
-	if event.Status == "approved" {
-		ScheduleEventEnd(config.DB, event)
-	}
+	// Jadwalkan ulang (atau batalkan) transisi active/ended sesuai status baru
+	SyncEventLifecycleJobs(config.DB, event.EventID)
+	notifyModerationResult(*item)
 
 	return c.JSON(fiber.Map{
 		"message": "Event verification updated",
-		"event":   event,
+		"event":   eventWithOwner,
 	})
 }
 
@@ -573,59 +782,72 @@ func DeleteEvent(c *fiber.Ctx) error {
 	}
 
 	// Check ownership or admin
-	if event.OwnerID != user.UserID && user.Role != "admin" {
+	if !canAccessEvent(config.DB, user, event, permEventDelete) {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 			"error": "Not authorized to delete this event",
 		})
 	}
 
+	// Event yang sudah punya tiket atau transaksi harus dibatalkan, bukan dihapus
+	var ticketCount int64
+	config.DB.Model(&models.Ticket{}).Where("event_id = ?", event.EventID).Count(&ticketCount)
+	if ticketCount > 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Event already has tickets. Cancel the event instead so buyers are refunded",
+		})
+	}
+
 	if err := config.DB.Delete(&event).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to delete event",
 		})
 	}
 
-	return c.JSON(fiber.Map{
-		"message": "Event deleted successfully",
-	})
-}
+	SyncEventLifecycleJobs(config.DB, event.EventID)
 
-func GetEventsPopular(c *fiber.Ctx) error {
+	if err := withdrawModeration(config.DB, moderationEntityEvent, event.EventID, user.UserID); err != nil {
+		log.Printf("Failed to withdraw moderation item for event %s: %v", event.EventID, err)
+	}
 
-	var events []models.Event
-	if err := config.DB.Preload("Owner").Preload("TicketCategories").
-		Where("status = ?", "approved").
-		Order("total_likes DESC").
-		Limit(6).
-		Find(&events).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch events",
-		})
+	// Hapus galeri beserta file-nya di storage
+	var gallery []models.EventMedia
+	if err := config.DB.Where("event_id = ?", event.EventID).Find(&gallery).Error; err == nil && len(gallery) > 0 {
+		cleanupUploadedMedia(withoutSharedMedia(config.DB, gallery))
+		config.DB.Where("event_id = ?", event.EventID).Delete(&models.EventMedia{})
 	}
 
+	// Slug event yang dihapus dibebaskan untuk event lain
+	config.DB.Where("event_id = ?", event.EventID).Delete(&models.EventSlug{})
+	config.DB.Where("event_id = ?", event.EventID).Delete(&models.EventTranslation{})
+	config.DB.Where("event_id = ?", event.EventID).Delete(&models.TicketCategoryTranslation{})
+	config.DB.Where("event_id = ?", event.EventID).Delete(&models.RegistrationAnswer{})
+	config.DB.Where("event_id = ?", event.EventID).Delete(&models.RegistrationQuestion{})
+
 	return c.JSON(fiber.Map{
-		"events": events,
+		"message": "Event deleted successfully",
 	})
 }
 
 type EventReportResponse struct {
-	Event            models.Event          `json:"event"`
-	PurchaseData     []TicketCategoryStats `json:"purchase_data"`
-	CheckinData      []TicketCategoryStats `json:"checkin_data"`
-	AttendantData    []TicketCategoryStats `json:"attendant_data"`
-	TotalIncome      float64               `json:"total_income"`
-	TotalTicketsSold int                   `json:"total_tickets_sold"`
-	TotalCheckins    int                   `json:"total_checkins"`
-	TotalLikes       uint                  `json:"total_likes"`
-	TotalQuota       int                   `json:"total_quota"`
+	Event              models.Event          `json:"event"`
+	PurchaseData       []TicketCategoryStats `json:"purchase_data"`
+	CheckinData        []TicketCategoryStats `json:"checkin_data"`
+	AttendantData      []TicketCategoryStats `json:"attendant_data"`
+	TotalIncome        float64               `json:"total_income"`
+	TotalTicketsSold   int                   `json:"total_tickets_sold"`
+	TotalCheckins      int                   `json:"total_checkins"`
+	TotalLikes         uint                  `json:"total_likes"`
+	TotalQuota         int                   `json:"total_quota"`
+	TotalComplimentary int                   `json:"total_complimentary"`
 }
 
 type TicketCategoryStats struct {
-	Name       string  `json:"name"`
-	Value      int     `json:"value"`
-	Quota      int     `json:"quota"`
-	Price      float64 `json:"price"`
-	Percentage float64 `json:"percentage"`
+	Name          string  `json:"name"`
+	Value         int     `json:"value"`
+	Quota         int     `json:"quota"`
+	Price         float64 `json:"price"`
+	Percentage    float64 `json:"percentage"`
+	Complimentary int     `json:"complimentary"`
 }
 
 func GetEventReport(c *fiber.Ctx) error {
@@ -642,7 +864,7 @@ func GetEventReport(c *fiber.Ctx) error {
 	}
 
 	// Check ownership
-	if event.OwnerID != user.UserID && user.Role != "admin" {
+	if !canAccessEvent(config.DB, user, event, permReportView) {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 			"error": "Not authorized to view this report",
 		})
@@ -658,6 +880,7 @@ func GetEventReport(c *fiber.Ctx) error {
 	var totalCheckedIn int64 = 0
 	var totalQuota int = 0
 	var totalIncome float64 = 0
+	var totalComplimentary int = 0
 
 	// Log untuk debug
 	log.Printf("Event ID: %s, TicketCategories count: %d", eventID, len(event.TicketCategories))
@@ -667,6 +890,7 @@ func GetEventReport(c *fiber.Ctx) error {
 		// Langsung ambil dari field Sold dan Attendant di TicketCategory
 		soldCount := int64(ticketCategory.Sold)
 		checkedInCount := int64(ticketCategory.Attendant)
+		compCount := int(ticketCategory.CompIssued)
 
 		// Log untuk debug setiap kategori
 		log.Printf("Category: %s, Sold: %d, Attendant: %d, Quota: %d, Price: %.2f",
@@ -678,39 +902,43 @@ func GetEventReport(c *fiber.Ctx) error {
 			soldPercentage = (float64(soldCount) / float64(ticketCategory.Quota)) * 100
 		}
 
-		// Calculate percentage of check-ins for this category
+		// Calculate percentage of check-ins for this category (termasuk tiket komplimen)
 		checkinPercentage := float64(0)
-		if soldCount > 0 {
-			checkinPercentage = (float64(checkedInCount) / float64(soldCount)) * 100
+		if soldCount+int64(compCount) > 0 {
+			checkinPercentage = (float64(checkedInCount) / float64(soldCount+int64(compCount))) * 100
 		}
 
 		// Calculate income for this category
 		categoryIncome := float64(soldCount) * ticketCategory.Price
 
 		purchaseData = append(purchaseData, TicketCategoryStats{
-			Name:       ticketCategory.Name,
-			Value:      int(soldCount),
-			Quota:      int(ticketCategory.Quota),
-			Price:      ticketCategory.Price,
-			Percentage: soldPercentage,
+			Name:          ticketCategory.Name,
+			Value:         int(soldCount),
+			Quota:         int(ticketCategory.Quota),
+			Price:         ticketCategory.Price,
+			Percentage:    soldPercentage,
+			Complimentary: compCount,
 		})
 
 		checkinData = append(checkinData, TicketCategoryStats{
-			Name:       ticketCategory.Name,
-			Value:      int(checkedInCount),
-			Quota:      int(soldCount),
-			Price:      ticketCategory.Price,
-			Percentage: checkinPercentage,
+			Name:          ticketCategory.Name,
+			Value:         int(checkedInCount),
+			Quota:         int(soldCount) + compCount,
+			Price:         ticketCategory.Price,
+			Percentage:    checkinPercentage,
+			Complimentary: compCount,
 		})
 
 		attendantData = append(attendantData, TicketCategoryStats{
-			Name:       ticketCategory.Name,
-			Value:      int(checkedInCount),
-			Quota:      int(ticketCategory.Quota),
-			Price:      ticketCategory.Price,
-			Percentage: checkinPercentage,
+			Name:          ticketCategory.Name,
+			Value:         int(checkedInCount),
+			Quota:         int(ticketCategory.Quota),
+			Price:         ticketCategory.Price,
+			Percentage:    checkinPercentage,
+			Complimentary: compCount,
 		})
 
+		totalComplimentary += compCount
 		totalSold += soldCount
 		totalCheckedIn += checkedInCount
 		totalQuota += int(ticketCategory.Quota)
@@ -729,31 +957,33 @@ func GetEventReport(c *fiber.Ctx) error {
 	}
 
 	attendanceRate := "0%"
-	if totalSold > 0 {
-		rate := (float64(totalCheckedIn) / float64(totalSold)) * 100
+	if totalSold+int64(totalComplimentary) > 0 {
+		rate := (float64(totalCheckedIn) / float64(totalSold+int64(totalComplimentary))) * 100
 		attendanceRate = fmt.Sprintf("%.1f%%", rate)
 	}
 
 	// Create metrics map
 	metrics := fiber.Map{
-		"total_attendant":    totalCheckedIn,
-		"total_tickets_sold": totalSold,
-		"total_sales":        totalIncome,
-		"total_quota":        totalQuota,
-		"sold_percentage":    soldPercentage,
-		"attendance_rate":    attendanceRate,
+		"total_attendant":     totalCheckedIn,
+		"total_tickets_sold":  totalSold,
+		"total_sales":         totalIncome,
+		"total_quota":         totalQuota,
+		"total_complimentary": totalComplimentary,
+		"sold_percentage":     soldPercentage,
+		"attendance_rate":     attendanceRate,
 	}
 
 	report := EventReportResponse{
-		Event:            event,
-		PurchaseData:     purchaseData,
-		CheckinData:      checkinData,
-		AttendantData:    attendantData,
-		TotalIncome:      totalIncome,
-		TotalLikes:       event.TotalLikes,
-		TotalTicketsSold: int(totalSold),
-		TotalCheckins:    int(totalCheckedIn),
-		TotalQuota:       totalQuota,
+		Event:              event,
+		PurchaseData:       purchaseData,
+		CheckinData:        checkinData,
+		AttendantData:      attendantData,
+		TotalIncome:        totalIncome,
+		TotalLikes:         event.TotalLikes,
+		TotalTicketsSold:   int(totalSold),
+		TotalCheckins:      int(totalCheckedIn),
+		TotalQuota:         totalQuota,
+		TotalComplimentary: totalComplimentary,
 	}
 
 	return c.JSON(fiber.Map{
@@ -762,10 +992,18 @@ func GetEventReport(c *fiber.Ctx) error {
 	})
 }
 
+// DownloadEventReport - Unduh laporan event. Query format=csv (default), xlsx, atau pdf.
 func DownloadEventReport(c *fiber.Ctx) error {
 	eventID := c.Params("id")
 	user := c.Locals("user").(models.User)
 
+	format := strings.ToLower(c.Query("format", reportFormatCSV))
+	if _, ok := reportContentTypes[format]; !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "format must be one of 'csv', 'xlsx' or 'pdf'",
+		})
+	}
+
 	var event models.Event
 	if err := config.DB.Preload("Owner").Preload("TicketCategories").
 		Where("event_id = ?", eventID).
@@ -776,86 +1014,30 @@ func DownloadEventReport(c *fiber.Ctx) error {
 	}
 
 	// Check ownership
-	if event.OwnerID != user.UserID && user.Role != "admin" {
+	if !canAccessEvent(config.DB, user, event, permReportView) {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 			"error": "Not authorized to download this report",
 		})
 	}
 
-	// Generate CSV report
-	csvData := "Kategori_Tiket,Harga_Tiket,Kuota_Tiket,Tiket_Terjual,Persentase_Terjual,Tiket_Check_in,Persentase_Check_in,Pendapatan_Kategori\n"
-
-	var grandTotalSold int64 = 0
-	var grandTotalCheckedIn int64 = 0
-	var grandTotalQuota int64 = 0
-	var grandTotalIncome float64 = 0
-
-	// Calculate data per category - langsung dari TicketCategory
-	for _, ticketCategory := range event.TicketCategories {
-		// Langsung ambil dari field Sold dan Attendant di TicketCategory
-		soldCount := int64(ticketCategory.Sold)
-		checkedInCount := int64(ticketCategory.Attendant)
-
-		// Log untuk debug
-		log.Printf("Download Report - Category: %s, Sold: %d, Attendant: %d",
-			ticketCategory.Name, soldCount, checkedInCount)
-
-		// Handle division by zero
-		soldPercentage := float64(0)
-		if ticketCategory.Quota > 0 {
-			soldPercentage = (float64(soldCount) / float64(ticketCategory.Quota)) * 100
-		}
-
-		checkInPercentage := float64(0)
-		if soldCount > 0 {
-			checkInPercentage = (float64(checkedInCount) / float64(soldCount)) * 100
-		}
+	data, err := buildEventReportData(event)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to build report",
+		})
+	}
+	content, err := renderEventReport(data, format)
+	if err != nil {
+		log.Printf("Failed to render %s report for event %s: %v", format, event.EventID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate report",
+		})
+	}
 
-		categoryIncome := float64(soldCount) * ticketCategory.Price
+	c.Set("Content-Type", reportContentTypes[format])
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", eventExportFilename("laporan", event, format)))
 
-		csvData += fmt.Sprintf("%s,%.0f,%d,%d,%.2f%%,%d,%.2f%%,%.0f\n",
-			ticketCategory.Name,
-			ticketCategory.Price,
-			ticketCategory.Quota,
-			soldCount,
-			soldPercentage,
-			checkedInCount,
-			checkInPercentage,
-			categoryIncome)
-
-		grandTotalSold += soldCount
-		grandTotalCheckedIn += checkedInCount
-		grandTotalQuota += int64(ticketCategory.Quota)
-		grandTotalIncome += categoryIncome
-	}
-
-	// Calculate overall percentages
-	overallSoldPercentage := float64(0)
-	if grandTotalQuota > 0 {
-		overallSoldPercentage = (float64(grandTotalSold) / float64(grandTotalQuota)) * 100
-	}
-
-	overallCheckInPercentage := float64(0)
-	if grandTotalSold > 0 {
-		overallCheckInPercentage = (float64(grandTotalCheckedIn) / float64(grandTotalSold)) * 100
-	}
-
-	// Add summary section
-	csvData += "\n"
-	csvData += fmt.Sprintf("RINGKASAN LAPORAN EVENT: %s\n", event.Name)
-	csvData += fmt.Sprintf("Tanggal Event:,%s - %s\n", event.DateStart.Format("02-01-2006"), event.DateEnd.Format("02-01-2006"))
-	csvData += fmt.Sprintf("Lokasi:,%s - %s\n", event.Venue, event.Location)
-	csvData += "\n"
-	csvData += fmt.Sprintf("Total Kuota Tiket:,%d\n", grandTotalQuota)
-	csvData += fmt.Sprintf("Total Tiket Terjual:,%d (%.2f%%)\n", grandTotalSold, overallSoldPercentage)
-	csvData += fmt.Sprintf("Total Check-in:,%d (%.2f%%)\n", grandTotalCheckedIn, overallCheckInPercentage)
-	csvData += fmt.Sprintf("Total Pendapatan:,Rp %.0f\n", grandTotalIncome)
-	csvData += fmt.Sprintf("Total Like:,%d\n", event.TotalLikes)
-
-	c.Set("Content-Type", "text/csv; charset=utf-8")
-	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=laporan_%s_%s.csv", event.Name, time.Now().Format("2006-01-02")))
-
-	return c.SendString(csvData)
+	return c.Send(content)
 }
 
 func AddLike(c *fiber.Ctx) error {
@@ -907,9 +1089,11 @@ func AddLike(c *fiber.Ctx) error {
 		})
 	}
 
+	likedAt := time.Now()
 	eventLike := models.EventLike{
-		UserID:  user.UserID,
-		EventID: eventID,
+		UserID:    user.UserID,
+		EventID:   eventID,
+		CreatedAt: &likedAt,
 	}
 
 	if err := config.DB.Create(&eventLike).Error; err != nil {
@@ -944,386 +1128,17 @@ func MyLikedEvent(c *fiber.Ctx) error {
 		})
 	}
 
-	var number_of_likes = len(userFigure.LikedEvents)
-	return c.JSON(fiber.Map{
-		"message":         "Successfully",
-		"number_of_likes": number_of_likes,
-		"liked_event":     userFigure.LikedEvents,
-	})
-
-}
-
-func ScheduleEventEnd(db *gorm.DB, event models.Event) {
-	durationEnd := time.Until(event.DateEnd)
-	durationStart := time.Until(event.DateStart)
-
-	go func() {
-		if durationStart > 0 {
-			log.Println("Event " + event.Name + " ---START in " + fmt.Sprintf("%.2f", durationStart.Hours()) + " hours")
-			time.Sleep(durationStart)
-		}
-
-		if err := db.Model(&event).
-			Where("event_id = ?", event.EventID).
-			Update("status", "active").Error; err != nil {
-
-			log.Println("Failed to update event start:", err)
-		}
-	}()
-
-	go func() {
-		if durationEnd > 0 {
-			log.Println("Event " + event.Name + " ---END in " + fmt.Sprintf("%.2f", durationEnd.Hours()) + " hours")
-			time.Sleep(durationEnd)
-		}
-
-		if err := db.Model(&event).
-			Where("event_id = ?", event.EventID).
-			Update("status", "ended").Error; err != nil {
-
-			log.Println("Failed to update event end:", err)
-		}
-	}()
-}
-
-func InitialScheduleEventEnd(db *gorm.DB) error {
-	var events []models.Event
-	if err := db.Model(&models.Event{}).Find(&events).Error; err != nil {
-		return err
-	}
-
-	var count uint = 0
-	for _, event := range events {
-		if event.Status == "approved" || event.Status == "active" {
-			ScheduleEventEnd(db, event)
-			count++
-		}
-
-	}
-	log.Println(" --  Start Goroutine for " + strconv.Itoa(int(count)) + " events")
-	return nil
-}
-
-func GetEventCategories(c *fiber.Ctx) error {
-
-	var allCategory []models.EventCategory
-	if err := config.DB.Model(&allCategory).Preload("ChildEventCategory").Find(&allCategory).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Event not found",
-		})
-	}
-
-	return c.JSON(fiber.Map{
-		"event_category": allCategory,
-	})
-}
-
-type ChildEventCategoryRequest struct {
-	ParentCategoryID       string `json:"event_category_id"`
-	ChildEventCategoryName string `json:"child_event_category_name"`
-}
-
-func AddEventCategory(c *fiber.Ctx) error {
-	var nameReq map[string]interface{}
-
-	if err := c.BodyParser(&nameReq); err != nil {
-		log.Printf("Error parsing : %v", err)
-		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
-	}
-	categoryName, _ := nameReq["category_event"].(string)
-
-	if categoryName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Input is Empty : ",
-		})
-	}
-
-	eventCategory := models.EventCategory{
-		EventCategoryID:   utils.GenerateEventCategoryID(),
-		EventCategoryName: categoryName,
-	}
-
-	if err := config.DB.Model(&models.EventCategory{}).Create(&eventCategory).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to add event category",
-		})
-	}
-
-	return c.JSON(fiber.Map{
-		"message": "Success create event categories: s" + categoryName,
-	})
-
-}
-
-func AddSubEventCategory(c *fiber.Ctx) error {
-	var nameReq map[string]interface{}
-
-	if err := c.BodyParser(&nameReq); err != nil {
-		log.Printf("Error parsing : %v", err)
-		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
-	}
-	categoryName, _ := nameReq["child_category_event"].(string)
-	parentCategoryName, _ := nameReq["category_event"].(string)
-
-	if categoryName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Input is Empty  ",
-		})
-	}
-
-	if parentCategoryName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Input is Empty  ",
-		})
-	}
-	var checkParent models.EventCategory
-	if err := config.DB.Model(&models.EventCategory{}).Where("event_category_name = ?", parentCategoryName).First(&checkParent).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "main category not found",
-		})
-
-	}
-
-	eventSubCategory := models.ChildEventCategory{
-		ChildEventCategoryID:   utils.GenerateChildEventCategoryID(),
-		ParentCategoryID:       checkParent.EventCategoryID,
-		ChildEventCategoryName: categoryName,
-		ParentCategoryName:     parentCategoryName,
-	}
-
-	if err := config.DB.Model(&models.ChildEventCategory{}).Create(&eventSubCategory).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to add event category",
-		})
-	}
-
-	return c.JSON(fiber.Map{
-		"message": "Success create event categories: " + categoryName,
-	})
-
-}
-
-func AddEventCategoryAll(c *fiber.Ctx) error {
-	childCategoryName := c.FormValue("child_categories")
-	EventCategoryName := c.FormValue("event_category")
-
-	var childCategories []ChildEventCategoryRequest
-	if childCategoryName != "" {
-		if err := json.Unmarshal([]byte(childCategoryName), &childCategories); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Invalid ticket categories JSON format: " + err.Error(),
-			})
-		}
-	}
-
-	tx := config.DB.Begin()
-	if tx.Error != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to start transaction",
-		})
-	}
-
-	var EventCategory models.EventCategory
-	if err := config.DB.Model(&models.EventCategory{}).Where(&EventCategory, "event_category_name = ?", EventCategoryName).Error; err != nil {
-
-		eventCategory := models.EventCategory{
-			EventCategoryID:   utils.GenerateEventCategoryID(),
-			EventCategoryName: EventCategoryName,
-		}
-
-		if err := tx.Model(&eventCategory).Create(&eventCategory).Error; err != nil {
-			tx.Rollback()
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to create event category: " + err.Error(),
-			})
-		}
-	}
-
-	if len(childCategories) > 0 {
-		for _, tcReq := range childCategories {
-
-			childCategory := models.ChildEventCategory{
-				ChildEventCategoryID:   utils.GenerateChildEventCategoryID(),
-				ParentCategoryID:       EventCategory.EventCategoryID,
-				ChildEventCategoryName: tcReq.ChildEventCategoryName,
-			}
-
-			if err := tx.Model(&childCategory).Where("child_event_category_name = ?", tcReq.ChildEventCategoryName).First(&childCategory).Error; err != nil {
-				tx.Rollback()
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error": "Duplicate child event category: " + err.Error(),
-				})
-			}
-
-			if err := tx.Model(&childCategory).Create(&childCategory).Error; err != nil {
-				tx.Rollback()
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": "Failed to create child event category: " + err.Error(),
-				})
-			}
-
-		}
-
-	}
-
-	if err := tx.Commit().Error; err != nil {
-		tx.Rollback()
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to commit transaction: " + err.Error(),
-		})
-	}
-
-	return c.JSON(fiber.Map{
-		"message": "Success create event categories",
-	})
-
-}
-
-func DeleteSubCategoryEvent(c *fiber.Ctx) error {
-	var deleteReq map[string]interface{}
-
-	if err := c.BodyParser(&deleteReq); err != nil {
-		log.Printf("Error parsing : %v", err)
-		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
-	}
-	deleteName, _ := deleteReq["child_category_event"].(string)
-
-	if err := config.DB.Model(&models.ChildEventCategory{}).Delete(&models.ChildEventCategory{}, "child_event_category_name = ?", deleteName).Error; err != nil {
+	if err := localizeEvents(config.DB, c, userFigure.LikedEvents); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to delete category: " + err.Error(),
+			"error": "Failed to load translations",
 		})
 	}
 
+	var number_of_likes = len(userFigure.LikedEvents)
 	return c.JSON(fiber.Map{
-		"message": "Success delete event categories",
-	})
-
-}
-
-func DeleteCategoryEvent(c *fiber.Ctx) error {
-	var deleteReq map[string]interface{}
-
-	if err := c.BodyParser(&deleteReq); err != nil {
-		log.Printf("Error parsing : %v", err)
-		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
-	}
-	deleteName, _ := deleteReq["category_event"].(string)
-
-	var categoryNameDelete models.EventCategory
-	if err := config.DB.Model(&categoryNameDelete).Preload("ChildEventCategory").Where("event_category_name = ?", deleteName).Find(&categoryNameDelete).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Category event not found",
-		})
-	}
-
-	if len(categoryNameDelete.ChildEventCategory) > 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "You have to delete the child categories first ",
-		})
-	}
-
-	if err := config.DB.Model(&models.EventCategory{}).Delete(&categoryNameDelete, "event_category_id = ?", categoryNameDelete.EventCategoryID).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to delete category: " + err.Error(),
-		})
-	}
-
-	return c.JSON(fiber.Map{
-		"message": "Success delete event categories",
-		"Deleted": categoryNameDelete.EventCategoryName,
+		"message":         "Successfully",
+		"number_of_likes": number_of_likes,
+		"liked_event":     userFigure.LikedEvents,
 	})
 
 }
-
-func InitializeDefaultCategories() error {
-	categories := map[string][]string{
-		"Hiburan":              {"Musik", "Konser", "Festival", "Stand Up Comedy", "Film", "Teater", "K-Pop", "Dance Performance"},
-		"Teknologi":            {"Konferensi Teknologi", "Workshop IT", "Startup", "Software Development", "Artificial Intelligence", "Data Science", "Cybersecurity", "Gaming & Esports"},
-		"Edukasi":              {"Seminar", "Workshop", "Pelatihan", "Webinar", "Bootcamp", "Kelas Online", "Literasi Digital", "Kelas Bisnis"},
-		"Olahraga":             {"Marathon", "Fun Run", "Sepak Bola", "Badminton", "Gym & Fitness", "Yoga", "Esport", "Cycling Event", "Horse Race"},
-		"Bisnis & Profesional": {"Konferensi Bisnis", "Networking", "Karir", "Entrepreneurship", "Leadership", "Startup Meetup", "Investor & Pitching"},
-		"Seni & Budaya":        {"Pameran Seni", "Pentas Budaya", "Fotografi", "Seni Rupa", "Crafting", "Pameran Museum", "Fashion Show"},
-		"Komunitas":            {"Kegiatan Relawan", "Kegiatan Sosial", "Gathering Komunitas", "Komunitas Hobi", "Meetup", "Charity Event"},
-		"Kuliner":              {"Festival Kuliner", "Food Tasting", "Workshop Memasak", "Street Food Event"},
-		"Kesehatan":            {"Seminar Kesehatan", "Medical Check Event", "Workshop Kesehatan Mental", "Donor Darah"},
-		"Agama & Spiritual":    {"Kajian", "Retreat", "Pengajian", "Event Keagamaan", "Meditasi"},
-		"Travel & Outdoor":     {"Camping", "Hiking", "Trip Wisata", "Outdoor Gathering", "Photography Trip"},
-		"Keluarga & Anak":      {"Family Gathering", "Event Anak", "Workshop Parenting", "Pentas Anak"},
-		"Fashion & Beauty":     {"Fashion Expo", "Beauty Class", "Makeup Workshop", "Brand Launching"},
-	}
-
-	var count int64
-	if err := config.DB.Model(&models.EventCategory{}).Count(&count).Error; err != nil {
-		return fmt.Errorf("failed to check existing categories: %w", err)
-	}
-
-	if count > 0 {
-		log.Println("Default event categories already exist")
-		return nil
-	}
-
-	tx := config.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	for categoryName, subcategories := range categories {
-
-		var existingCategory models.EventCategory
-		err := tx.Where("event_category_name = ?", categoryName).First(&existingCategory).Error
-
-		var categoryID string
-
-		if err != nil {
-
-			categoryID = utils.GenerateEventCategoryID()
-			eventCategory := models.EventCategory{
-				EventCategoryID:   categoryID,
-				EventCategoryName: categoryName,
-			}
-
-			if err := tx.Create(&eventCategory).Error; err != nil {
-				tx.Rollback()
-				return fmt.Errorf("failed to create category %s: %w", categoryName, err)
-			}
-			log.Printf("Created main category: %s", categoryName)
-		} else {
-
-			categoryID = existingCategory.EventCategoryID
-			log.Printf("Category already exists: %s", categoryName)
-		}
-
-		for _, subcategoryName := range subcategories {
-
-			var existingSubcategory models.ChildEventCategory
-			err := tx.Where("child_event_category_name = ? AND parent_category_id = ?", subcategoryName, categoryID).First(&existingSubcategory).Error
-
-			if err != nil {
-
-				childEventCategory := models.ChildEventCategory{
-					ChildEventCategoryID:   utils.GenerateChildEventCategoryID(),
-					ParentCategoryID:       categoryID,
-					ParentCategoryName:     categoryName,
-					ChildEventCategoryName: subcategoryName,
-				}
-
-				if err := tx.Create(&childEventCategory).Error; err != nil {
-					tx.Rollback()
-					return fmt.Errorf("failed to create subcategory %s for category %s: %w", subcategoryName, categoryName, err)
-				}
-				log.Printf("Created subcategory: %s -> %s", categoryName, subcategoryName)
-			} else {
-				log.Printf("Subcategory already exists: %s -> %s", categoryName, subcategoryName)
-			}
-		}
-	}
-
-	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	log.Println("Successfully initialized all default categories and subcategories")
-	return nil
-}
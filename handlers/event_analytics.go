@@ -0,0 +1,490 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	analyticsBucketDay  = "day"
+	analyticsBucketHour = "hour"
+	maxAnalyticsBuckets = 2000
+)
+
+// soldTransactionStatuses adalah status transaksi yang dihitung sebagai penjualan (refund tidak mengurangi
+// penjualan pada waktu transaksi terjadi, tetapi dicatat terpisah lewat loadEventRefunds)
+var soldTransactionStatuses = []string{"paid", "partially_refunded", "refunded"}
+
+// comparableEventStatuses adalah status event yang layak dijadikan pembanding pace penjualan
+var comparableEventStatuses = []string{"approved", "active", "ended"}
+
+// salesRow adalah satu detail transaksi yang terjual untuk event
+type salesRow struct {
+	TicketCategoryID string
+	TransactionTime  time.Time
+	Quantity         uint
+	Subtotal         float64
+}
+
+// refundRow adalah bagian detail transaksi yang di-refund, dihitung saat refund dibuat
+// (saat itu juga counter Sold dikurangi)
+type refundRow struct {
+	TicketCategoryID string
+	RefundedAt       time.Time
+	Quantity         uint
+	Subtotal         float64
+}
+
+// checkInRow adalah satu tiket yang sudah check-in
+type checkInRow struct {
+	TicketCategoryID string
+	CheckedInAt      time.Time
+}
+
+// AnalyticsPoint adalah nilai satu bucket waktu. TicketsSold/Revenue adalah penjualan kotor pada
+// waktu transaksi; refund dicatat terpisah pada waktu refund dibuat, dan nilai net-nya
+// sama dengan counter Sold pada laporan event.
+type AnalyticsPoint struct {
+	TicketsSold     int     `json:"tickets_sold"`
+	Revenue         float64 `json:"revenue"`
+	TicketsRefunded int     `json:"tickets_refunded"`
+	RefundAmount    float64 `json:"refund_amount"`
+	NetTicketsSold  int     `json:"net_tickets_sold"`
+	NetRevenue      float64 `json:"net_revenue"`
+	CheckIns        int     `json:"check_ins"`
+}
+
+func (p *AnalyticsPoint) addSale(quantity uint, subtotal float64) {
+	p.TicketsSold += int(quantity)
+	p.Revenue += subtotal
+	p.NetTicketsSold += int(quantity)
+	p.NetRevenue += subtotal
+}
+
+func (p *AnalyticsPoint) addRefund(quantity uint, subtotal float64) {
+	p.TicketsRefunded += int(quantity)
+	p.RefundAmount += subtotal
+	p.NetTicketsSold -= int(quantity)
+	p.NetRevenue -= subtotal
+}
+
+func (p *AnalyticsPoint) add(other AnalyticsPoint) {
+	p.TicketsSold += other.TicketsSold
+	p.Revenue += other.Revenue
+	p.TicketsRefunded += other.TicketsRefunded
+	p.RefundAmount += other.RefundAmount
+	p.NetTicketsSold += other.NetTicketsSold
+	p.NetRevenue += other.NetRevenue
+	p.CheckIns += other.CheckIns
+}
+
+// AnalyticsBucket adalah satu bucket waktu beserta rincian per ticket category
+type AnalyticsBucket struct {
+	Start string `json:"start"`
+	AnalyticsPoint
+	Cumulative AnalyticsPoint             `json:"cumulative"`
+	Categories map[string]*AnalyticsPoint `json:"categories"` // key: ticket_category_id
+}
+
+// SalesPacePoint membandingkan tiket terjual kumulatif pada jarak hari yang sama sebelum event dimulai
+type SalesPacePoint struct {
+	DaysBeforeEvent int  `json:"days_before_event"`
+	Current         *int `json:"current"` // nil untuk hari yang belum terjadi
+	Previous        int  `json:"previous"`
+}
+
+// loadEventSales mengambil detail transaksi terjual untuk event pada rentang [from, to)
+func loadEventSales(db *gorm.DB, eventID string, from, to *time.Time) ([]salesRow, error) {
+	query := db.Table("transaction_details td").
+		Select("td.ticket_category_id, th.transaction_time, td.quantity, td.subtotal").
+		Joins("JOIN ticket_categories tc ON tc.ticket_category_id = td.ticket_category_id").
+		Joins("JOIN transaction_histories th ON th.transaction_id = td.transaction_id").
+		Where("tc.event_id = ? AND th.transaction_status IN ?", eventID, soldTransactionStatuses)
+	if from != nil {
+		query = query.Where("th.transaction_time >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("th.transaction_time < ?", *to)
+	}
+
+	var rows []salesRow
+	err := query.Order("th.transaction_time ASC").Scan(&rows).Error
+	return rows, err
+}
+
+// loadEventRefunds mengambil detail transaksi yang di-refund untuk event pada rentang [from, to)
+func loadEventRefunds(db *gorm.DB, eventID string, from, to time.Time) ([]refundRow, error) {
+	var rows []refundRow
+	err := db.Table("refunds r").
+		Select("td.ticket_category_id, r.created_at AS refunded_at, td.quantity, td.subtotal").
+		Joins("JOIN transaction_details td ON td.transaction_id = r.transaction_id AND td.owner_id = r.owner_id").
+		Joins("JOIN ticket_categories tc ON tc.ticket_category_id = td.ticket_category_id AND tc.event_id = r.event_id").
+		Where("r.event_id = ? AND r.created_at >= ? AND r.created_at < ?", eventID, from, to).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// loadEventCheckIns mengambil waktu check-in tiket event pada rentang [from, to).
+// Tiket yang check-in sebelum kolom checked_in_at ada memakai updated_at sebagai perkiraan.
+func loadEventCheckIns(db *gorm.DB, eventID string, from, to time.Time) ([]checkInRow, error) {
+	var rows []checkInRow
+	err := db.Model(&models.Ticket{}).
+		Select("ticket_category_id, COALESCE(checked_in_at, updated_at) AS checked_in_at").
+		Where("event_id = ? AND status = ?", eventID, "used").
+		Where("COALESCE(checked_in_at, updated_at) >= ? AND COALESCE(checked_in_at, updated_at) < ?", from, to).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// truncateToBucket membulatkan t ke awal bucket pada zona event. Bucket jam dihitung dari instant
+// (bukan jam dinding) supaya jam yang berulang saat DST berakhir tetap menjadi dua bucket,
+// sejalan dengan iterasi nextBucket.
+func truncateToBucket(t time.Time, bucket string, loc *time.Location) time.Time {
+	local := t.In(loc)
+	if bucket == analyticsBucketHour {
+		return local.Add(-time.Duration(local.Minute())*time.Minute -
+			time.Duration(local.Second())*time.Second - time.Duration(local.Nanosecond()))
+	}
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
+
+func nextBucket(t time.Time, bucket string) time.Time {
+	if bucket == analyticsBucketHour {
+		return t.Add(time.Hour)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// defaultAnalyticsRange adalah dari penjualan pertama (atau pembuatan event) sampai sekarang/berakhirnya event
+func defaultAnalyticsRange(db *gorm.DB, event models.Event) (time.Time, time.Time) {
+	from := event.CreatedAt
+	for _, tc := range event.TicketCategories {
+		if !tc.DateTimeStart.IsZero() && tc.DateTimeStart.Before(from) {
+			from = tc.DateTimeStart
+		}
+	}
+	var first struct{ First *time.Time }
+	db.Table("transaction_details td").
+		Select("MIN(th.transaction_time) AS first").
+		Joins("JOIN ticket_categories tc ON tc.ticket_category_id = td.ticket_category_id").
+		Joins("JOIN transaction_histories th ON th.transaction_id = td.transaction_id").
+		Where("tc.event_id = ? AND th.transaction_status IN ?", event.EventID, soldTransactionStatuses).
+		Scan(&first)
+	if first.First != nil && first.First.Before(from) {
+		from = *first.First
+	}
+
+	to := time.Now()
+	if event.DateEnd.Before(to) {
+		to = event.DateEnd
+	}
+	if !to.After(from) {
+		to = from.Add(time.Hour)
+	}
+	return from, to
+}
+
+// cumulativeSoldByDay menghitung tiket terjual per jumlah hari sebelum event dimulai.
+// Penjualan setelah event dimulai dihitung pada hari-H (0).
+func cumulativeSoldByDay(sales []salesRow, dateStart time.Time) map[int]int {
+	perDay := make(map[int]int)
+	for _, row := range sales {
+		days := int(dateStart.Sub(row.TransactionTime).Hours() / 24)
+		if days < 0 {
+			days = 0
+		}
+		perDay[days] += int(row.Quantity)
+	}
+	return perDay
+}
+
+// previousComparableEvent mencari event sebelumnya dari organisasi (atau organizer) yang sama
+func previousComparableEvent(db *gorm.DB, event models.Event) (*models.Event, error) {
+	query := db.Where("event_id <> ? AND date_start < ? AND status IN ?", event.EventID, event.DateStart, comparableEventStatuses)
+	if event.OrganizationID != nil {
+		query = query.Where("organization_id = ?", *event.OrganizationID)
+	} else {
+		query = query.Where("owner_id = ?", event.OwnerID)
+	}
+	var previous models.Event
+	err := query.Order("date_start DESC").First(&previous).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &previous, nil
+}
+
+// salesPaceComparison membandingkan pace penjualan event dengan event pembanding, diselaraskan
+// berdasarkan jumlah hari sebelum masing-masing event dimulai
+func salesPaceComparison(db *gorm.DB, event, previous models.Event, currentSales []salesRow) (fiber.Map, error) {
+	previousSales, err := loadEventSales(db, previous.EventID, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	currentByDay := cumulativeSoldByDay(currentSales, event.DateStart)
+	previousByDay := cumulativeSoldByDay(previousSales, previous.DateStart)
+
+	// Hari sebelum event saat ini; event yang sudah lewat dibandingkan sampai hari-H
+	today := int(event.DateStart.Sub(time.Now()).Hours() / 24)
+	if today < 0 {
+		today = 0
+	}
+
+	maxDays := 0
+	for day := range currentByDay {
+		if day > maxDays {
+			maxDays = day
+		}
+	}
+	for day := range previousByDay {
+		if day > maxDays {
+			maxDays = day
+		}
+	}
+
+	pace := make([]SalesPacePoint, 0, maxDays+1)
+	currentTotal, previousTotal := 0, 0
+	currentToDate, previousToDate := 0, 0
+	for day := maxDays; day >= 0; day-- {
+		currentTotal += currentByDay[day]
+		previousTotal += previousByDay[day]
+		point := SalesPacePoint{DaysBeforeEvent: day, Previous: previousTotal}
+		if day >= today {
+			value := currentTotal
+			point.Current = &value
+			currentToDate, previousToDate = currentTotal, previousTotal
+		}
+		pace = append(pace, point)
+	}
+
+	var paceChange *float64
+	if previousToDate > 0 {
+		change := (float64(currentToDate) - float64(previousToDate)) / float64(previousToDate) * 100
+		paceChange = &change
+	}
+
+	var previousFinal int
+	for _, row := range previousSales {
+		previousFinal += int(row.Quantity)
+	}
+
+	return fiber.Map{
+		"previous_event": fiber.Map{
+			"event_id":   previous.EventID,
+			"name":       previous.Name,
+			"date_start": previous.DateStart,
+			"total_sold": previousFinal,
+		},
+		"days_before_event":      today,
+		"current_sold_to_date":   currentToDate,
+		"previous_sold_same_day": previousToDate,
+		"pace_change_percent":    paceChange,
+		"pace":                   pace,
+	}, nil
+}
+
+// GetEventSalesAnalytics - Time-series penjualan tiket, pendapatan, dan check-in per ticket category.
+// Query: bucket=day|hour, from, to (RFC3339 atau YYYY-MM-DD pada zona event),
+// compare_event_id (default: event sebelumnya dari organizer/organisasi yang sama), compare=false untuk mematikan.
+func GetEventSalesAnalytics(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	user := c.Locals("user").(models.User)
+
+	var event models.Event
+	if err := config.DB.Preload("TicketCategories").Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+	if !canAccessEvent(config.DB, user, event, permReportView) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to view this report",
+		})
+	}
+
+	bucket := c.Query("bucket", analyticsBucketDay)
+	if bucket != analyticsBucketDay && bucket != analyticsBucketHour {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "bucket must be either 'day' or 'hour'",
+		})
+	}
+
+	loc := utils.TimeZoneOrDefault(event.TimeZone)
+	from, to := defaultAnalyticsRange(config.DB, event)
+	if value := c.Query("from"); value != "" {
+		t, err := parseQueryDate(value, false, loc)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid from format. Use RFC3339 or YYYY-MM-DD",
+			})
+		}
+		from = t
+	}
+	if value := c.Query("to"); value != "" {
+		t, err := parseQueryDate(value, true, loc)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid to format. Use RFC3339 or YYYY-MM-DD",
+			})
+		}
+		to = t
+	}
+	if !to.After(from) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "to must be after from",
+		})
+	}
+
+	// Bucket mencakup seluruh rentang, termasuk bucket parsial di kedua ujung
+	start := truncateToBucket(from, bucket, loc)
+	end := nextBucket(truncateToBucket(to, bucket, loc), bucket)
+	if to.Equal(truncateToBucket(to, bucket, loc)) {
+		end = to.In(loc)
+	}
+
+	var buckets []*AnalyticsBucket
+	index := make(map[int64]*AnalyticsBucket)
+	for t := start; t.Before(end); t = nextBucket(t, bucket) {
+		if len(buckets) >= maxAnalyticsBuckets {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Range too large: at most %d buckets per request, use a larger bucket or a shorter range", maxAnalyticsBuckets),
+			})
+		}
+		b := &AnalyticsBucket{Start: t.Format(time.RFC3339), Categories: make(map[string]*AnalyticsPoint)}
+		for _, tc := range event.TicketCategories {
+			b.Categories[tc.TicketCategoryID] = &AnalyticsPoint{}
+		}
+		buckets = append(buckets, b)
+		index[t.Unix()] = b
+	}
+
+	sales, err := loadEventSales(config.DB, event.EventID, &from, &to)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch sales",
+		})
+	}
+	refunds, err := loadEventRefunds(config.DB, event.EventID, from, to)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch refunds",
+		})
+	}
+	checkIns, err := loadEventCheckIns(config.DB, event.EventID, from, to)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch check-ins",
+		})
+	}
+
+	var totals AnalyticsPoint
+	for _, row := range sales {
+		b, ok := index[truncateToBucket(row.TransactionTime, bucket, loc).Unix()]
+		if !ok {
+			continue
+		}
+		b.addSale(row.Quantity, row.Subtotal)
+		if point, ok := b.Categories[row.TicketCategoryID]; ok {
+			point.addSale(row.Quantity, row.Subtotal)
+		}
+		totals.addSale(row.Quantity, row.Subtotal)
+	}
+	for _, row := range refunds {
+		b, ok := index[truncateToBucket(row.RefundedAt, bucket, loc).Unix()]
+		if !ok {
+			continue
+		}
+		b.addRefund(row.Quantity, row.Subtotal)
+		if point, ok := b.Categories[row.TicketCategoryID]; ok {
+			point.addRefund(row.Quantity, row.Subtotal)
+		}
+		totals.addRefund(row.Quantity, row.Subtotal)
+	}
+	for _, row := range checkIns {
+		b, ok := index[truncateToBucket(row.CheckedInAt, bucket, loc).Unix()]
+		if !ok {
+			continue
+		}
+		b.CheckIns++
+		if point, ok := b.Categories[row.TicketCategoryID]; ok {
+			point.CheckIns++
+		}
+		totals.CheckIns++
+	}
+
+	var running AnalyticsPoint
+	for _, b := range buckets {
+		running.add(b.AnalyticsPoint)
+		b.Cumulative = running
+	}
+
+	categories := make([]fiber.Map, 0, len(event.TicketCategories))
+	for _, tc := range event.TicketCategories {
+		categories = append(categories, fiber.Map{
+			"ticket_category_id": tc.TicketCategoryID,
+			"name":               tc.Name,
+			"price":              tc.Price,
+			"quota":              tc.Quota,
+		})
+	}
+
+	response := fiber.Map{
+		"event_id":   event.EventID,
+		"time_zone":  loc.String(),
+		"bucket":     bucket,
+		"from":       utils.FormatLocal(from, loc),
+		"to":         utils.FormatLocal(to, loc),
+		"categories": categories,
+		"series":     buckets,
+		"totals":     totals,
+	}
+
+	if c.QueryBool("compare", true) {
+		var previous *models.Event
+		if compareID := c.Query("compare_event_id"); compareID != "" {
+			var other models.Event
+			if err := config.DB.Where("event_id = ?", compareID).First(&other).Error; err != nil {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": "Comparison event not found",
+				})
+			}
+			if !canAccessEvent(config.DB, user, other, permReportView) {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error": "Not authorized to view the comparison event",
+				})
+			}
+			previous = &other
+		} else if previous, err = previousComparableEvent(config.DB, event); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch comparison event",
+			})
+		}
+
+		if previous != nil {
+			// Pace memakai seluruh penjualan event ini, bukan hanya rentang yang diminta
+			allSales, err := loadEventSales(config.DB, event.EventID, nil, nil)
+			if err == nil {
+				response["comparison"], err = salesPaceComparison(config.DB, event, *previous, allSales)
+			}
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "Failed to compute sales comparison",
+				})
+			}
+		} else {
+			response["comparison"] = nil
+		}
+	}
+
+	return c.JSON(response)
+}
@@ -0,0 +1,431 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+const defaultRefundWindowDays = 14
+
+type CancelEventRequest struct {
+	Reason string `json:"reason"`
+}
+
+type PostponeEventRequest struct {
+	DateStart        string `json:"date_start"`
+	DateEnd          string `json:"date_end"`
+	Reason           string `json:"reason"`
+	RefundWindowDays int    `json:"refund_window_days"`
+}
+
+type PostponementResponseRequest struct {
+	Decision string `json:"decision"` // keep, refund
+}
+
+// CancelEvent - Membatalkan event: semua tiket dibatalkan, transaksi paid di-refund,
+// dan pemegang tiket mendapat notifikasi. Data event tetap disimpan (tidak dihapus).
+func CancelEvent(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req CancelEventRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if req.Reason == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "reason is required",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	var event models.Event
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("event_id = ?", c.Params("id")).
+		First(&event).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if !canAccessEvent(config.DB, user, event, permEventManage) {
+		tx.Rollback()
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to cancel this event",
+		})
+	}
+
+	if event.Status == "cancelled" || event.Status == "ended" {
+		tx.Rollback()
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Event is already " + event.Status,
+		})
+	}
+
+	// Ambil pemegang tiket sebelum status tiket diubah
+	holderIDs, err := eventTicketHolderIDs(tx, event.EventID, []string{"active", "pending", "used"})
+	if err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch ticket holders",
+		})
+	}
+
+	now := time.Now()
+	previousStatus := event.Status
+	if err := tx.Model(&models.Event{}).
+		Where("event_id = ?", event.EventID).
+		Updates(map[string]interface{}{
+			"status":              "cancelled",
+			"cancelled_at":        &now,
+			"cancellation_reason": req.Reason,
+			"updated_at":          now,
+		}).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to cancel event",
+		})
+	}
+
+	refunds, err := refundEventOrders(tx, event.EventID, "", "event_cancelled", "cancelled")
+	if err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create refunds: " + err.Error(),
+		})
+	}
+
+	// Tiket komplimen dan tiket pending juga tidak berlaku lagi
+	if err := tx.Model(&models.Ticket{}).
+		Where("event_id = ? AND status IN ?", event.EventID, []string{"active", "pending"}).
+		Updates(map[string]interface{}{"status": "cancelled", "updated_at": now}).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to cancel tickets",
+		})
+	}
+
+	// Keluarkan tiket event ini dari cart user
+	if err := tx.Where("ticket_category_id IN (?)",
+		tx.Model(&models.TicketCategory{}).Select("ticket_category_id").Where("event_id = ?", event.EventID),
+	).Delete(&models.Cart{}).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to clear carts",
+		})
+	}
+
+	var refundTotal float64
+	for _, refund := range refunds {
+		refundTotal += refund.Amount
+	}
+
+	if err := recordAudit(tx, c, user.UserID, "event.cancel", "event", event.EventID, fiber.Map{
+		"reason":          req.Reason,
+		"previous_status": previousStatus,
+		"refund_count":    len(refunds),
+		"refund_total":    refundTotal,
+		"holders":         len(holderIDs),
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	SyncEventLifecycleJobs(config.DB, event.EventID)
+
+	notifyUsers(config.DB, holderIDs, "event_cancelled", event.Name+" has been cancelled",
+		"The organizer cancelled this event: "+req.Reason+". Your tickets are no longer valid and paid orders will be refunded automatically.",
+		event.EventID)
+
+	return c.JSON(fiber.Map{
+		"message":      "Event cancelled",
+		"event_id":     event.EventID,
+		"refund_count": len(refunds),
+		"refund_total": refundTotal,
+		"notified":     len(holderIDs),
+	})
+}
+
+// PostponeEvent - Memindahkan tanggal event live. Pemegang tiket diberi jendela waktu
+// untuk tetap memakai tiketnya atau meminta refund.
+func PostponeEvent(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req PostponeEventRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+
+	if req.DateStart == "" || req.DateEnd == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "date_start and date_end are required",
+		})
+	}
+	if req.Reason == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "reason is required",
+		})
+	}
+	if req.RefundWindowDays == 0 {
+		req.RefundWindowDays = defaultRefundWindowDays
+	}
+	if req.RefundWindowDays < 1 || req.RefundWindowDays > 90 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "refund_window_days must be between 1 and 90",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	var event models.Event
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("event_id = ?", c.Params("id")).
+		First(&event).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if !canAccessEvent(config.DB, user, event, permEventManage) {
+		tx.Rollback()
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to postpone this event",
+		})
+	}
+
+	if !containsString(liveEventStatuses, event.Status) {
+		tx.Rollback()
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Only approved or active events can be postponed",
+		})
+	}
+
+	// Tanggal tanpa offset diartikan sebagai jam lokal zona event
+	loc := utils.TimeZoneOrDefault(event.TimeZone)
+	dateStart, err := utils.ParseEventTime(req.DateStart, loc)
+	if err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid date_start format",
+		})
+	}
+	dateEnd, err := utils.ParseEventTime(req.DateEnd, loc)
+	if err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid date_end format",
+		})
+	}
+	if !dateEnd.After(dateStart) || !dateStart.After(time.Now()) {
+		tx.Rollback()
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "New dates must be in the future and date_end must be after date_start",
+		})
+	}
+
+	now := time.Now()
+	refundWindowEnd := now.AddDate(0, 0, req.RefundWindowDays)
+	updateData := map[string]interface{}{
+		"date_start":        dateStart,
+		"date_end":          dateEnd,
+		"postponed_at":      &now,
+		"postpone_reason":   req.Reason,
+		"refund_window_end": &refundWindowEnd,
+		"updated_at":        now,
+	}
+	if event.OriginalDateStart == nil {
+		updateData["original_date_start"] = event.DateStart
+	}
+
+	if err := tx.Model(&models.Event{}).Where("event_id = ?", event.EventID).Updates(updateData).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to postpone event",
+		})
+	}
+
+	holderIDs, err := eventTicketHolderIDs(tx, event.EventID, []string{"active"})
+	if err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch ticket holders",
+		})
+	}
+
+	if err := recordAudit(tx, c, user.UserID, "event.postpone", "event", event.EventID, fiber.Map{
+		"reason":             req.Reason,
+		"old_date_start":     event.DateStart,
+		"old_date_end":       event.DateEnd,
+		"new_date_start":     dateStart,
+		"new_date_end":       dateEnd,
+		"refund_window_days": req.RefundWindowDays,
+		"holders":            len(holderIDs),
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	SyncEventLifecycleJobs(config.DB, event.EventID)
+
+	notifyUsers(config.DB, holderIDs, "event_postponed", event.Name+" has been postponed",
+		fmt.Sprintf("New schedule: %s - %s. Reason: %s. Your ticket stays valid; if you cannot attend you can request a refund until %s.",
+			dateStart.In(loc).Format("02 Jan 2006 15:04 MST"), dateEnd.In(loc).Format("02 Jan 2006 15:04 MST"), req.Reason, refundWindowEnd.In(loc).Format("02 Jan 2006")),
+		event.EventID)
+
+	return c.JSON(fiber.Map{
+		"message":           "Event postponed",
+		"event_id":          event.EventID,
+		"date_start":        dateStart,
+		"date_end":          dateEnd,
+		"refund_window_end": refundWindowEnd,
+		"notified":          len(holderIDs),
+	})
+}
+
+// RespondToPostponement - Pemegang tiket memilih tetap datang (keep) atau refund selama jendela refund terbuka
+func RespondToPostponement(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req PostponementResponseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if req.Decision != "keep" && req.Decision != "refund" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "decision must be either 'keep' or 'refund'",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	var event models.Event
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("event_id = ?", c.Params("event_id")).
+		First(&event).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if event.PostponedAt == nil || event.RefundWindowEnd == nil || time.Now().After(*event.RefundWindowEnd) {
+		tx.Rollback()
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "There is no open refund window for this event",
+		})
+	}
+	if !containsString(liveEventStatuses, event.Status) {
+		tx.Rollback()
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Event is no longer live",
+		})
+	}
+
+	var activeTickets int64
+	if err := tx.Model(&models.Ticket{}).
+		Where("event_id = ? AND owner_id = ? AND status = ? AND is_complimentary = ?", event.EventID, user.UserID, "active", false).
+		Count(&activeTickets).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch tickets",
+		})
+	}
+	if activeTickets == 0 {
+		tx.Rollback()
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "You have no refundable tickets for this event",
+		})
+	}
+
+	response := fiber.Map{
+		"message":  "Your ticket stays valid for the new schedule",
+		"decision": req.Decision,
+	}
+	details := fiber.Map{"decision": req.Decision, "tickets": activeTickets}
+
+	if req.Decision == "refund" {
+		refunds, err := refundEventOrders(tx, event.EventID, user.UserID, "event_postponed", "refunded")
+		if err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to create refund: " + err.Error(),
+			})
+		}
+		var amount float64
+		for _, refund := range refunds {
+			amount += refund.Amount
+		}
+		details["refund_count"] = len(refunds)
+		details["refund_total"] = amount
+		response = fiber.Map{
+			"message":      "Refund requested. Your tickets for this event have been cancelled",
+			"decision":     req.Decision,
+			"refunds":      refunds,
+			"refund_total": amount,
+		}
+	}
+
+	if err := recordAudit(tx, c, user.UserID, "ticket.postponement_"+req.Decision, "event", event.EventID, details); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(response)
+}
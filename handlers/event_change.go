@@ -0,0 +1,843 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Status event yang dianggap live: tiket tetap dijual selama change request diproses
+var liveEventStatuses = []string{"approved", "active"}
+
+// Field event yang boleh diajukan lewat change request, urut sesuai form
+var changeableEventFields = []string{
+	"name", "description", "rules", "location", "venue", "district",
+	"category_id", "child_category_id", "time_zone", "date_start", "date_end", "image", "flyer",
+}
+
+const (
+	changeFieldCoordinates    = "coordinates"
+	changeFieldTicketCategory = "ticket_category"
+)
+
+type ChangeRequestReview struct {
+	Approve    []string `json:"approve"`
+	Reject     []string `json:"reject"`
+	ApproveAll bool     `json:"approve_all"`
+	RejectAll  bool     `json:"reject_all"`
+	Comment    string   `json:"comment"`
+}
+
+// SubmitEventChangeRequest - Organizer mengajukan perubahan untuk event yang sudah live.
+// Form sama dengan UpdateEvent; hanya field yang berbeda dari versi live yang dicatat sebagai item.
+func SubmitEventChangeRequest(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	eventID := c.Params("id")
+
+	var event models.Event
+	if err := config.DB.Preload("TicketCategories").Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if !canAccessEvent(config.DB, user, event, permEventManage) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to update this event",
+		})
+	}
+
+	if !containsString(liveEventStatuses, event.Status) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Change requests are only for live events. Edit pending or rejected events directly",
+		})
+	}
+
+	var pending int64
+	config.DB.Model(&models.EventChangeRequest{}).
+		Where("event_id = ? AND status = ?", event.EventID, "pending").
+		Count(&pending)
+	if pending > 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "This event already has a pending change request",
+		})
+	}
+
+	current := eventFieldValues(event)
+	var items []models.EventChangeRequestItem
+	addItem := func(field, ticketCategoryID, oldValue, newValue string) {
+		items = append(items, models.EventChangeRequestItem{
+			ItemID:           utils.GenerateChangeRequestItemID(),
+			Field:            field,
+			TicketCategoryID: ticketCategoryID,
+			OldValue:         oldValue,
+			NewValue:         newValue,
+			Status:           "pending",
+			CreatedAt:        time.Now(),
+			UpdatedAt:        time.Now(),
+		})
+	}
+
+	// Tanggal tanpa offset diartikan dalam zona waktu baru jika ikut diubah
+	loc := utils.TimeZoneOrDefault(event.TimeZone)
+	if timeZone := c.FormValue("time_zone"); timeZone != "" {
+		newLoc, err := utils.LoadTimeZone(timeZone)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		loc = newLoc
+	}
+
+	formValues := make(map[string]string)
+	for _, field := range changeableEventFields {
+		formValues[field] = c.FormValue(field)
+	}
+
+	// Kategori divalidasi ke master kategori (ID atau nama); item menyimpan ID hasil resolve
+	// supaya tetap benar jika kategori diganti nama sebelum direview
+	categoryInput := firstNonEmpty(formValues["category_id"], c.FormValue("category"))
+	childCategoryInput := firstNonEmpty(formValues["child_category_id"], c.FormValue("child_category"))
+	categoryResolved := categoryInput != "" || childCategoryInput != ""
+	if categoryResolved {
+		parentCategory, childEventCategory, err := resolveEventUpdateCategory(config.DB, categoryInput, childCategoryInput, event)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		formValues["category_id"], formValues["child_category_id"] = "", ""
+		if parentCategory != nil {
+			formValues["category_id"] = parentCategory.EventCategoryID
+		}
+		if childEventCategory != nil {
+			formValues["child_category_id"] = childEventCategory.ChildEventCategoryID
+		}
+	}
+
+	for _, field := range changeableEventFields {
+		if field == "image" || field == "flyer" {
+			continue
+		}
+		value := formValues[field]
+		// Sub kategori boleh kosong jika kategori induknya diganti
+		if value == current[field] || (value == "" && !(field == "child_category_id" && categoryResolved)) {
+			continue
+		}
+		if field == "time_zone" {
+			value = loc.String()
+			if value == current[field] {
+				continue
+			}
+		}
+		if field == "date_start" || field == "date_end" {
+			t, err := utils.ParseEventTime(value, loc)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid " + field + " format",
+				})
+			}
+			if (field == "date_start" && t.Equal(event.DateStart)) || (field == "date_end" && t.Equal(event.DateEnd)) {
+				continue
+			}
+			value = t.UTC().Format(time.RFC3339)
+		}
+		addItem(field, "", current[field], value)
+	}
+
+	latitude, longitude, err := parseCoordinates(c.FormValue("latitude"), c.FormValue("longitude"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if latitude != nil {
+		addItem(changeFieldCoordinates, "", current[changeFieldCoordinates], formatCoordinates(*latitude, *longitude))
+	}
+
+	// Validasi tanggal terhadap nilai gabungan (baru atau live)
+	dateStart, dateEnd := event.DateStart, event.DateEnd
+	for _, item := range items {
+		t, _ := time.Parse(time.RFC3339, item.NewValue)
+		switch item.Field {
+		case "date_start":
+			dateStart = t
+		case "date_end":
+			dateEnd = t
+		}
+	}
+	if !dateEnd.After(dateStart) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "date_end must be after date_start",
+		})
+	}
+
+	if ticketCategoriesJSON := c.FormValue("ticket_categories"); ticketCategoriesJSON != "" {
+		var ticketCategories []TicketCategoryRequest
+		if err := json.Unmarshal([]byte(ticketCategoriesJSON), &ticketCategories); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid ticket categories JSON format",
+			})
+		}
+
+		byID := make(map[string]models.TicketCategory)
+		for _, tc := range event.TicketCategories {
+			byID[tc.TicketCategoryID] = tc
+		}
+
+		names := make(map[string]bool)
+		for _, req := range ticketCategories {
+			if req.Name == "" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "ticket category name is required",
+				})
+			}
+			if names[req.Name] {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Duplicate ticket category name : " + req.Name,
+				})
+			}
+			names[req.Name] = true
+
+			dateTimeStart, dateTimeEnd, err := parseTicketCategoryDates(req, loc)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			}
+			req.DateTimeStart = dateTimeStart.UTC().Format(time.RFC3339)
+			req.DateTimeEnd = dateTimeEnd.UTC().Format(time.RFC3339)
+
+			newValue, _ := json.Marshal(req)
+			if req.TicketCategoryID == "" {
+				addItem(changeFieldTicketCategory, "", "", string(newValue))
+				continue
+			}
+
+			tc, ok := byID[req.TicketCategoryID]
+			if !ok {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Ticket category " + req.TicketCategoryID + " does not belong to this event",
+				})
+			}
+			// Cek awal; quota dicek ulang saat approve karena penjualan tetap berjalan
+			if err := checkTicketCategoryQuota(tc, req.Quota, req.CompQuota); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			}
+
+			oldReq := ticketCategoryRequestFrom(tc)
+			if oldReq == req {
+				continue
+			}
+			oldValue, _ := json.Marshal(oldReq)
+			addItem(changeFieldTicketCategory, tc.TicketCategoryID, string(oldValue), string(newValue))
+		}
+
+		// Nama kategori baru atau hasil rename tidak boleh bentrok dengan kategori lain yang
+		// tidak ikut diubah di request ini
+		for _, tc := range event.TicketCategories {
+			inRequest := false
+			for _, req := range ticketCategories {
+				if req.TicketCategoryID == tc.TicketCategoryID {
+					inRequest = true
+					break
+				}
+			}
+			if !inRequest && names[tc.Name] {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Duplicate ticket category name : " + tc.Name,
+				})
+			}
+		}
+	}
+
+	// Upload gambar baru, URL lama tetap dipakai sampai item disetujui
+	for _, field := range []string{"image", "flyer"} {
+		fileHeader, err := c.FormFile(field)
+		if err != nil {
+			continue
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			continue
+		}
+		folder := fmt.Sprintf("ticketing-app/events/%s/%ss", user.UserID, field)
+		url, err := config.UploadImage(context.Background(), file, folder)
+		file.Close()
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to upload event " + field,
+			})
+		}
+		addItem(field, "", current[field], url)
+	}
+
+	if len(items) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "No changes detected",
+		})
+	}
+
+	changeRequest := models.EventChangeRequest{
+		ChangeRequestID: utils.GenerateChangeRequestID(),
+		EventID:         event.EventID,
+		RequestedBy:     user.UserID,
+		Status:          "pending",
+		Note:            c.FormValue("note"),
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	for i := range items {
+		items[i].ChangeRequestID = changeRequest.ChangeRequestID
+	}
+	changeRequest.Items = items
+
+	if err := config.DB.Create(&changeRequest).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create change request: " + err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":        "Change request submitted. The live event stays on sale until it is reviewed",
+		"change_request": changeRequest,
+	})
+}
+
+// GetEventChangeRequests - Riwayat change request sebuah event (owner atau admin)
+func GetEventChangeRequests(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	eventID := c.Params("id")
+
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if !canAccessEvent(config.DB, user, event, permEventView) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to view change requests for this event",
+		})
+	}
+
+	var changeRequests []models.EventChangeRequest
+	if err := config.DB.Preload("Items").
+		Where("event_id = ?", event.EventID).
+		Order("created_at DESC").
+		Find(&changeRequests).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch change requests",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":         "Change requests retrieved successfully",
+		"change_requests": changeRequests,
+	})
+}
+
+// GetPendingChangeRequests - Antrian change request untuk admin, yang terlama lebih dulu
+func GetPendingChangeRequests(c *fiber.Ctx) error {
+	var changeRequests []models.EventChangeRequest
+	if err := config.DB.Preload("Items").Preload("Event").Preload("Requester").
+		Where("status = ?", "pending").
+		Order("created_at ASC").
+		Find(&changeRequests).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch change requests",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":         "Pending change requests retrieved successfully",
+		"change_requests": changeRequests,
+	})
+}
+
+// CancelEventChangeRequest - Organizer membatalkan change request yang belum direview
+func CancelEventChangeRequest(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var changeRequest models.EventChangeRequest
+	if err := config.DB.Preload("Event").
+		Where("change_request_id = ? AND event_id = ?", c.Params("request_id"), c.Params("id")).
+		First(&changeRequest).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Change request not found",
+		})
+	}
+
+	if !canAccessEvent(config.DB, user, changeRequest.Event, permEventManage) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to cancel this change request",
+		})
+	}
+
+	if changeRequest.Status != "pending" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Only pending change requests can be cancelled",
+		})
+	}
+
+	result := config.DB.Model(&models.EventChangeRequest{}).
+		Where("change_request_id = ? AND status = ?", changeRequest.ChangeRequestID, "pending").
+		Updates(map[string]interface{}{"status": "cancelled", "updated_at": time.Now()})
+	if result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to cancel change request",
+		})
+	}
+	if result.RowsAffected == 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Change request is already being reviewed",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Change request cancelled",
+	})
+}
+
+// ReviewEventChangeRequest - Admin menyetujui atau menolak item perubahan satu per satu.
+// Item yang belum diputuskan tetap pending; item yang disetujui langsung diterapkan ke event live.
+func ReviewEventChangeRequest(c *fiber.Ctx) error {
+	admin := c.Locals("user").(models.User)
+
+	var req ChangeRequestReview
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if req.ApproveAll && req.RejectAll {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "approve_all and reject_all cannot be used together",
+		})
+	}
+
+	// Alamat baru di-geocode sebelum transaksi dibuka karena memanggil layanan eksternal
+	geocoded := geocodeChangeRequestAddress(c.Params("request_id"), req)
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	var changeRequest models.EventChangeRequest
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("change_request_id = ?", c.Params("request_id")).
+		First(&changeRequest).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Change request not found",
+		})
+	}
+	if changeRequest.Status != "pending" {
+		tx.Rollback()
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Change request has already been reviewed",
+		})
+	}
+
+	var event models.Event
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("event_id = ?", changeRequest.EventID).
+		First(&event).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	var items []models.EventChangeRequestItem
+	if err := tx.Where("change_request_id = ?", changeRequest.ChangeRequestID).
+		Order("created_at ASC").
+		Find(&items).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load change request items",
+		})
+	}
+
+	decisions := make(map[string]string)
+	for _, id := range req.Approve {
+		decisions[id] = "approved"
+	}
+	for _, id := range req.Reject {
+		if decisions[id] == "approved" {
+			tx.Rollback()
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Item " + id + " cannot be both approved and rejected",
+			})
+		}
+		decisions[id] = "rejected"
+	}
+
+	// Hanya item yang diputuskan pada review ini yang diterapkan dan disimpan; item dari review
+	// sebelumnya sudah diterapkan dan statusnya tidak diubah lagi
+	known := make(map[string]bool)
+	var decidedIndexes []int
+	for i := range items {
+		known[items[i].ItemID] = true
+		if items[i].Status != "pending" {
+			continue
+		}
+		switch {
+		case req.ApproveAll:
+			items[i].Status = "approved"
+		case req.RejectAll:
+			items[i].Status = "rejected"
+		case decisions[items[i].ItemID] != "":
+			items[i].Status = decisions[items[i].ItemID]
+		default:
+			continue
+		}
+		decidedIndexes = append(decidedIndexes, i)
+	}
+	for id := range decisions {
+		if !known[id] {
+			tx.Rollback()
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Item " + id + " does not belong to this change request",
+			})
+		}
+	}
+
+	decided := make([]models.EventChangeRequestItem, len(decidedIndexes))
+	for i, index := range decidedIndexes {
+		decided[i] = items[index]
+	}
+	if err := applyApprovedChanges(tx, event, decided, geocoded); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to apply changes: " + err.Error(),
+		})
+	}
+	for i, index := range decidedIndexes {
+		items[index] = decided[i]
+	}
+
+	for _, item := range decided {
+		if err := tx.Model(&models.EventChangeRequestItem{}).
+			Where("item_id = ?", item.ItemID).
+			Updates(map[string]interface{}{
+				"status":             item.Status,
+				"reason":             item.Reason,
+				"ticket_category_id": item.TicketCategoryID,
+				"updated_at":         time.Now(),
+			}).Error; err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update change request items",
+			})
+		}
+	}
+
+	status := changeRequestStatus(items)
+	requestData := map[string]interface{}{
+		"status":     status,
+		"updated_at": time.Now(),
+	}
+	if req.Comment != "" {
+		requestData["review_comment"] = req.Comment
+	}
+	if status != "pending" {
+		now := time.Now()
+		requestData["reviewed_by"] = admin.UserID
+		requestData["reviewed_at"] = &now
+	}
+	if err := tx.Model(&models.EventChangeRequest{}).
+		Where("change_request_id = ?", changeRequest.ChangeRequestID).
+		Updates(requestData).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update change request",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	// Perubahan tanggal yang disetujui menggeser job start/end event
+	SyncEventLifecycleJobs(config.DB, event.EventID)
+
+	var reviewed models.EventChangeRequest
+	config.DB.Preload("Items").Where("change_request_id = ?", changeRequest.ChangeRequestID).First(&reviewed)
+
+	return c.JSON(fiber.Map{
+		"message":        "Change request reviewed",
+		"change_request": reviewed,
+	})
+}
+
+// geocodeChangeRequestAddress menghitung koordinat alamat yang akan berlaku jika review disetujui.
+// Hasilnya dicocokkan ulang di applyApprovedChanges; alamat yang berubah sejak itu tidak mendapat koordinat.
+func geocodeChangeRequestAddress(changeRequestID string, req ChangeRequestReview) map[string]geoPoint {
+	geocoded := map[string]geoPoint{}
+	if req.RejectAll {
+		return geocoded
+	}
+
+	var changeRequest models.EventChangeRequest
+	if err := config.DB.Preload("Items", "status = ?", "pending").
+		Where("change_request_id = ? AND status = ?", changeRequestID, "pending").
+		First(&changeRequest).Error; err != nil {
+		return geocoded
+	}
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", changeRequest.EventID).First(&event).Error; err != nil {
+		return geocoded
+	}
+
+	address := map[string]string{"venue": event.Venue, "location": event.Location, "district": event.District}
+	addressChanged := false
+	for _, item := range changeRequest.Items {
+		if !req.ApproveAll && !containsString(req.Approve, item.ItemID) {
+			continue
+		}
+		switch item.Field {
+		case changeFieldCoordinates:
+			// Koordinat manual dipakai apa adanya
+			return geocoded
+		case "venue", "location", "district":
+			address[item.Field] = firstNonEmpty(item.NewValue, address[item.Field])
+			addressChanged = true
+		}
+	}
+	if addressChanged {
+		geocodeCached(geocoded, address["venue"], address["location"], address["district"])
+	}
+	return geocoded
+}
+
+// applyApprovedChanges menerapkan item berstatus approved ke event live.
+// Item yang tidak lagi valid (misal quota sudah di bawah Sold) ditandai failed beserta alasannya.
+// Koordinat alamat baru diambil dari geocoded yang diisi sebelum transaksi dibuka.
+func applyApprovedChanges(tx *gorm.DB, event models.Event, items []models.EventChangeRequestItem, geocoded map[string]geoPoint) error {
+	updateData := map[string]interface{}{}
+	addressChanged := false
+	categoryItems := make(map[string]*models.EventChangeRequestItem)
+	dateStart, dateEnd := event.DateStart, event.DateEnd
+	loc := utils.TimeZoneOrDefault(event.TimeZone)
+
+	for i := range items {
+		item := &items[i]
+		if item.Status != "approved" {
+			continue
+		}
+
+		switch item.Field {
+		case "date_start", "date_end":
+			t, err := utils.ParseEventTime(item.NewValue, loc)
+			if err != nil {
+				item.Status, item.Reason = "failed", "invalid date value"
+				continue
+			}
+			if item.Field == "date_start" {
+				dateStart = t
+			} else {
+				dateEnd = t
+			}
+			updateData[item.Field] = t
+		case changeFieldCoordinates:
+			var lat, lng float64
+			if _, err := fmt.Sscanf(item.NewValue, "%f,%f", &lat, &lng); err != nil {
+				item.Status, item.Reason = "failed", "invalid coordinates value"
+				continue
+			}
+			updateData["latitude"] = lat
+			updateData["longitude"] = lng
+		case "category_id", "child_category_id", "category", "child_category":
+			// Item lama menyimpan nama kategori; resolve menerima ID maupun nama
+			categoryItems[strings.TrimSuffix(item.Field, "_id")] = item
+		case changeFieldTicketCategory:
+			var req TicketCategoryRequest
+			if err := json.Unmarshal([]byte(item.NewValue), &req); err != nil {
+				item.Status, item.Reason = "failed", "invalid ticket category value"
+				continue
+			}
+			var err error
+			if item.TicketCategoryID == "" {
+				var created models.TicketCategory
+				created, err = createTicketCategory(tx, event.EventID, req, loc)
+				item.TicketCategoryID = created.TicketCategoryID
+			} else {
+				err = updateTicketCategoryInPlace(tx, item.TicketCategoryID, req, loc)
+			}
+			if errors.Is(err, errInvalidTicketCategory) {
+				item.Status, item.Reason = "failed", err.Error()
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		default:
+			updateData[item.Field] = item.NewValue
+			if item.Field == "location" || item.Field == "venue" || item.Field == "district" {
+				addressChanged = true
+			}
+		}
+	}
+
+	// Kategori divalidasi ulang karena bisa saja dinonaktifkan admin sejak diajukan
+	if len(categoryItems) > 0 {
+		var categoryInput, childCategoryInput string
+		if item, ok := categoryItems["category"]; ok {
+			categoryInput = item.NewValue
+		}
+		if item, ok := categoryItems["child_category"]; ok {
+			childCategoryInput = item.NewValue
+		}
+		parentCategory, childEventCategory, err := resolveEventUpdateCategory(tx, categoryInput, childCategoryInput, event)
+		if errors.Is(err, errInvalidEventCategory) {
+			for _, item := range categoryItems {
+				item.Status, item.Reason = "failed", err.Error()
+			}
+		} else if err != nil {
+			return err
+		} else {
+			for column, value := range eventCategoryColumns(parentCategory, childEventCategory) {
+				updateData[column] = value
+			}
+		}
+	}
+
+	// Perubahan tanggal yang membuat date_end <= date_start tidak diterapkan
+	if !dateEnd.After(dateStart) {
+		delete(updateData, "date_start")
+		delete(updateData, "date_end")
+		for i := range items {
+			if items[i].Status == "approved" && (items[i].Field == "date_start" || items[i].Field == "date_end") {
+				items[i].Status, items[i].Reason = "failed", "date_end must be after date_start"
+			}
+		}
+	}
+
+	if len(updateData) == 0 {
+		return nil
+	}
+
+	if _, ok := updateData["latitude"]; !ok && addressChanged {
+		venue, _ := updateData["venue"].(string)
+		location, _ := updateData["location"].(string)
+		district, _ := updateData["district"].(string)
+		point := geocoded[geoAddressKey(
+			firstNonEmpty(venue, event.Venue),
+			firstNonEmpty(location, event.Location),
+			firstNonEmpty(district, event.District),
+		)]
+		if point.lat != nil {
+			updateData["latitude"] = *point.lat
+			updateData["longitude"] = *point.lng
+		}
+	}
+
+	// Alamat yang diubah manual tidak lagi mengikuti venue direktori
+	if addressChanged {
+		updateData["venue_id"] = nil
+	}
+
+	updateData["updated_at"] = time.Now()
+	if err := tx.Model(&models.Event{}).Where("event_id = ?", event.EventID).Updates(updateData).Error; err != nil {
+		return err
+	}
+	// Cover galeri mengikuti image yang disetujui
+	if image, ok := updateData["image"].(string); ok {
+		if err := markGalleryCover(tx, event.EventID, image); err != nil {
+			return err
+		}
+	}
+	return assignEventSlug(tx, event.EventID)
+}
+
+func changeRequestStatus(items []models.EventChangeRequestItem) string {
+	approved, rejected := 0, 0
+	for _, item := range items {
+		switch item.Status {
+		case "pending":
+			return "pending"
+		case "approved":
+			approved++
+		default:
+			rejected++
+		}
+	}
+
+	switch {
+	case rejected == 0:
+		return "approved"
+	case approved == 0:
+		return "rejected"
+	default:
+		return "partially_approved"
+	}
+}
+
+func eventFieldValues(event models.Event) map[string]string {
+	values := map[string]string{
+		"name":              event.Name,
+		"description":       event.Description,
+		"rules":             event.Rules,
+		"location":          event.Location,
+		"venue":             event.Venue,
+		"district":          event.District,
+		"category_id":       "",
+		"child_category_id": "",
+		"time_zone":         utils.TimeZoneOrDefault(event.TimeZone).String(),
+		"date_start":        event.DateStart.UTC().Format(time.RFC3339),
+		"date_end":          event.DateEnd.UTC().Format(time.RFC3339),
+		"image":             event.Image,
+		"flyer":             event.Flyer,
+	}
+	if event.CategoryID != nil {
+		values["category_id"] = *event.CategoryID
+	}
+	if event.ChildCategoryID != nil {
+		values["child_category_id"] = *event.ChildCategoryID
+	}
+	if event.Latitude != nil && event.Longitude != nil {
+		values[changeFieldCoordinates] = formatCoordinates(*event.Latitude, *event.Longitude)
+	}
+	return values
+}
+
+func formatCoordinates(lat, lng float64) string {
+	return strconv.FormatFloat(lat, 'f', 7, 64) + "," + strconv.FormatFloat(lng, 'f', 7, 64)
+}
+
+func ticketCategoryRequestFrom(tc models.TicketCategory) TicketCategoryRequest {
+	return TicketCategoryRequest{
+		TicketCategoryID: tc.TicketCategoryID,
+		Name:             tc.Name,
+		Price:            tc.Price,
+		Quota:            tc.Quota,
+		CompQuota:        tc.CompQuota,
+		IsHidden:         tc.IsHidden,
+		Description:      tc.Description,
+		DateTimeStart:    tc.DateTimeStart.UTC().Format(time.RFC3339),
+		DateTimeEnd:      tc.DateTimeEnd.UTC().Format(time.RFC3339),
+	}
+}
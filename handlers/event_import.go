@@ -0,0 +1,803 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	maxImportEvents        = 500
+	importModeAllOrNothing = "all_or_nothing"
+	importModePerRow       = "per_row"
+)
+
+// eventImportColumns adalah kolom CSV impor/ekspor. Satu baris = satu ticket category; baris dengan
+// ref yang sama digabung menjadi satu event dan kolom event diambil dari baris pertamanya.
+var eventImportColumns = []string{
+	"ref", "name", "description", "rules", "date_start", "date_end", "time_zone",
+	"location", "venue", "venue_id", "district", "latitude", "longitude",
+	"category", "child_category", "image_url", "flyer_url", "gallery_urls",
+	"ticket_name", "ticket_price", "ticket_quota", "ticket_comp_quota", "ticket_is_hidden",
+	"ticket_description", "ticket_sale_start", "ticket_sale_end",
+}
+
+// EventImportRecord adalah satu event pada file impor JSON (dan hasil pengelompokan baris CSV).
+// Media direferensikan lewat URL dan disalin ke storage saat impor dijalankan.
+type EventImportRecord struct {
+	Ref              string                  `json:"ref"` // ID event di platform asal
+	Name             string                  `json:"name"`
+	Description      string                  `json:"description"`
+	Rules            string                  `json:"rules"`
+	DateStart        string                  `json:"date_start"`
+	DateEnd          string                  `json:"date_end"`
+	TimeZone         string                  `json:"time_zone"`
+	Location         string                  `json:"location"`
+	Venue            string                  `json:"venue"`
+	VenueID          string                  `json:"venue_id"`
+	District         string                  `json:"district"`
+	Latitude         *float64                `json:"latitude"`
+	Longitude        *float64                `json:"longitude"`
+	Category         string                  `json:"category"`
+	ChildCategory    string                  `json:"child_category"`
+	ImageURL         string                  `json:"image_url"`
+	FlyerURL         string                  `json:"flyer_url"`
+	GalleryURLs      []string                `json:"gallery_urls"`
+	TicketCategories []TicketCategoryRequest `json:"ticket_categories"`
+
+	rows        []int    // nomor baris CSV asal, untuk laporan
+	parseErrors []string // sel CSV yang tidak bisa dibaca, dilaporkan saat validasi
+}
+
+// EventImportResult adalah status satu event di laporan impor
+type EventImportResult struct {
+	Index   int      `json:"index"`
+	Ref     string   `json:"ref,omitempty"`
+	Rows    []int    `json:"rows,omitempty"`
+	Name    string   `json:"name"`
+	Status  string   `json:"status"` // valid, invalid, created, failed, not_imported
+	Errors  []string `json:"errors,omitempty"`
+	EventID string   `json:"event_id,omitempty"`
+}
+
+// importedEvent adalah record yang lolos validasi beserta event dan ticket category siap simpan
+type importedEvent struct {
+	record           EventImportRecord
+	event            models.Event
+	ticketCategories []models.TicketCategory
+	result           *EventImportResult
+}
+
+// readEventImportCSV mengelompokkan baris CSV menjadi record event. Sel yang tidak valid dicatat
+// pada record-nya (bukan menggagalkan seluruh file) supaya laporan menandai event itu invalid.
+func readEventImportCSV(r io.Reader) ([]EventImportRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("missing header row: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"name", "date_start", "date_end"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing required column %q", required)
+		}
+	}
+
+	var records []EventImportRecord
+	byRef := make(map[string]int)
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Nomor baris fisik, tetap benar jika ada sel multi-baris
+		line, _ := reader.FieldPos(0)
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		if strings.Join(row, "") == "" {
+			continue
+		}
+
+		// Tanpa ref, baris dengan nama dan date_start sama dianggap event yang sama
+		key := get("ref")
+		if key == "" {
+			key = get("name") + "|" + get("date_start")
+		}
+		index, ok := byRef[key]
+		if !ok {
+			record := EventImportRecord{
+				Ref:           get("ref"),
+				Name:          get("name"),
+				Description:   get("description"),
+				Rules:         get("rules"),
+				DateStart:     get("date_start"),
+				DateEnd:       get("date_end"),
+				TimeZone:      get("time_zone"),
+				Location:      get("location"),
+				Venue:         get("venue"),
+				VenueID:       get("venue_id"),
+				District:      get("district"),
+				Category:      get("category"),
+				ChildCategory: get("child_category"),
+				ImageURL:      get("image_url"),
+				FlyerURL:      get("flyer_url"),
+			}
+			if lat, lng := get("latitude"), get("longitude"); lat != "" || lng != "" {
+				latitude, longitude, err := parseCoordinates(lat, lng)
+				if err != nil {
+					record.parseErrors = append(record.parseErrors, fmt.Sprintf("row %d: %v", line, err))
+				}
+				record.Latitude, record.Longitude = latitude, longitude
+			}
+			for _, galleryURL := range strings.Split(get("gallery_urls"), "|") {
+				if galleryURL = strings.TrimSpace(galleryURL); galleryURL != "" {
+					record.GalleryURLs = append(record.GalleryURLs, galleryURL)
+				}
+			}
+			records = append(records, record)
+			index = len(records) - 1
+			byRef[key] = index
+		}
+		record := &records[index]
+		record.rows = append(record.rows, line)
+
+		if ticketName := get("ticket_name"); ticketName != "" {
+			ticket := TicketCategoryRequest{
+				Name:          ticketName,
+				Description:   get("ticket_description"),
+				DateTimeStart: get("ticket_sale_start"),
+				DateTimeEnd:   get("ticket_sale_end"),
+			}
+			invalid := func(column string) {
+				record.parseErrors = append(record.parseErrors, fmt.Sprintf("row %d: invalid %s %q", line, column, get(column)))
+			}
+			if ticket.Price, err = parseImportFloat(get("ticket_price")); err != nil {
+				invalid("ticket_price")
+			}
+			if ticket.Quota, err = parseImportUint(get("ticket_quota")); err != nil {
+				invalid("ticket_quota")
+			}
+			if ticket.CompQuota, err = parseImportUint(get("ticket_comp_quota")); err != nil {
+				invalid("ticket_comp_quota")
+			}
+			if hidden := get("ticket_is_hidden"); hidden != "" {
+				if ticket.IsHidden, err = strconv.ParseBool(hidden); err != nil {
+					invalid("ticket_is_hidden")
+				}
+			}
+			record.TicketCategories = append(record.TicketCategories, ticket)
+		}
+	}
+	return records, nil
+}
+
+func parseImportFloat(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func parseImportUint(value string) (uint, error) {
+	if value == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseUint(value, 10, 32)
+	return uint(v), err
+}
+
+func validImportURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// validateImportRecord memeriksa satu record dan membentuk event yang siap disimpan.
+// Semua masalah dikumpulkan supaya laporan dry-run lengkap dalam sekali jalan.
+func validateImportRecord(db *gorm.DB, owner models.User, record EventImportRecord) (models.Event, []models.TicketCategory, []string) {
+	errs := append([]string(nil), record.parseErrors...)
+	addErr := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	event := models.Event{
+		Name:        strings.TrimSpace(record.Name),
+		Description: record.Description,
+		Rules:       record.Rules,
+		Location:    strings.TrimSpace(record.Location),
+		Venue:       strings.TrimSpace(record.Venue),
+		District:    strings.TrimSpace(record.District),
+		Latitude:    record.Latitude,
+		Longitude:   record.Longitude,
+	}
+
+	if record.VenueID != "" {
+		venue, _, err := resolveEventVenue(db, owner, record.VenueID)
+		if err != nil {
+			addErr("venue_id: %v", err)
+		} else {
+			event.VenueID = &venue.VenueID
+			event.Venue, event.Location, event.District = venue.Name, venue.Address, venue.District
+			if event.Latitude == nil {
+				event.Latitude, event.Longitude = venue.Latitude, venue.Longitude
+			}
+		}
+	}
+
+	if event.Name == "" || event.Location == "" || event.Venue == "" || event.District == "" {
+		addErr("name, location, venue and district are required")
+	}
+	if len(event.Name) > 100 {
+		addErr("name must be at most 100 characters")
+	}
+	if (event.Latitude == nil) != (event.Longitude == nil) {
+		addErr("latitude and longitude must be provided together")
+	} else if event.Latitude != nil && (*event.Latitude < -90 || *event.Latitude > 90 || *event.Longitude < -180 || *event.Longitude > 180) {
+		addErr("coordinates are out of range")
+	}
+
+	loc, err := utils.LoadTimeZone(record.TimeZone)
+	if err != nil {
+		addErr("time_zone: %v", err)
+		loc = utils.TimeZoneOrDefault("")
+	}
+	event.TimeZone = loc.String()
+
+	dateStart, errStart := utils.ParseEventTime(record.DateStart, loc)
+	if errStart != nil {
+		addErr("invalid date_start: %v", errStart)
+	}
+	dateEnd, errEnd := utils.ParseEventTime(record.DateEnd, loc)
+	if errEnd != nil {
+		addErr("invalid date_end: %v", errEnd)
+	}
+	if errStart == nil && errEnd == nil && !dateEnd.After(dateStart) {
+		addErr("date_end must be after date_start")
+	}
+	event.DateStart, event.DateEnd = dateStart, dateEnd
+
+	parentCategory, childEventCategory, err := resolveEventCategory(db, record.Category, record.ChildCategory, nil)
+	if err != nil {
+		addErr("%v", err)
+	}
+	applyEventCategory(&event, parentCategory, childEventCategory)
+
+	for _, mediaURL := range append([]string{record.ImageURL, record.FlyerURL}, record.GalleryURLs...) {
+		if mediaURL != "" && !validImportURL(mediaURL) {
+			addErr("media URL %q must be an absolute http(s) URL", mediaURL)
+		}
+	}
+	if len(record.GalleryURLs) > maxGalleryItems {
+		addErr("a gallery can contain at most %d images", maxGalleryItems)
+	}
+
+	// Impor ulang file yang sama tidak membuat event ganda
+	if errStart == nil && event.Name != "" {
+		var existing int64
+		db.Model(&models.Event{}).Where("owner_id = ? AND name = ? AND date_start = ?", owner.UserID, event.Name, dateStart).Count(&existing)
+		if existing > 0 {
+			addErr("an event with the same name and date_start already exists for this organizer")
+		}
+	}
+
+	var ticketCategories []models.TicketCategory
+	seen := make(map[string]bool)
+	for _, req := range record.TicketCategories {
+		req.Name = strings.TrimSpace(req.Name)
+		if req.Name == "" {
+			addErr("ticket category name is required")
+			continue
+		}
+		if seen[req.Name] {
+			addErr("duplicate ticket category name: %s", req.Name)
+			continue
+		}
+		seen[req.Name] = true
+		if req.Price < 0 {
+			addErr("price for %s cannot be negative", req.Name)
+		}
+		dateTimeStart, dateTimeEnd, err := parseTicketCategoryDates(req, loc)
+		if err != nil {
+			addErr("%v", err)
+			continue
+		}
+		if !dateTimeEnd.After(dateTimeStart) {
+			addErr("sale end for %s must be after sale start", req.Name)
+		}
+		ticketCategories = append(ticketCategories, models.TicketCategory{
+			Name:          req.Name,
+			Price:         req.Price,
+			Quota:         req.Quota,
+			CompQuota:     req.CompQuota,
+			IsHidden:      req.IsHidden,
+			Description:   req.Description,
+			DateTimeStart: dateTimeStart,
+			DateTimeEnd:   dateTimeEnd,
+		})
+	}
+
+	return event, ticketCategories, errs
+}
+
+// uploadImportMedia menyalin media dari URL asal ke storage. Semua file yang berhasil diupload
+// dikembalikan supaya bisa dibersihkan jika penyimpanan gagal.
+func uploadImportMedia(item *importedEvent, ownerID string) ([]models.EventMedia, error) {
+	var uploaded []models.EventMedia
+	upload := func(source, folder string) (string, error) {
+		secureURL, publicID, err := config.UploadImageWithPublicID(context.Background(), source, folder)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch media %s: %v", source, err)
+		}
+		uploaded = append(uploaded, models.EventMedia{URL: secureURL, PublicID: publicID})
+		return secureURL, nil
+	}
+
+	var err error
+	if item.record.ImageURL != "" {
+		if item.event.Image, err = upload(item.record.ImageURL, fmt.Sprintf("ticketing-app/events/%s/images", ownerID)); err != nil {
+			return uploaded, err
+		}
+	}
+	if item.record.FlyerURL != "" {
+		if item.event.Flyer, err = upload(item.record.FlyerURL, fmt.Sprintf("ticketing-app/events/%s/flyers", ownerID)); err != nil {
+			return uploaded, err
+		}
+	}
+	for _, galleryURL := range item.record.GalleryURLs {
+		if _, err := upload(galleryURL, fmt.Sprintf("ticketing-app/events/%s/gallery", ownerID)); err != nil {
+			return uploaded, err
+		}
+	}
+	return uploaded, nil
+}
+
+// saveImportedEvent menyimpan event, ticket category, galeri, slug, dan item moderasi
+func saveImportedEvent(tx *gorm.DB, item *importedEvent, owner models.User, organizationID, status string, uploaded []models.EventMedia) error {
+	event := item.event
+	event.EventID = utils.GenerateEventID()
+	event.OwnerID = owner.UserID
+	event.OrganizationID = &organizationID
+	event.Status = status
+	event.CreatedAt, event.UpdatedAt = time.Now(), time.Now()
+	if err := tx.Create(&event).Error; err != nil {
+		return err
+	}
+
+	for _, tc := range item.ticketCategories {
+		tc.TicketCategoryID = utils.GenerateTicketCategoryID()
+		tc.EventID = event.EventID
+		tc.CreatedAt, tc.UpdatedAt = time.Now(), time.Now()
+		if err := tx.Create(&tc).Error; err != nil {
+			return err
+		}
+	}
+
+	// Media galeri adalah upload setelah image dan flyer
+	galleryStart := 0
+	if item.record.ImageURL != "" {
+		galleryStart++
+	}
+	if item.record.FlyerURL != "" {
+		galleryStart++
+	}
+	for i, media := range uploaded[galleryStart:] {
+		media.MediaID = utils.GenerateMediaID()
+		media.EventID = event.EventID
+		media.Position = i
+		media.CreatedAt, media.UpdatedAt = time.Now(), time.Now()
+		if err := tx.Create(&media).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := assignEventSlug(tx, event.EventID); err != nil {
+		return err
+	}
+	if status == "pending" {
+		if err := enqueueModeration(tx, moderationEntityEvent, event.EventID, owner.UserID); err != nil {
+			return err
+		}
+	}
+
+	item.event = event
+	return nil
+}
+
+// ImportEvents - Admin mengimpor event beserta ticket category untuk seorang organizer.
+// Form: file (CSV atau JSON), owner_id, organization_id (opsional).
+// Query: format=csv|json, mode=all_or_nothing|per_row, dry_run=true, approve=true (langsung approved).
+func ImportEvents(c *fiber.Ctx) error {
+	admin := c.Locals("user").(models.User)
+
+	mode := c.Query("mode", importModeAllOrNothing)
+	if mode != importModeAllOrNothing && mode != importModePerRow {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "mode must be either 'all_or_nothing' or 'per_row'",
+		})
+	}
+	dryRun := c.QueryBool("dry_run", false)
+	status := "pending"
+	if c.QueryBool("approve", false) {
+		status = "approved"
+	}
+
+	var owner models.User
+	if err := config.DB.Where("user_id = ? AND role = ?", c.FormValue("owner_id"), "organizer").First(&owner).Error; err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "owner_id must reference an organizer account",
+		})
+	}
+	organizationID, code, err := resolveEventOrganization(config.DB, owner, c.FormValue("organization_id"))
+	if err != nil {
+		return c.Status(code).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "file is required",
+		})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to open import file",
+		})
+	}
+	defer file.Close()
+
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		format = "csv"
+		if strings.HasSuffix(strings.ToLower(fileHeader.Filename), ".json") {
+			format = "json"
+		}
+	}
+
+	var records []EventImportRecord
+	switch format {
+	case "csv":
+		records, err = readEventImportCSV(file)
+	case "json":
+		err = json.NewDecoder(file).Decode(&records)
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "format must be either 'csv' or 'json'",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid import file: " + err.Error(),
+		})
+	}
+	if len(records) == 0 || len(records) > maxImportEvents {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("An import must contain between 1 and %d events", maxImportEvents),
+		})
+	}
+
+	// Validasi seluruh file terlebih dahulu
+	results := make([]EventImportResult, len(records))
+	var valid []*importedEvent
+	seenKeys := make(map[string]int)
+	for i, record := range records {
+		results[i] = EventImportResult{Index: i, Ref: record.Ref, Rows: record.rows, Name: record.Name}
+		event, ticketCategories, errs := validateImportRecord(config.DB, owner, record)
+		key := event.Name + "|" + event.DateStart.String()
+		if first, ok := seenKeys[key]; ok && event.Name != "" {
+			errs = append(errs, fmt.Sprintf("duplicate of event at index %d in this file", first))
+		} else {
+			seenKeys[key] = i
+		}
+		if len(errs) > 0 {
+			results[i].Status, results[i].Errors = "invalid", errs
+			continue
+		}
+		results[i].Status = "valid"
+		valid = append(valid, &importedEvent{record: record, event: event, ticketCategories: ticketCategories, result: &results[i]})
+	}
+
+	invalid := len(records) - len(valid)
+	report := func(httpStatus int, message string) error {
+		summary := fiber.Map{"total": len(records), "valid": len(valid), "invalid": invalid, "created": 0, "failed": 0}
+		for _, result := range results {
+			switch result.Status {
+			case "created":
+				summary["created"] = summary["created"].(int) + 1
+			case "failed":
+				summary["failed"] = summary["failed"].(int) + 1
+			}
+		}
+		return c.Status(httpStatus).JSON(fiber.Map{
+			"message": message,
+			"dry_run": dryRun,
+			"mode":    mode,
+			"summary": summary,
+			"results": results,
+		})
+	}
+
+	if dryRun {
+		return report(fiber.StatusOK, "Dry run completed, nothing was imported")
+	}
+	if mode == importModeAllOrNothing && invalid > 0 {
+		return report(fiber.StatusUnprocessableEntity, "Import rejected: fix the invalid events and try again")
+	}
+
+	// Geocode sebelum transaksi dibuka karena memanggil layanan eksternal
+	geocoded := map[string]geoPoint{}
+	for _, item := range valid {
+		if item.event.Latitude == nil {
+			point := geocodeCached(geocoded, item.event.Venue, item.event.Location, item.event.District)
+			item.event.Latitude, item.event.Longitude = point.lat, point.lng
+		}
+	}
+
+	var createdIDs []string
+	if mode == importModeAllOrNothing {
+		var allUploaded []models.EventMedia
+		fail := func(item *importedEvent, err error) error {
+			cleanupUploadedMedia(allUploaded)
+			item.result.Status, item.result.Errors = "failed", []string{err.Error()}
+			for _, other := range valid {
+				if other.result.Status == "valid" {
+					other.result.Status = "not_imported"
+				}
+			}
+			return report(fiber.StatusUnprocessableEntity, "Import failed, nothing was imported")
+		}
+
+		uploads := make([][]models.EventMedia, len(valid))
+		for i, item := range valid {
+			uploaded, err := uploadImportMedia(item, owner.UserID)
+			allUploaded = append(allUploaded, uploaded...)
+			if err != nil {
+				return fail(item, err)
+			}
+			uploads[i] = uploaded
+		}
+
+		tx := config.DB.Begin()
+		if tx.Error != nil {
+			cleanupUploadedMedia(allUploaded)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to start transaction",
+			})
+		}
+		for i, item := range valid {
+			if err := saveImportedEvent(tx, item, owner, organizationID, status, uploads[i]); err != nil {
+				tx.Rollback()
+				return fail(item, err)
+			}
+		}
+		if err := tx.Commit().Error; err != nil {
+			cleanupUploadedMedia(allUploaded)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to commit transaction",
+			})
+		}
+		for _, item := range valid {
+			item.result.Status, item.result.EventID = "created", item.event.EventID
+			createdIDs = append(createdIDs, item.event.EventID)
+		}
+	} else {
+		for _, item := range valid {
+			uploaded, err := uploadImportMedia(item, owner.UserID)
+			if err == nil {
+				err = config.DB.Transaction(func(tx *gorm.DB) error {
+					return saveImportedEvent(tx, item, owner, organizationID, status, uploaded)
+				})
+			}
+			if err != nil {
+				cleanupUploadedMedia(uploaded)
+				item.result.Status, item.result.Errors = "failed", []string{err.Error()}
+				continue
+			}
+			item.result.Status, item.result.EventID = "created", item.event.EventID
+			createdIDs = append(createdIDs, item.event.EventID)
+		}
+	}
+
+	for _, eventID := range createdIDs {
+		SyncEventLifecycleJobs(config.DB, eventID)
+	}
+	if err := recordAudit(config.DB, c, admin.UserID, "event.import", "user", owner.UserID, fiber.Map{
+		"organization_id": organizationID,
+		"mode":            mode,
+		"status":          status,
+		"total":           len(records),
+		"created":         len(createdIDs),
+	}); err != nil {
+		log.Printf("Failed to record import audit log: %v", err)
+	}
+
+	return report(fiber.StatusOK, fmt.Sprintf("%d of %d events imported", len(createdIDs), len(records)))
+}
+
+// eventExportRecord membentuk record impor dari event sehingga hasil ekspor bisa diimpor ulang
+func eventExportRecord(event models.Event) EventImportRecord {
+	loc := utils.TimeZoneOrDefault(event.TimeZone)
+	record := EventImportRecord{
+		Ref:           event.EventID,
+		Name:          event.Name,
+		Description:   event.Description,
+		Rules:         event.Rules,
+		DateStart:     utils.FormatLocal(event.DateStart, loc),
+		DateEnd:       utils.FormatLocal(event.DateEnd, loc),
+		TimeZone:      event.TimeZone,
+		Location:      event.Location,
+		Venue:         event.Venue,
+		District:      event.District,
+		Latitude:      event.Latitude,
+		Longitude:     event.Longitude,
+		Category:      event.Category,
+		ChildCategory: event.ChildCategory,
+		ImageURL:      event.Image,
+		FlyerURL:      event.Flyer,
+		GalleryURLs:   []string{},
+	}
+	if event.VenueID != nil {
+		record.VenueID = *event.VenueID
+	}
+	for _, media := range event.Gallery {
+		record.GalleryURLs = append(record.GalleryURLs, media.URL)
+	}
+	for _, tc := range event.TicketCategories {
+		record.TicketCategories = append(record.TicketCategories, TicketCategoryRequest{
+			Name:          tc.Name,
+			Price:         tc.Price,
+			Quota:         tc.Quota,
+			CompQuota:     tc.CompQuota,
+			IsHidden:      tc.IsHidden,
+			Description:   tc.Description,
+			DateTimeStart: utils.FormatLocal(tc.DateTimeStart, loc),
+			DateTimeEnd:   utils.FormatLocal(tc.DateTimeEnd, loc),
+		})
+	}
+	return record
+}
+
+func writeEventExportCSV(w io.Writer, records []EventImportRecord) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(eventImportColumns); err != nil {
+		return err
+	}
+
+	formatFloat := func(v *float64) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	}
+	for _, record := range records {
+		eventColumns := []string{
+			record.Ref, record.Name, record.Description, record.Rules, record.DateStart, record.DateEnd, record.TimeZone,
+			record.Location, record.Venue, record.VenueID, record.District, formatFloat(record.Latitude), formatFloat(record.Longitude),
+			record.Category, record.ChildCategory, record.ImageURL, record.FlyerURL, strings.Join(record.GalleryURLs, "|"),
+		}
+		if len(record.TicketCategories) == 0 {
+			if err := writer.Write(append(eventColumns, "", "", "", "", "", "", "", "")); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, tc := range record.TicketCategories {
+			row := append(append([]string{}, eventColumns...),
+				tc.Name,
+				strconv.FormatFloat(tc.Price, 'f', -1, 64),
+				strconv.FormatUint(uint64(tc.Quota), 10),
+				strconv.FormatUint(uint64(tc.CompQuota), 10),
+				strconv.FormatBool(tc.IsHidden),
+				tc.Description,
+				tc.DateTimeStart,
+				tc.DateTimeEnd,
+			)
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportEvents - Ekspor event beserta ticket category dalam format impor (CSV atau JSON).
+// Query: format=csv|json, owner_id (admin), organization_id, status.
+// Tanpa filter, organizer mendapat event miliknya dan organisasinya.
+func ExportEvents(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	format := strings.ToLower(c.Query("format", "csv"))
+	if format != "csv" && format != "json" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "format must be either 'csv' or 'json'",
+		})
+	}
+
+	query := config.DB.Model(&models.Event{})
+	ownerID, organizationID := c.Query("owner_id"), c.Query("organization_id")
+	switch {
+	case organizationID != "":
+		if user.Role != "admin" && !hasOrganizationPermission(config.DB, user, organizationID, permEventView) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Not authorized to export events of this organization",
+			})
+		}
+		query = query.Where("organization_id = ?", organizationID)
+	case ownerID != "":
+		if user.Role != "admin" && ownerID != user.UserID {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Not authorized to export events of this organizer",
+			})
+		}
+		query = query.Where("owner_id = ?", ownerID)
+	case user.Role != "admin":
+		organizationIDs, err := memberOrganizationIDs(config.DB, user.UserID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch organizations",
+			})
+		}
+		query = ownedByUserOrOrganizations(query, user.UserID, organizationIDs)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var events []models.Event
+	if err := query.Preload("TicketCategories").Preload("Gallery", galleryOrder).
+		Order("date_start ASC").Find(&events).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch events",
+		})
+	}
+
+	records := make([]EventImportRecord, 0, len(events))
+	for _, event := range events {
+		records = append(records, eventExportRecord(event))
+	}
+
+	filename := fmt.Sprintf("events_%s.%s", time.Now().Format("2006-01-02"), format)
+	c.Set("Content-Disposition", "attachment; filename="+filename)
+	if format == "json" {
+		return c.JSON(records)
+	}
+
+	var buf bytes.Buffer
+	if err := writeEventExportCSV(&buf, records); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate export",
+		})
+	}
+	c.Set("Content-Type", "text/csv; charset=utf-8")
+	return c.Send(buf.Bytes())
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+)
+
+const (
+	jobTypeEventStart = "event_start"
+	jobTypeEventEnd   = "event_end"
+)
+
+type eventJobPayload struct {
+	EventID string `json:"event_id"`
+}
+
+func eventJobKey(jobType, eventID string) string {
+	return jobType + ":" + eventID
+}
+
+func init() {
+	RegisterJobHandler(jobTypeEventStart, handleEventStartJob)
+	RegisterJobHandler(jobTypeEventEnd, handleEventEndJob)
+}
+
+// InitEventLifecycleJobs melakukan backfill job untuk event live yang belum punya job
+// (misal data dari sebelum scheduler ada).
+func InitEventLifecycleJobs(db *gorm.DB) error {
+	var events []models.Event
+	if err := db.Where("status IN ?", liveEventStatuses).Find(&events).Error; err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		payload := eventJobPayload{EventID: event.EventID}
+		if event.Status == "approved" {
+			if err := EnsureJob(db, jobTypeEventStart, eventJobKey(jobTypeEventStart, event.EventID), event.DateStart, payload); err != nil {
+				return err
+			}
+		}
+		if err := EnsureJob(db, jobTypeEventEnd, eventJobKey(jobTypeEventEnd, event.EventID), event.DateEnd, payload); err != nil {
+			return err
+		}
+	}
+
+	log.Println(" --  Lifecycle jobs ensured for " + strconv.Itoa(len(events)) + " events")
+	return nil
+}
+
+// SyncEventLifecycleJobs menjadwalkan ulang job start/end sesuai status dan tanggal event terbaru.
+// Dipanggil setiap kali status atau tanggal event berubah.
+func SyncEventLifecycleJobs(db *gorm.DB, eventID string) {
+	var event models.Event
+	if err := db.Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		// Event sudah dihapus: batalkan job yang tersisa
+		CancelJob(db, eventJobKey(jobTypeEventStart, eventID))
+		CancelJob(db, eventJobKey(jobTypeEventEnd, eventID))
+		return
+	}
+
+	startKey := eventJobKey(jobTypeEventStart, event.EventID)
+	endKey := eventJobKey(jobTypeEventEnd, event.EventID)
+	payload := eventJobPayload{EventID: event.EventID}
+
+	if !containsString(liveEventStatuses, event.Status) {
+		if err := CancelJob(db, startKey); err != nil {
+			log.Println("Failed to cancel event start job:", err)
+		}
+		if err := CancelJob(db, endKey); err != nil {
+			log.Println("Failed to cancel event end job:", err)
+		}
+		return
+	}
+
+	// Event active yang tanggal mulainya dimundurkan kembali ke approved sampai waktunya tiba
+	if event.Status == "active" && event.DateStart.After(time.Now()) {
+		db.Model(&models.Event{}).
+			Where("event_id = ? AND status = ?", event.EventID, "active").
+			Update("status", "approved")
+		event.Status = "approved"
+	}
+
+	if event.Status == "approved" {
+		if err := ScheduleJob(db, jobTypeEventStart, startKey, event.DateStart, payload); err != nil {
+			log.Println("Failed to schedule event start:", err)
+		}
+	} else if err := CancelJob(db, startKey); err != nil {
+		log.Println("Failed to cancel event start job:", err)
+	}
+
+	if err := ScheduleJob(db, jobTypeEventEnd, endKey, event.DateEnd, payload); err != nil {
+		log.Println("Failed to schedule event end:", err)
+	}
+}
+
+func handleEventStartJob(db *gorm.DB, job models.ScheduledJob) error {
+	var payload eventJobPayload
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return err
+	}
+
+	var event models.Event
+	if err := db.Where("event_id = ?", payload.EventID).First(&event).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	// Tanggal sudah diubah tanpa reschedule: jadwalkan ulang, jangan ubah status
+	if event.DateStart.After(time.Now()) {
+		return ScheduleJob(db, jobTypeEventStart, job.JobKey, event.DateStart, payload)
+	}
+
+	// Update bersyarat supaya event yang sudah dibatalkan/ditunda tidak ikut aktif
+	return db.Model(&models.Event{}).
+		Where("event_id = ? AND status = ?", event.EventID, "approved").
+		Updates(map[string]interface{}{"status": "active", "updated_at": time.Now()}).Error
+}
+
+func handleEventEndJob(db *gorm.DB, job models.ScheduledJob) error {
+	var payload eventJobPayload
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return err
+	}
+
+	var event models.Event
+	if err := db.Where("event_id = ?", payload.EventID).First(&event).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if event.DateEnd.After(time.Now()) {
+		return ScheduleJob(db, jobTypeEventEnd, job.JobKey, event.DateEnd, payload)
+	}
+
+	return db.Model(&models.Event{}).
+		Where("event_id = ? AND status IN ?", event.EventID, liveEventStatuses).
+		Updates(map[string]interface{}{"status": "ended", "updated_at": time.Now()}).Error
+}
@@ -0,0 +1,541 @@
+package handlers
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+)
+
+const (
+	reportFormatCSV  = "csv"
+	reportFormatXLSX = "xlsx"
+	reportFormatPDF  = "pdf"
+)
+
+// Font UTF-8 untuk PDF supaya nama event/kategori dengan karakter di luar Latin-1 tetap tampil
+const reportPDFFont = "DejaVu"
+
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	reportFontRegular []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	reportFontBold []byte
+)
+
+var reportContentTypes = map[string]string{
+	reportFormatCSV:  "text/csv; charset=utf-8",
+	reportFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	reportFormatPDF:  "application/pdf",
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// eventReportCategory adalah satu baris tabel per ticket category pada laporan unduhan
+type eventReportCategory struct {
+	Name              string
+	Price             float64
+	Quota             int64
+	Sold              int64
+	SoldPercentage    float64
+	Complimentary     int64
+	CheckedIn         int64
+	CheckInPercentage float64
+	Income            float64
+}
+
+// eventReportDay adalah penjualan harian (zona event) untuk grafik tren
+type eventReportDay struct {
+	Day         string
+	TicketsSold int
+	Revenue     float64
+}
+
+// eventReportData adalah isi laporan yang sama untuk semua format unduhan
+type eventReportData struct {
+	Event             models.Event
+	Period            string
+	Categories        []eventReportCategory
+	DailySales        []eventReportDay
+	TotalQuota        int64
+	TotalSold         int64
+	SoldPercentage    float64
+	TotalComp         int64
+	TotalCheckedIn    int64
+	CheckInPercentage float64
+	TotalIncome       float64
+	GeneratedAt       time.Time
+}
+
+// buildEventReportData menghitung statistik per kategori dari counter Sold/Attendant/CompIssued
+// dan penjualan harian dari transaksi
+func buildEventReportData(event models.Event) (eventReportData, error) {
+	loc := utils.TimeZoneOrDefault(event.TimeZone)
+	data := eventReportData{
+		Event:       event,
+		Period:      fmt.Sprintf("%s - %s", event.DateStart.In(loc).Format("02-01-2006"), event.DateEnd.In(loc).Format("02-01-2006")),
+		GeneratedAt: time.Now().In(loc),
+	}
+
+	for _, ticketCategory := range event.TicketCategories {
+		row := eventReportCategory{
+			Name:          ticketCategory.Name,
+			Price:         ticketCategory.Price,
+			Quota:         int64(ticketCategory.Quota),
+			Sold:          int64(ticketCategory.Sold),
+			Complimentary: int64(ticketCategory.CompIssued),
+			CheckedIn:     int64(ticketCategory.Attendant),
+		}
+		if row.Quota > 0 {
+			row.SoldPercentage = float64(row.Sold) / float64(row.Quota) * 100
+		}
+		if row.Sold+row.Complimentary > 0 {
+			row.CheckInPercentage = float64(row.CheckedIn) / float64(row.Sold+row.Complimentary) * 100
+		}
+		row.Income = float64(row.Sold) * row.Price
+		data.Categories = append(data.Categories, row)
+
+		data.TotalQuota += row.Quota
+		data.TotalSold += row.Sold
+		data.TotalComp += row.Complimentary
+		data.TotalCheckedIn += row.CheckedIn
+		data.TotalIncome += row.Income
+	}
+	if data.TotalQuota > 0 {
+		data.SoldPercentage = float64(data.TotalSold) / float64(data.TotalQuota) * 100
+	}
+	if data.TotalSold+data.TotalComp > 0 {
+		data.CheckInPercentage = float64(data.TotalCheckedIn) / float64(data.TotalSold+data.TotalComp) * 100
+	}
+
+	sales, err := loadEventSales(config.DB, event.EventID, nil, nil)
+	if err != nil {
+		return data, err
+	}
+	byDay := make(map[string]int)
+	for _, row := range sales {
+		day := row.TransactionTime.In(loc).Format("2006-01-02")
+		i, ok := byDay[day]
+		if !ok {
+			data.DailySales = append(data.DailySales, eventReportDay{Day: day})
+			i = len(data.DailySales) - 1
+			byDay[day] = i
+		}
+		data.DailySales[i].TicketsSold += int(row.Quantity)
+		data.DailySales[i].Revenue += row.Subtotal
+	}
+	return data, nil
+}
+
+// eventExportFilename membentuk nama file aman untuk header Content-Disposition
+func eventExportFilename(prefix string, event models.Event, format string) string {
+	name := strings.Trim(unsafeFilenameChars.ReplaceAllString(event.Name, "_"), "_")
+	if name == "" {
+		name = event.EventID
+	}
+	return fmt.Sprintf("%s_%s_%s.%s", prefix, name, time.Now().Format("2006-01-02"), format)
+}
+
+func formatPercent(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64) + "%"
+}
+
+func formatRupiah(v float64) string {
+	digits := strconv.FormatFloat(v, 'f', 0, 64)
+	negative := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	if negative {
+		return "-Rp " + b.String()
+	}
+	return "Rp " + b.String()
+}
+
+// writeEventReportCSV menulis laporan CSV; nilai diberi quote oleh encoding/csv bila perlu
+func writeEventReportCSV(w io.Writer, data eventReportData) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{
+		{"Kategori_Tiket", "Harga_Tiket", "Kuota_Tiket", "Tiket_Terjual", "Persentase_Terjual", "Tiket_Komplimen", "Tiket_Check_in", "Persentase_Check_in", "Pendapatan_Kategori"},
+	}
+	for _, row := range data.Categories {
+		rows = append(rows, []string{
+			row.Name,
+			strconv.FormatFloat(row.Price, 'f', 0, 64),
+			strconv.FormatInt(row.Quota, 10),
+			strconv.FormatInt(row.Sold, 10),
+			formatPercent(row.SoldPercentage),
+			strconv.FormatInt(row.Complimentary, 10),
+			strconv.FormatInt(row.CheckedIn, 10),
+			formatPercent(row.CheckInPercentage),
+			strconv.FormatFloat(row.Income, 'f', 0, 64),
+		})
+	}
+
+	rows = append(rows,
+		[]string{},
+		[]string{"RINGKASAN LAPORAN EVENT: " + data.Event.Name},
+		[]string{"Tanggal Event:", data.Period},
+		[]string{"Lokasi:", data.Event.Venue + " - " + data.Event.Location},
+		[]string{},
+		[]string{"Total Kuota Tiket:", strconv.FormatInt(data.TotalQuota, 10)},
+		[]string{"Total Tiket Terjual:", fmt.Sprintf("%d (%s)", data.TotalSold, formatPercent(data.SoldPercentage))},
+		[]string{"Total Tiket Komplimen:", strconv.FormatInt(data.TotalComp, 10)},
+		[]string{"Total Check-in:", fmt.Sprintf("%d (%s)", data.TotalCheckedIn, formatPercent(data.CheckInPercentage))},
+		[]string{"Total Pendapatan:", fmt.Sprintf("Rp %.0f", data.TotalIncome)},
+		[]string{"Total Like:", strconv.FormatUint(uint64(data.Event.TotalLikes), 10)},
+	)
+
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// writeEventReportXLSX membuat workbook dengan sheet ringkasan, tabel per kategori, dan penjualan harian
+// beserta grafiknya
+func writeEventReportXLSX(w io.Writer, data eventReportData) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	const (
+		summarySheet  = "Ringkasan"
+		categorySheet = "Kategori Tiket"
+		dailySheet    = "Penjualan Harian"
+	)
+	if err := f.SetSheetName("Sheet1", summarySheet); err != nil {
+		return err
+	}
+	if _, err := f.NewSheet(categorySheet); err != nil {
+		return err
+	}
+	if _, err := f.NewSheet(dailySheet); err != nil {
+		return err
+	}
+
+	titleStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}})
+	if err != nil {
+		return err
+	}
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"1F4E79"}},
+	})
+	if err != nil {
+		return err
+	}
+	numberStyle, err := f.NewStyle(&excelize.Style{NumFmt: 3}) // #,##0
+	if err != nil {
+		return err
+	}
+	percentStyle, err := f.NewStyle(&excelize.Style{NumFmt: 10}) // 0.00%
+	if err != nil {
+		return err
+	}
+
+	// Ringkasan
+	summary := [][]interface{}{
+		{"Laporan Event", data.Event.Name},
+		{"Tanggal Event", data.Period},
+		{"Lokasi", data.Event.Venue + " - " + data.Event.Location},
+		{"Dibuat Pada", data.GeneratedAt.Format("02-01-2006 15:04 MST")},
+		{},
+		{"Total Kuota Tiket", data.TotalQuota},
+		{"Total Tiket Terjual", data.TotalSold},
+		{"Persentase Terjual", data.SoldPercentage / 100},
+		{"Total Tiket Komplimen", data.TotalComp},
+		{"Total Check-in", data.TotalCheckedIn},
+		{"Persentase Check-in", data.CheckInPercentage / 100},
+		{"Total Pendapatan", data.TotalIncome},
+		{"Total Like", data.Event.TotalLikes},
+	}
+	for i, row := range summary {
+		if err := f.SetSheetRow(summarySheet, fmt.Sprintf("A%d", i+1), &row); err != nil {
+			return err
+		}
+	}
+	f.SetCellStyle(summarySheet, "A1", "B1", titleStyle)
+	f.SetCellStyle(summarySheet, "B6", "B7", numberStyle)
+	f.SetCellStyle(summarySheet, "B8", "B8", percentStyle)
+	f.SetCellStyle(summarySheet, "B9", "B10", numberStyle)
+	f.SetCellStyle(summarySheet, "B11", "B11", percentStyle)
+	f.SetCellStyle(summarySheet, "B12", "B13", numberStyle)
+	f.SetColWidth(summarySheet, "A", "A", 24)
+	f.SetColWidth(summarySheet, "B", "B", 40)
+
+	// Tabel per kategori
+	header := []interface{}{"Kategori Tiket", "Harga", "Kuota", "Terjual", "% Terjual", "Komplimen", "Check-in", "% Check-in", "Pendapatan"}
+	if err := f.SetSheetRow(categorySheet, "A1", &header); err != nil {
+		return err
+	}
+	f.SetCellStyle(categorySheet, "A1", "I1", headerStyle)
+	for i, row := range data.Categories {
+		values := []interface{}{row.Name, row.Price, row.Quota, row.Sold, row.SoldPercentage / 100, row.Complimentary, row.CheckedIn, row.CheckInPercentage / 100, row.Income}
+		if err := f.SetSheetRow(categorySheet, fmt.Sprintf("A%d", i+2), &values); err != nil {
+			return err
+		}
+	}
+	last := len(data.Categories) + 1
+	totals := []interface{}{"Total", nil, data.TotalQuota, data.TotalSold, data.SoldPercentage / 100, data.TotalComp, data.TotalCheckedIn, data.CheckInPercentage / 100, data.TotalIncome}
+	if err := f.SetSheetRow(categorySheet, fmt.Sprintf("A%d", last+1), &totals); err != nil {
+		return err
+	}
+	f.SetCellStyle(categorySheet, "B2", fmt.Sprintf("D%d", last+1), numberStyle)
+	f.SetCellStyle(categorySheet, "E2", fmt.Sprintf("E%d", last+1), percentStyle)
+	f.SetCellStyle(categorySheet, "F2", fmt.Sprintf("G%d", last+1), numberStyle)
+	f.SetCellStyle(categorySheet, "H2", fmt.Sprintf("H%d", last+1), percentStyle)
+	f.SetCellStyle(categorySheet, "I2", fmt.Sprintf("I%d", last+1), numberStyle)
+	f.SetColWidth(categorySheet, "A", "A", 28)
+	f.SetColWidth(categorySheet, "B", "I", 14)
+	f.SetPanes(categorySheet, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"})
+
+	if len(data.Categories) > 0 {
+		ref := func(col string) string {
+			return fmt.Sprintf("'%s'!$%s$2:$%s$%d", categorySheet, col, col, last)
+		}
+		if err := f.AddChart(categorySheet, fmt.Sprintf("A%d", last+3), &excelize.Chart{
+			Type: excelize.Col,
+			Series: []excelize.ChartSeries{
+				{Name: fmt.Sprintf("'%s'!$C$1", categorySheet), Categories: ref("A"), Values: ref("C")},
+				{Name: fmt.Sprintf("'%s'!$D$1", categorySheet), Categories: ref("A"), Values: ref("D")},
+				{Name: fmt.Sprintf("'%s'!$G$1", categorySheet), Categories: ref("A"), Values: ref("G")},
+			},
+			Title:  []excelize.RichTextRun{{Text: "Kuota, Terjual, dan Check-in per Kategori"}},
+			Legend: excelize.ChartLegend{Position: "bottom"},
+		}); err != nil {
+			return err
+		}
+		if err := f.AddChart(categorySheet, fmt.Sprintf("F%d", last+3), &excelize.Chart{
+			Type:   excelize.Pie,
+			Series: []excelize.ChartSeries{{Name: fmt.Sprintf("'%s'!$I$1", categorySheet), Categories: ref("A"), Values: ref("I")}},
+			Title:  []excelize.RichTextRun{{Text: "Pendapatan per Kategori"}},
+			Legend: excelize.ChartLegend{Position: "right"},
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Penjualan harian
+	dailyHeader := []interface{}{"Tanggal", "Tiket Terjual", "Pendapatan"}
+	if err := f.SetSheetRow(dailySheet, "A1", &dailyHeader); err != nil {
+		return err
+	}
+	f.SetCellStyle(dailySheet, "A1", "C1", headerStyle)
+	for i, day := range data.DailySales {
+		values := []interface{}{day.Day, day.TicketsSold, day.Revenue}
+		if err := f.SetSheetRow(dailySheet, fmt.Sprintf("A%d", i+2), &values); err != nil {
+			return err
+		}
+	}
+	f.SetCellStyle(dailySheet, "B2", fmt.Sprintf("C%d", len(data.DailySales)+1), numberStyle)
+	f.SetColWidth(dailySheet, "A", "C", 16)
+	if len(data.DailySales) > 0 {
+		lastDay := len(data.DailySales) + 1
+		if err := f.AddChart(dailySheet, "E2", &excelize.Chart{
+			Type: excelize.Line,
+			Series: []excelize.ChartSeries{{
+				Name:       fmt.Sprintf("'%s'!$B$1", dailySheet),
+				Categories: fmt.Sprintf("'%s'!$A$2:$A$%d", dailySheet, lastDay),
+				Values:     fmt.Sprintf("'%s'!$B$2:$B$%d", dailySheet, lastDay),
+			}},
+			Title:  []excelize.RichTextRun{{Text: "Tiket Terjual per Hari"}},
+			Legend: excelize.ChartLegend{Position: "none"},
+		}); err != nil {
+			return err
+		}
+	}
+
+	f.SetActiveSheet(0)
+	_, err = f.WriteTo(w)
+	return err
+}
+
+// pdfBarChart menggambar grafik batang horizontal sederhana mulai dari posisi Y saat ini
+func pdfBarChart(pdf *fpdf.Fpdf, title string, labels []string, values []float64, format func(float64) string) {
+	const (
+		labelWidth = 50.0
+		barWidth   = 100.0
+		rowHeight  = 7.0
+	)
+	pdf.SetFont(reportPDFFont, "B", 11)
+	pdf.CellFormat(0, 8, title, "", 1, "L", false, 0, "")
+	pdf.SetFont(reportPDFFont, "", 9)
+
+	maxValue := 0.0
+	for _, v := range values {
+		if v > maxValue {
+			maxValue = v
+		}
+	}
+	left := pdf.GetX()
+	for i, label := range labels {
+		if pdf.GetY()+rowHeight > 280 {
+			pdf.AddPage()
+		}
+		y := pdf.GetY()
+		pdf.SetXY(left, y)
+		pdf.CellFormat(labelWidth, rowHeight, label, "", 0, "L", false, 0, "")
+		width := 0.0
+		if maxValue > 0 {
+			width = values[i] / maxValue * barWidth
+		}
+		pdf.SetFillColor(31, 78, 121)
+		if width > 0 {
+			pdf.Rect(left+labelWidth, y+1.5, width, rowHeight-3, "F")
+		}
+		pdf.SetXY(left+labelWidth+width+2, y)
+		pdf.CellFormat(0, rowHeight, format(values[i]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+}
+
+// writeEventReportPDF membuat PDF dengan halaman ringkasan, tabel per kategori, dan grafik
+func writeEventReportPDF(w io.Writer, data eventReportData) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Laporan Event "+data.Event.Name, true)
+	pdf.SetAutoPageBreak(true, 15)
+	// Semua teks (termasuk label dan nilai ringkasan) memakai font UTF-8 yang sama
+	pdf.AddUTF8FontFromBytes(reportPDFFont, "", reportFontRegular)
+	pdf.AddUTF8FontFromBytes(reportPDFFont, "B", reportFontBold)
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont(reportPDFFont, "", 8)
+		pdf.CellFormat(0, 8, fmt.Sprintf("Dibuat %s - Halaman %d", data.GeneratedAt.Format("02-01-2006 15:04 MST"), pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	// Halaman ringkasan
+	pdf.AddPage()
+	pdf.SetFont(reportPDFFont, "B", 16)
+	pdf.MultiCell(0, 8, "Laporan Event: "+data.Event.Name, "", "L", false)
+	pdf.Ln(2)
+	pdf.SetFont(reportPDFFont, "", 10)
+	pdf.MultiCell(0, 6, "Tanggal Event: "+data.Period, "", "L", false)
+	pdf.MultiCell(0, 6, "Lokasi: "+data.Event.Venue+" - "+data.Event.Location, "", "L", false)
+	pdf.Ln(4)
+
+	summary := [][2]string{
+		{"Total Kuota Tiket", strconv.FormatInt(data.TotalQuota, 10)},
+		{"Total Tiket Terjual", fmt.Sprintf("%d (%s)", data.TotalSold, formatPercent(data.SoldPercentage))},
+		{"Total Tiket Komplimen", strconv.FormatInt(data.TotalComp, 10)},
+		{"Total Check-in", fmt.Sprintf("%d (%s)", data.TotalCheckedIn, formatPercent(data.CheckInPercentage))},
+		{"Total Pendapatan", formatRupiah(data.TotalIncome)},
+		{"Total Like", strconv.FormatUint(uint64(data.Event.TotalLikes), 10)},
+	}
+	for _, row := range summary {
+		pdf.SetFont(reportPDFFont, "B", 10)
+		pdf.CellFormat(60, 8, row[0], "1", 0, "L", false, 0, "")
+		pdf.SetFont(reportPDFFont, "", 10)
+		pdf.CellFormat(80, 8, row[1], "1", 1, "L", false, 0, "")
+	}
+	pdf.Ln(6)
+
+	if len(data.DailySales) > 0 {
+		labels := make([]string, len(data.DailySales))
+		values := make([]float64, len(data.DailySales))
+		for i, day := range data.DailySales {
+			labels[i], values[i] = day.Day, float64(day.TicketsSold)
+		}
+		pdfBarChart(pdf, "Tiket Terjual per Hari", labels, values, func(v float64) string {
+			return strconv.FormatFloat(v, 'f', 0, 64)
+		})
+	}
+
+	// Halaman per kategori
+	pdf.AddPageFormat("L", pdf.GetPageSizeStr("A4"))
+	pdf.SetFont(reportPDFFont, "B", 13)
+	pdf.CellFormat(0, 10, "Rincian per Kategori Tiket", "", 1, "L", false, 0, "")
+
+	columns := []struct {
+		title string
+		width float64
+		align string
+	}{
+		{"Kategori", 60, "L"}, {"Harga", 28, "R"}, {"Kuota", 20, "R"}, {"Terjual", 20, "R"}, {"% Terjual", 22, "R"},
+		{"Komplimen", 24, "R"}, {"Check-in", 20, "R"}, {"% Check-in", 24, "R"}, {"Pendapatan", 40, "R"},
+	}
+	pdf.SetFont(reportPDFFont, "B", 9)
+	pdf.SetFillColor(31, 78, 121)
+	pdf.SetTextColor(255, 255, 255)
+	for _, col := range columns {
+		pdf.CellFormat(col.width, 8, col.title, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont(reportPDFFont, "", 9)
+
+	writeRow := func(values []string, bold bool) {
+		if bold {
+			pdf.SetFont(reportPDFFont, "B", 9)
+		}
+		for i, col := range columns {
+			pdf.CellFormat(col.width, 7, values[i], "1", 0, col.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont(reportPDFFont, "", 9)
+	}
+	for _, row := range data.Categories {
+		writeRow([]string{
+			row.Name, formatRupiah(row.Price), strconv.FormatInt(row.Quota, 10), strconv.FormatInt(row.Sold, 10),
+			formatPercent(row.SoldPercentage), strconv.FormatInt(row.Complimentary, 10), strconv.FormatInt(row.CheckedIn, 10),
+			formatPercent(row.CheckInPercentage), formatRupiah(row.Income),
+		}, false)
+	}
+	writeRow([]string{
+		"Total", "", strconv.FormatInt(data.TotalQuota, 10), strconv.FormatInt(data.TotalSold, 10),
+		formatPercent(data.SoldPercentage), strconv.FormatInt(data.TotalComp, 10), strconv.FormatInt(data.TotalCheckedIn, 10),
+		formatPercent(data.CheckInPercentage), formatRupiah(data.TotalIncome),
+	}, true)
+
+	// Grafik per kategori
+	if len(data.Categories) > 0 {
+		pdf.AddPageFormat("P", pdf.GetPageSizeStr("A4"))
+		labels := make([]string, len(data.Categories))
+		sold := make([]float64, len(data.Categories))
+		checkIn := make([]float64, len(data.Categories))
+		income := make([]float64, len(data.Categories))
+		for i, row := range data.Categories {
+			labels[i] = row.Name
+			sold[i], checkIn[i], income[i] = row.SoldPercentage, row.CheckInPercentage, row.Income
+		}
+		pdfBarChart(pdf, "Persentase Terjual per Kategori", labels, sold, formatPercent)
+		pdfBarChart(pdf, "Persentase Check-in per Kategori", labels, checkIn, formatPercent)
+		pdfBarChart(pdf, "Pendapatan per Kategori", labels, income, formatRupiah)
+	}
+
+	return pdf.Output(w)
+}
+
+// renderEventReport menghasilkan isi file laporan sesuai format
+func renderEventReport(data eventReportData, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case reportFormatXLSX:
+		err = writeEventReportXLSX(&buf, data)
+	case reportFormatPDF:
+		err = writeEventReportPDF(&buf, data)
+	default:
+		err = writeEventReportCSV(&buf, data)
+	}
+	return buf.Bytes(), err
+}
@@ -0,0 +1,406 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+var publicEventStatuses = []string{"active", "approved", "ended"}
+
+// Harga minimum dari ticket category publik, dipakai untuk filter & sort harga
+const eventMinPriceExpr = "COALESCE((SELECT MIN(tc.price) FROM ticket_categories tc WHERE tc.event_id = events.event_id AND tc.is_hidden = false), 0)"
+
+const (
+	defaultEventPageSize = 20
+	maxEventPageSize     = 100
+)
+
+type eventListQuery struct {
+	Search        string
+	Category      string
+	ChildCategory string
+	District      string
+	DateFrom      *time.Time
+	DateTo        *time.Time
+	PriceMin      *float64
+	PriceMax      *float64
+	FreeOnly      bool
+	Statuses      []string
+	Lat           *float64
+	Lng           *float64
+	RadiusKm      float64
+	BBox          *[4]float64 // min_lng, min_lat, max_lng, max_lat
+	Sort          string      // date, price, popularity, recent, distance
+	Order         string      // asc, desc
+	Page          int
+	Limit         int
+	Cursor        *eventCursor
+}
+
+type eventCursor struct {
+	Value   string `json:"v"`
+	EventID string `json:"id"`
+}
+
+type eventPagination struct {
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	Total      int64  `json:"total"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// parseEventListQuery membaca parameter listing. defaultSort dipakai jika sort tidak diisi,
+// sehingga cursor divalidasi terhadap sort yang benar-benar berlaku.
+func parseEventListQuery(c *fiber.Ctx, defaultSort string) (eventListQuery, error) {
+	q := eventListQuery{
+		Search:        strings.TrimSpace(c.Query("q")),
+		Category:      c.Query("category"),
+		ChildCategory: c.Query("child_category"),
+		District:      c.Query("district"),
+		FreeOnly:      c.QueryBool("free", false),
+		Sort:          c.Query("sort", defaultSort),
+		Order:         strings.ToLower(c.Query("order")),
+		Page:          c.QueryInt("page", 1),
+		Limit:         c.QueryInt("limit", defaultEventPageSize),
+	}
+
+	// Tanggal YYYY-MM-DD dibaca sebagai hari kalender pada zona tz (default WIB)
+	loc, err := utils.LoadTimeZone(c.Query("tz"))
+	if err != nil {
+		return q, err
+	}
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		t, err := parseQueryDate(dateFrom, false, loc)
+		if err != nil {
+			return q, errors.New("invalid date_from format. Use RFC3339 or YYYY-MM-DD")
+		}
+		q.DateFrom = &t
+	}
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		t, err := parseQueryDate(dateTo, true, loc)
+		if err != nil {
+			return q, errors.New("invalid date_to format. Use RFC3339 or YYYY-MM-DD")
+		}
+		q.DateTo = &t
+	}
+
+	if priceMin := c.Query("price_min"); priceMin != "" {
+		v, err := strconv.ParseFloat(priceMin, 64)
+		if err != nil || v < 0 {
+			return q, errors.New("invalid price_min")
+		}
+		q.PriceMin = &v
+	}
+	if priceMax := c.Query("price_max"); priceMax != "" {
+		v, err := strconv.ParseFloat(priceMax, 64)
+		if err != nil || v < 0 {
+			return q, errors.New("invalid price_max")
+		}
+		q.PriceMax = &v
+	}
+
+	q.Statuses = publicEventStatuses
+	if status := c.Query("status"); status != "" {
+		var statuses []string
+		for _, s := range strings.Split(status, ",") {
+			s = strings.TrimSpace(s)
+			if !containsString(publicEventStatuses, s) {
+				return q, errors.New("status must be one of: approved, active, ended")
+			}
+			statuses = append(statuses, s)
+		}
+		q.Statuses = statuses
+	}
+
+	switch q.Sort {
+	case "date", "price", "popularity", "recent", "distance":
+	default:
+		return q, errors.New("sort must be one of: date, price, popularity, recent, distance")
+	}
+	if q.Order != "" && q.Order != "asc" && q.Order != "desc" {
+		return q, errors.New("order must be either asc or desc")
+	}
+	if q.Order == "" {
+		q.Order = defaultSortOrder(q.Sort)
+	}
+
+	if err := parseGeoQuery(c, &q); err != nil {
+		return q, err
+	}
+
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit < 1 {
+		q.Limit = defaultEventPageSize
+	}
+	if q.Limit > maxEventPageSize {
+		q.Limit = maxEventPageSize
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		decoded, err := decodeEventCursor(cursor)
+		if err != nil {
+			return q, errors.New("invalid cursor")
+		}
+		if _, err := cursorValue(q.Sort, decoded.Value); err != nil {
+			return q, errors.New("cursor does not match the requested sort")
+		}
+		q.Cursor = decoded
+	}
+
+	return q, nil
+}
+
+// applyEventFilters menerapkan semua filter (tanpa sort & pagination) ke query events
+func applyEventFilters(db *gorm.DB, q eventListQuery) *gorm.DB {
+	query := db.Model(&models.Event{}).Where("events.status IN ?", q.Statuses)
+
+	if q.Search != "" {
+		like := "%" + strings.ToLower(q.Search) + "%"
+		// Pencarian mencakup teks default dan semua terjemahan
+		query = query.Where("(LOWER(events.name) LIKE ? OR LOWER(events.description) LIKE ? OR LOWER(events.venue) LIKE ?"+
+			" OR EXISTS (SELECT 1 FROM event_translations et WHERE et.event_id = events.event_id AND (LOWER(et.name) LIKE ? OR LOWER(et.description) LIKE ?)))",
+			like, like, like, like, like)
+	}
+	// Filter kategori menerima ID maupun nama
+	if q.Category != "" {
+		query = query.Where("(events.category_id = ? OR events.category = ?)", q.Category, q.Category)
+	}
+	if q.ChildCategory != "" {
+		query = query.Where("(events.child_category_id = ? OR events.child_category = ?)", q.ChildCategory, q.ChildCategory)
+	}
+	if q.District != "" {
+		query = query.Where("events.district = ?", q.District)
+	}
+
+	// Event yang berlangsung (overlap) di dalam rentang tanggal
+	if q.DateFrom != nil {
+		query = query.Where("events.date_end >= ?", *q.DateFrom)
+	}
+	if q.DateTo != nil {
+		query = query.Where("events.date_start <= ?", *q.DateTo)
+	}
+
+	// Filter harga memakai ticket category publik yang ada di rentang harga
+	if q.FreeOnly {
+		query = query.Where("EXISTS (SELECT 1 FROM ticket_categories tc WHERE tc.event_id = events.event_id AND tc.is_hidden = false AND tc.price = 0)")
+	}
+	if q.PriceMin != nil || q.PriceMax != nil {
+		priceMin, priceMax := 0.0, math.MaxFloat64
+		if q.PriceMin != nil {
+			priceMin = *q.PriceMin
+		}
+		if q.PriceMax != nil {
+			priceMax = *q.PriceMax
+		}
+		query = query.Where("EXISTS (SELECT 1 FROM ticket_categories tc WHERE tc.event_id = events.event_id AND tc.is_hidden = false AND tc.price BETWEEN ? AND ?)", priceMin, priceMax)
+	}
+
+	// Filter lokasi hanya untuk event yang sudah punya koordinat
+	if q.RadiusKm > 0 || q.BBox != nil {
+		query = query.Where("events.latitude IS NOT NULL AND events.longitude IS NOT NULL")
+	}
+	if q.RadiusKm > 0 && q.Lat != nil {
+		query = query.Where(haversineExpr(*q.Lat, *q.Lng)+" <= ?", q.RadiusKm)
+	}
+	if q.BBox != nil {
+		minLng, minLat, maxLng, maxLat := q.BBox[0], q.BBox[1], q.BBox[2], q.BBox[3]
+		query = query.Where("events.latitude BETWEEN ? AND ?", minLat, maxLat)
+		if minLng <= maxLng {
+			query = query.Where("events.longitude BETWEEN ? AND ?", minLng, maxLng)
+		} else {
+			// Bounding box melewati antimeridian
+			query = query.Where("(events.longitude >= ? OR events.longitude <= ?)", minLng, maxLng)
+		}
+	}
+
+	return query
+}
+
+func eventSortExpr(q eventListQuery) string {
+	switch q.Sort {
+	case "distance":
+		return haversineExpr(*q.Lat, *q.Lng)
+	case "price":
+		return eventMinPriceExpr
+	case "popularity":
+		return "(events.total_likes + events.total_tickets_sold)"
+	case "recent":
+		return "events.created_at"
+	default:
+		return "events.date_start"
+	}
+}
+
+func defaultSortOrder(sort string) string {
+	switch sort {
+	case "popularity", "recent":
+		return "desc"
+	default:
+		return "asc"
+	}
+}
+
+// applyEventSort menambahkan ORDER BY dan, jika ada cursor, kondisi keyset (sort_value, event_id)
+func applyEventSort(query *gorm.DB, q eventListQuery) (*gorm.DB, error) {
+	expr := eventSortExpr(q)
+	direction := "ASC"
+	comparator := ">"
+	if q.Order == "desc" {
+		direction = "DESC"
+		comparator = "<"
+	}
+
+	if q.Cursor != nil {
+		value, err := cursorValue(q.Sort, q.Cursor.Value)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(
+			"("+expr+" "+comparator+" ? OR ("+expr+" = ? AND events.event_id "+comparator+" ?))",
+			value, value, q.Cursor.EventID,
+		)
+	}
+
+	return query.Order(expr + " " + direction).Order("events.event_id " + direction), nil
+}
+
+func cursorValue(sort, raw string) (interface{}, error) {
+	switch sort {
+	case "date", "recent":
+		return time.Parse(time.RFC3339Nano, raw)
+	default:
+		return strconv.ParseFloat(raw, 64)
+	}
+}
+
+func eventCursorFor(sort string, event models.Event) string {
+	var value string
+	switch sort {
+	case "distance":
+		if event.Distance != nil {
+			value = strconv.FormatFloat(*event.Distance, 'f', -1, 64)
+		}
+	case "price":
+		value = strconv.FormatFloat(minPublicPrice(event), 'f', -1, 64)
+	case "popularity":
+		value = strconv.FormatUint(uint64(event.TotalLikes+event.TotalTicketsSold), 10)
+	case "recent":
+		value = event.CreatedAt.Format(time.RFC3339Nano)
+	default:
+		value = event.DateStart.Format(time.RFC3339Nano)
+	}
+
+	raw, _ := json.Marshal(eventCursor{Value: value, EventID: event.EventID})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeEventCursor(raw string) (*eventCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, err
+	}
+	var cursor eventCursor
+	if err := json.Unmarshal(decoded, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.EventID == "" {
+		return nil, errors.New("cursor missing event id")
+	}
+	return &cursor, nil
+}
+
+func minPublicPrice(event models.Event) float64 {
+	minPrice := -1.0
+	for _, tc := range event.TicketCategories {
+		if tc.IsHidden {
+			continue
+		}
+		if minPrice < 0 || tc.Price < minPrice {
+			minPrice = tc.Price
+		}
+	}
+	if minPrice < 0 {
+		return 0
+	}
+	return minPrice
+}
+
+func parseQueryDate(value string, endOfDay bool, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return t, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t.UTC(), nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// listPublicEvents menjalankan query listing publik: filter, total, sort, lalu page atau cursor pagination
+func listPublicEvents(q eventListQuery) ([]models.Event, eventPagination, error) {
+	pagination := eventPagination{Limit: q.Limit}
+
+	if err := applyEventFilters(config.DB, q).Count(&pagination.Total).Error; err != nil {
+		return nil, pagination, err
+	}
+
+	query, err := applyEventSort(applyEventFilters(config.DB, q), q)
+	if err != nil {
+		return nil, pagination, err
+	}
+
+	query = query.Preload("Owner").Preload("TicketCategories", publicTicketCategories)
+	if q.Lat != nil {
+		query = query.Select("events.*, " + haversineExpr(*q.Lat, *q.Lng) + " AS distance")
+	}
+
+	if q.Cursor == nil {
+		pagination.Page = q.Page
+		pagination.TotalPages = int(math.Ceil(float64(pagination.Total) / float64(q.Limit)))
+		query = query.Offset((q.Page - 1) * q.Limit)
+	}
+
+	// Ambil satu data lebih untuk mengetahui apakah masih ada halaman berikutnya
+	var events []models.Event
+	if err := query.Limit(q.Limit + 1).Find(&events).Error; err != nil {
+		return nil, pagination, err
+	}
+
+	if len(events) > q.Limit {
+		events = events[:q.Limit]
+		pagination.HasMore = true
+	}
+	if pagination.HasMore && len(events) > 0 {
+		pagination.NextCursor = eventCursorFor(q.Sort, events[len(events)-1])
+	}
+
+	return events, pagination, nil
+}
@@ -0,0 +1,627 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// EventTemplateData adalah isi event yang bisa dipakai ulang: teks, lokasi, kategori, gambar, dan
+// struktur ticket category. Jadwal disimpan relatif terhadap waktu mulai event.
+type EventTemplateData struct {
+	Name             string                 `json:"name"`
+	Description      string                 `json:"description"`
+	Rules            string                 `json:"rules"`
+	Location         string                 `json:"location"`
+	Venue            string                 `json:"venue"`
+	VenueID          *string                `json:"venue_id"`
+	District         string                 `json:"district"`
+	Latitude         *float64               `json:"latitude"`
+	Longitude        *float64               `json:"longitude"`
+	TimeZone         string                 `json:"time_zone"`
+	CategoryID       *string                `json:"category_id"`
+	ChildCategoryID  *string                `json:"child_category_id"`
+	Image            string                 `json:"image"`
+	Flyer            string                 `json:"flyer"`
+	DurationMinutes  int                    `json:"duration_minutes"`
+	TicketCategories []SeriesTicketTemplate `json:"ticket_categories"`
+}
+
+type EventTemplateRequest struct {
+	Name    string `json:"name"`
+	EventID string `json:"event_id"` // saat update: isi ulang template dari event ini
+}
+
+type EventFromTemplateRequest struct {
+	Name           string `json:"name"`
+	DateStart      string `json:"date_start"` // RFC3339 atau jam lokal zona event
+	OrganizationID string `json:"organization_id"`
+}
+
+// eventTemplateData membentuk template dari event beserta ticket category-nya.
+// Penjualan yang berakhir tepat saat event selesai disimpan tanpa end offset.
+func eventTemplateData(event models.Event) EventTemplateData {
+	data := EventTemplateData{
+		Name:            event.Name,
+		Description:     event.Description,
+		Rules:           event.Rules,
+		Location:        event.Location,
+		Venue:           event.Venue,
+		VenueID:         event.VenueID,
+		District:        event.District,
+		Latitude:        event.Latitude,
+		Longitude:       event.Longitude,
+		TimeZone:        event.TimeZone,
+		CategoryID:      event.CategoryID,
+		ChildCategoryID: event.ChildCategoryID,
+		Image:           event.Image,
+		Flyer:           event.Flyer,
+		DurationMinutes: int(event.DateEnd.Sub(event.DateStart) / time.Minute),
+	}
+
+	for _, tc := range event.TicketCategories {
+		tmpl := SeriesTicketTemplate{
+			Name:               tc.Name,
+			Price:              tc.Price,
+			Quota:              tc.Quota,
+			CompQuota:          tc.CompQuota,
+			IsHidden:           tc.IsHidden,
+			Description:        tc.Description,
+			StartOffsetMinutes: int(tc.DateTimeStart.Sub(event.DateStart) / time.Minute),
+		}
+		if !tc.DateTimeEnd.Equal(event.DateEnd) {
+			endOffset := int(tc.DateTimeEnd.Sub(event.DateStart) / time.Minute)
+			tmpl.EndOffsetMinutes = &endOffset
+		}
+		data.TicketCategories = append(data.TicketCategories, tmpl)
+	}
+	return data
+}
+
+// instantiateEventTemplate membuat event pending baru dari template yang dimulai pada start.
+// Counter penjualan, like, dan rating mulai dari nol. current diisi saat duplikasi supaya kategori
+// nonaktif yang dipakai event sumber tetap boleh disalin.
+func instantiateEventTemplate(tx *gorm.DB, user models.User, organizationID string, data EventTemplateData, start time.Time, current *models.Event) (models.Event, error) {
+	if data.DurationMinutes < 1 {
+		return models.Event{}, errors.New("template duration must be positive")
+	}
+
+	event := models.Event{
+		EventID:        utils.GenerateEventID(),
+		Name:           data.Name,
+		OwnerID:        user.UserID,
+		OrganizationID: &organizationID,
+		Status:         "pending",
+		DateStart:      start,
+		DateEnd:        start.Add(time.Duration(data.DurationMinutes) * time.Minute),
+		TimeZone:       utils.TimeZoneOrDefault(data.TimeZone).String(),
+		Location:       data.Location,
+		Venue:          data.Venue,
+		District:       data.District,
+		Latitude:       data.Latitude,
+		Longitude:      data.Longitude,
+		Description:    data.Description,
+		Rules:          data.Rules,
+		Image:          data.Image,
+		Flyer:          data.Flyer,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	var categoryID, childCategoryID string
+	if data.CategoryID != nil {
+		categoryID = *data.CategoryID
+	}
+	if data.ChildCategoryID != nil {
+		childCategoryID = *data.ChildCategoryID
+	}
+	parentCategory, childEventCategory, err := resolveEventCategory(tx, categoryID, childCategoryID, current)
+	if err != nil {
+		return event, err
+	}
+	applyEventCategory(&event, parentCategory, childEventCategory)
+
+	// Venue yang sudah dihapus atau ditolak dilepas; teks alamatnya tetap dipakai
+	if data.VenueID != nil {
+		if venue, _, err := resolveEventVenue(tx, user, *data.VenueID); err == nil {
+			event.VenueID = &venue.VenueID
+		}
+	}
+
+	if err := tx.Create(&event).Error; err != nil {
+		return event, err
+	}
+
+	for _, tmpl := range data.TicketCategories {
+		ticketCategory := ticketCategoryFromTemplate(tmpl, event)
+		if err := tx.Create(&ticketCategory).Error; err != nil {
+			return event, err
+		}
+		event.TicketCategories = append(event.TicketCategories, ticketCategory)
+	}
+
+	if err := assignEventSlug(tx, event.EventID); err != nil {
+		return event, err
+	}
+	if err := enqueueModeration(tx, moderationEntityEvent, event.EventID, user.UserID); err != nil {
+		return event, err
+	}
+	return event, nil
+}
+
+// copyEventExtras menyalin galeri dan terjemahan event sumber ke event hasil duplikasi.
+// File galeri dipakai bersama; penghapusan memeriksa pemakaian lain lewat withoutSharedMedia.
+func copyEventExtras(tx *gorm.DB, source, target models.Event) error {
+	var gallery []models.EventMedia
+	if err := tx.Where("event_id = ?", source.EventID).Find(&gallery).Error; err != nil {
+		return err
+	}
+	for _, media := range gallery {
+		media.MediaID = utils.GenerateMediaID()
+		media.EventID = target.EventID
+		media.CreatedAt, media.UpdatedAt = time.Now(), time.Now()
+		if err := tx.Create(&media).Error; err != nil {
+			return err
+		}
+	}
+
+	var translations []models.EventTranslation
+	if err := tx.Where("event_id = ?", source.EventID).Find(&translations).Error; err != nil {
+		return err
+	}
+	for _, translation := range translations {
+		translation.EventID = target.EventID
+		translation.CreatedAt, translation.UpdatedAt = time.Now(), time.Now()
+		if err := tx.Create(&translation).Error; err != nil {
+			return err
+		}
+	}
+
+	// Terjemahan ticket category dipetakan lewat nama category yang sama
+	newCategoryIDs := make(map[string]string)
+	for _, tc := range target.TicketCategories {
+		newCategoryIDs[tc.Name] = tc.TicketCategoryID
+	}
+	sourceCategoryNames := make(map[string]string)
+	for _, tc := range source.TicketCategories {
+		sourceCategoryNames[tc.TicketCategoryID] = tc.Name
+	}
+
+	var categoryTranslations []models.TicketCategoryTranslation
+	if err := tx.Where("event_id = ?", source.EventID).Find(&categoryTranslations).Error; err != nil {
+		return err
+	}
+	for _, translation := range categoryTranslations {
+		newID, ok := newCategoryIDs[sourceCategoryNames[translation.TicketCategoryID]]
+		if !ok {
+			continue
+		}
+		translation.TicketCategoryID = newID
+		translation.EventID = target.EventID
+		translation.CreatedAt, translation.UpdatedAt = time.Now(), time.Now()
+		if err := tx.Create(&translation).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DuplicateEvent - Menyalin event menjadi event pending baru. Tanpa date_start, jadwal digeser
+// per tahun (jam lokal sama) sampai berada di masa depan.
+func DuplicateEvent(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var source models.Event
+	if err := config.DB.Preload("TicketCategories").Where("event_id = ?", c.Params("id")).First(&source).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+	if !canAccessEvent(config.DB, user, source, permEventManage) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to duplicate this event",
+		})
+	}
+
+	var req EventFromTemplateRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request",
+			})
+		}
+	}
+
+	loc := utils.TimeZoneOrDefault(source.TimeZone)
+	start := source.DateStart.In(loc)
+	if req.DateStart != "" {
+		parsed, err := utils.ParseEventTime(req.DateStart, loc)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid date_start format: " + err.Error(),
+			})
+		}
+		start = parsed
+	} else {
+		for !start.After(time.Now()) || start.Equal(source.DateStart.In(loc)) {
+			start = start.AddDate(1, 0, 0)
+		}
+	}
+	if !start.After(time.Now()) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "date_start must be in the future",
+		})
+	}
+
+	organizationID := req.OrganizationID
+	if organizationID == "" && source.OrganizationID != nil {
+		organizationID = *source.OrganizationID
+	}
+	organizationID, status, err := resolveEventOrganization(config.DB, user, organizationID)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	data := eventTemplateData(source)
+	data.Name = firstNonEmpty(strings.TrimSpace(req.Name), source.Name)
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	event, err := instantiateEventTemplate(tx, user, organizationID, data, start.UTC(), &source)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, errInvalidEventCategory) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to duplicate event: " + err.Error(),
+		})
+	}
+
+	if err := copyEventExtras(tx, source, event); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to copy event gallery and translations",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	var created models.Event
+	config.DB.Preload("TicketCategories").Preload("Gallery", galleryOrder).Where("event_id = ?", event.EventID).First(&created)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":         "Event duplicated successfully",
+		"event":           created,
+		"source_event_id": source.EventID,
+	})
+}
+
+// eventTemplateResponse menggabungkan metadata template dengan isinya
+func eventTemplateResponse(template models.EventTemplate) fiber.Map {
+	var data EventTemplateData
+	json.Unmarshal([]byte(template.Data), &data)
+	return fiber.Map{
+		"template_id":     template.TemplateID,
+		"owner_id":        template.OwnerID,
+		"organization_id": template.OrganizationID,
+		"name":            template.Name,
+		"source_event_id": template.SourceEventID,
+		"data":            data,
+		"created_at":      template.CreatedAt,
+		"updated_at":      template.UpdatedAt,
+	}
+}
+
+// loadAccessibleTemplate memuat template yang boleh dipakai user (pemilik atau anggota organisasinya)
+func loadAccessibleTemplate(templateID string, user models.User) (models.EventTemplate, int, error) {
+	var template models.EventTemplate
+	if err := config.DB.Where("template_id = ?", templateID).First(&template).Error; err != nil {
+		return template, fiber.StatusNotFound, errors.New("Event template not found")
+	}
+	if !canAccessOwned(config.DB, user, template.OwnerID, template.OrganizationID, permEventManage) {
+		return template, fiber.StatusForbidden, errors.New("Not authorized to use this event template")
+	}
+	return template, 0, nil
+}
+
+// loadTemplateSourceEvent memuat event yang akan dijadikan isi template
+func loadTemplateSourceEvent(eventID string, user models.User) (models.Event, int, error) {
+	var event models.Event
+	if err := config.DB.Preload("TicketCategories").Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		return event, fiber.StatusNotFound, errors.New("Event not found")
+	}
+	if !canAccessEvent(config.DB, user, event, permEventManage) {
+		return event, fiber.StatusForbidden, errors.New("Not authorized to use this event as a template")
+	}
+	return event, 0, nil
+}
+
+// CreateEventTemplate - Menyimpan event sebagai template bernama. Galeri tidak ikut disimpan.
+func CreateEventTemplate(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req EventTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 || req.EventID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "name (max 100 characters) and event_id are required",
+		})
+	}
+
+	event, status, err := loadTemplateSourceEvent(req.EventID, user)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	data, _ := json.Marshal(eventTemplateData(event))
+	template := models.EventTemplate{
+		TemplateID:     utils.GenerateEventTemplateID(),
+		OwnerID:        user.UserID,
+		OrganizationID: event.OrganizationID,
+		Name:           req.Name,
+		SourceEventID:  &event.EventID,
+		Data:           string(data),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	if err := config.DB.Create(&template).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save event template",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":  "Event template saved successfully",
+		"template": eventTemplateResponse(template),
+	})
+}
+
+// GetEventTemplates - Template milik user dan organisasi tempat user menjadi anggota
+func GetEventTemplates(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	organizationIDs, err := memberOrganizationIDs(config.DB, user.UserID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch organizations",
+		})
+	}
+
+	query := config.DB.Model(&models.EventTemplate{})
+	if organizationID := c.Query("organization_id"); organizationID != "" {
+		if !containsString(organizationIDs, organizationID) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Not a member of this organization",
+			})
+		}
+		query = query.Where("organization_id = ?", organizationID)
+	} else {
+		query = ownedByUserOrOrganizations(query, user.UserID, organizationIDs)
+	}
+
+	var templates []models.EventTemplate
+	if err := query.Order("name ASC").Find(&templates).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch event templates",
+		})
+	}
+
+	response := make([]fiber.Map, 0, len(templates))
+	for _, template := range templates {
+		response = append(response, eventTemplateResponse(template))
+	}
+
+	return c.JSON(fiber.Map{
+		"templates": response,
+	})
+}
+
+// GetEventTemplate - Detail satu template
+func GetEventTemplate(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	template, status, err := loadAccessibleTemplate(c.Params("id"), user)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"template": eventTemplateResponse(template),
+	})
+}
+
+// UpdateEventTemplate - Mengganti nama template dan/atau mengisi ulang isinya dari event_id
+func UpdateEventTemplate(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	template, status, err := loadAccessibleTemplate(c.Params("id"), user)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var req EventTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if len(req.Name) > 100 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "name must be at most 100 characters",
+		})
+	}
+
+	updateData := map[string]interface{}{"updated_at": time.Now()}
+	if req.Name != "" {
+		updateData["name"] = req.Name
+	}
+	if req.EventID != "" {
+		event, status, err := loadTemplateSourceEvent(req.EventID, user)
+		if err != nil {
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		data, _ := json.Marshal(eventTemplateData(event))
+		updateData["data"] = string(data)
+		updateData["source_event_id"] = event.EventID
+	}
+
+	if err := config.DB.Model(&template).Updates(updateData).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update event template",
+		})
+	}
+	config.DB.Where("template_id = ?", template.TemplateID).First(&template)
+
+	return c.JSON(fiber.Map{
+		"message":  "Event template updated successfully",
+		"template": eventTemplateResponse(template),
+	})
+}
+
+// DeleteEventTemplate - Menghapus template; event yang sudah dibuat darinya tidak terpengaruh
+func DeleteEventTemplate(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	template, status, err := loadAccessibleTemplate(c.Params("id"), user)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if err := config.DB.Delete(&template).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete event template",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Event template deleted successfully",
+	})
+}
+
+// CreateEventFromTemplate - Membuat event pending baru dari template pada date_start
+func CreateEventFromTemplate(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	template, status, err := loadAccessibleTemplate(c.Params("id"), user)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var req EventFromTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if req.DateStart == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "date_start is required",
+		})
+	}
+
+	var data EventTemplateData
+	if err := json.Unmarshal([]byte(template.Data), &data); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Event template is corrupted",
+		})
+	}
+	data.Name = firstNonEmpty(strings.TrimSpace(req.Name), data.Name)
+
+	start, err := utils.ParseEventTime(req.DateStart, utils.TimeZoneOrDefault(data.TimeZone))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid date_start format: " + err.Error(),
+		})
+	}
+	if !start.After(time.Now()) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "date_start must be in the future",
+		})
+	}
+
+	organizationID := req.OrganizationID
+	if organizationID == "" && template.OrganizationID != nil {
+		organizationID = *template.OrganizationID
+	}
+	organizationID, status, err = resolveEventOrganization(config.DB, user, organizationID)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	event, err := instantiateEventTemplate(tx, user, organizationID, data, start, nil)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, errInvalidEventCategory) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create event from template: " + err.Error(),
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	var created models.Event
+	config.DB.Preload("TicketCategories").Where("event_id = ?", event.EventID).First(&created)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":     "Event created from template successfully",
+		"event":       created,
+		"template_id": template.TemplateID,
+	})
+}
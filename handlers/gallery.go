@@ -0,0 +1,612 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxGalleryItems = 30
+
+var allowedImageExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".gif":  true,
+	".webp": true,
+}
+
+type UpdateMediaRequest struct {
+	Caption *string `json:"caption"`
+	IsCover *bool   `json:"is_cover"`
+}
+
+type ReorderMediaRequest struct {
+	MediaIDs []string `json:"media_ids"`
+}
+
+func validateImageUpload(file *multipart.FileHeader) error {
+	if file.Size > 5*1024*1024 {
+		return errors.New("file size too large. Maximum size is 5MB")
+	}
+	if !allowedImageExtensions[strings.ToLower(filepath.Ext(file.Filename))] {
+		return errors.New("invalid file type. Allowed types: JPG, JPEG, PNG, GIF, WEBP")
+	}
+	return nil
+}
+
+// galleryOrder dipakai untuk preload galeri sesuai urutan
+func galleryOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, created_at ASC")
+}
+
+// loadEditableEvent memuat event dan memastikan user boleh mengelola galerinya.
+// Status HTTP dikembalikan bersama error supaya handler cukup meneruskannya.
+func loadEditableEvent(eventID string, user models.User) (models.Event, int, error) {
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		return event, fiber.StatusNotFound, errors.New("Event not found")
+	}
+
+	if !canAccessEvent(config.DB, user, event, permEventManage) {
+		return event, fiber.StatusForbidden, errors.New("Not authorized to manage this event gallery")
+	}
+
+	return event, fiber.StatusOK, nil
+}
+
+// GetEventGallery - Galeri event sesuai urutan
+func GetEventGallery(c *fiber.Ctx) error {
+	var media []models.EventMedia
+	if err := galleryOrder(config.DB).
+		Where("event_id = ?", c.Params("id")).
+		Find(&media).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch gallery",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Gallery retrieved successfully",
+		"gallery": media,
+	})
+}
+
+// AddEventMedia - Upload satu atau lebih gambar (field "images") ke galeri event.
+// Caption opsional lewat field "captions" (JSON array, urutan sama dengan file).
+func AddEventMedia(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	event, status, err := loadEditableEvent(c.Params("id"), user)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid form data",
+		})
+	}
+
+	files := form.File["images"]
+	if len(files) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "No images provided",
+		})
+	}
+
+	var captions []string
+	if captionsJSON := c.FormValue("captions"); captionsJSON != "" {
+		if err := json.Unmarshal([]byte(captionsJSON), &captions); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "captions must be a JSON array of strings",
+			})
+		}
+	}
+
+	for _, file := range files {
+		if err := validateImageUpload(file); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": file.Filename + ": " + err.Error(),
+			})
+		}
+	}
+
+	var existing int64
+	config.DB.Model(&models.EventMedia{}).Where("event_id = ?", event.EventID).Count(&existing)
+	if int(existing)+len(files) > maxGalleryItems {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("A gallery can contain at most %d images", maxGalleryItems),
+		})
+	}
+
+	var lastPosition struct{ Max *int }
+	config.DB.Model(&models.EventMedia{}).
+		Select("MAX(position) AS max").
+		Where("event_id = ?", event.EventID).
+		Scan(&lastPosition)
+	position := 0
+	if lastPosition.Max != nil {
+		position = *lastPosition.Max + 1
+	}
+
+	folder := fmt.Sprintf("ticketing-app/events/%s/gallery", event.EventID)
+	var uploaded []models.EventMedia
+	for i, file := range files {
+		fileHeader, err := file.Open()
+		if err != nil {
+			cleanupUploadedMedia(uploaded)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to open file " + file.Filename,
+			})
+		}
+
+		url, publicID, err := config.UploadImageWithPublicID(context.Background(), fileHeader, folder)
+		fileHeader.Close()
+		if err != nil {
+			cleanupUploadedMedia(uploaded)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to upload image to Cloudinary",
+			})
+		}
+
+		media := models.EventMedia{
+			MediaID:   utils.GenerateMediaID(),
+			EventID:   event.EventID,
+			URL:       url,
+			PublicID:  publicID,
+			Position:  position + i,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		if i < len(captions) {
+			media.Caption = captions[i]
+		}
+		uploaded = append(uploaded, media)
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		cleanupUploadedMedia(uploaded)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	if err := tx.Create(&uploaded).Error; err != nil {
+		tx.Rollback()
+		cleanupUploadedMedia(uploaded)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save gallery: " + err.Error(),
+		})
+	}
+
+	// Galeri pertama kali diisi: gambar pertama otomatis jadi cover
+	coverPending := false
+	if existing == 0 {
+		if coverPending, err = setGalleryCover(tx, user.UserID, event, uploaded[0]); err != nil {
+			tx.Rollback()
+			cleanupUploadedMedia(uploaded)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update event cover",
+			})
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		cleanupUploadedMedia(uploaded)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	config.DB.Where("event_id = ? AND media_id IN ?", event.EventID, mediaIDs(uploaded)).Order("position ASC").Find(&uploaded)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":              "Images added to gallery",
+		"media":                uploaded,
+		"cover_pending_review": coverPending,
+	})
+}
+
+// UpdateEventMedia - Ubah caption dan/atau jadikan gambar sebagai cover event
+func UpdateEventMedia(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	event, status, err := loadEditableEvent(c.Params("id"), user)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var req UpdateMediaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+
+	var media models.EventMedia
+	if err := config.DB.Where("media_id = ? AND event_id = ?", c.Params("media_id"), event.EventID).First(&media).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Media not found",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	updateData := map[string]interface{}{"updated_at": time.Now()}
+	if req.Caption != nil {
+		updateData["caption"] = *req.Caption
+	}
+
+	coverPending := false
+	if req.IsCover != nil && *req.IsCover && !media.IsCover {
+		if coverPending, err = setGalleryCover(tx, user.UserID, event, media); err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update cover",
+			})
+		}
+	}
+
+	if err := tx.Model(&models.EventMedia{}).Where("media_id = ?", media.MediaID).Updates(updateData).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update media",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	config.DB.Where("media_id = ?", media.MediaID).First(&media)
+
+	message := "Media updated successfully"
+	if coverPending {
+		message = "Media updated. The new cover of a live event is applied once its change request is approved"
+	}
+
+	return c.JSON(fiber.Map{
+		"message":              message,
+		"media":                media,
+		"cover_pending_review": coverPending,
+	})
+}
+
+// ReorderEventMedia - Menyusun ulang galeri; media_ids harus berisi semua media event
+func ReorderEventMedia(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	event, status, err := loadEditableEvent(c.Params("id"), user)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var req ReorderMediaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+
+	var existing []models.EventMedia
+	if err := config.DB.Where("event_id = ?", event.EventID).Find(&existing).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch gallery",
+		})
+	}
+
+	known := make(map[string]bool)
+	for _, media := range existing {
+		known[media.MediaID] = true
+	}
+	seen := make(map[string]bool)
+	for _, id := range req.MediaIDs {
+		if !known[id] || seen[id] {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "media_ids must list every media of this event exactly once",
+			})
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(known) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "media_ids must list every media of this event exactly once",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	for position, id := range req.MediaIDs {
+		if err := tx.Model(&models.EventMedia{}).
+			Where("media_id = ?", id).
+			Updates(map[string]interface{}{"position": position, "updated_at": time.Now()}).Error; err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to reorder gallery",
+			})
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	var gallery []models.EventMedia
+	galleryOrder(config.DB).Where("event_id = ?", event.EventID).Find(&gallery)
+
+	return c.JSON(fiber.Map{
+		"message": "Gallery reordered successfully",
+		"gallery": gallery,
+	})
+}
+
+// DeleteEventMedia - Hapus gambar dari galeri sekaligus dari Cloudinary
+func DeleteEventMedia(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	event, status, err := loadEditableEvent(c.Params("id"), user)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var media models.EventMedia
+	if err := config.DB.Where("media_id = ? AND event_id = ?", c.Params("media_id"), event.EventID).First(&media).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Media not found",
+		})
+	}
+
+	// Cover event live tidak bisa dihapus langsung karena gambarnya masih tampil di halaman publik
+	isCover := media.IsCover || event.Image == media.URL
+	if isCover && containsString(liveEventStatuses, event.Status) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "The cover of a live event cannot be deleted. Choose another cover and delete this image after the change request is approved",
+		})
+	}
+
+	// Hapus dari storage dulu; jika gagal, record tetap ada supaya bisa dicoba lagi.
+	// File yang juga dipakai event lain (hasil duplikasi) dibiarkan.
+	if media.PublicID != "" && len(withoutSharedMedia(config.DB, []models.EventMedia{media})) > 0 {
+		if err := config.DeleteImage(context.Background(), media.PublicID); err != nil {
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+				"error": "Failed to delete image from storage: " + err.Error(),
+			})
+		}
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	if err := tx.Where("media_id = ?", media.MediaID).Delete(&models.EventMedia{}).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete media",
+		})
+	}
+
+	// Rapatkan posisi setelah media yang dihapus
+	if err := tx.Model(&models.EventMedia{}).
+		Where("event_id = ? AND position > ?", event.EventID, media.Position).
+		Update("position", gorm.Expr("position - 1")).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to reorder gallery",
+		})
+	}
+
+	// Cover dihapus: gambar pertama yang tersisa menjadi cover baru
+	if isCover {
+		var next models.EventMedia
+		err := galleryOrder(tx).Where("event_id = ?", event.EventID).First(&next).Error
+		switch {
+		case err == nil:
+			_, err = setGalleryCover(tx, user.UserID, event, next)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			err = tx.Model(&models.Event{}).Where("event_id = ?", event.EventID).Update("image", "").Error
+		}
+		if err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update cover",
+			})
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Media deleted successfully",
+	})
+}
+
+// setGalleryCover menjadikan media sebagai satu-satunya cover dan menyalin URL-nya ke Event.Image.
+// Event live tidak diubah langsung: cover baru diajukan sebagai item change request dan baru diterapkan
+// saat disetujui. Mengembalikan true jika cover menunggu review.
+func setGalleryCover(tx *gorm.DB, userID string, event models.Event, media models.EventMedia) (bool, error) {
+	if containsString(liveEventStatuses, event.Status) {
+		return true, proposeCoverChange(tx, userID, event, media.URL)
+	}
+	if err := markGalleryCover(tx, event.EventID, media.URL); err != nil {
+		return false, err
+	}
+	return false, tx.Model(&models.Event{}).Where("event_id = ?", event.EventID).Update("image", media.URL).Error
+}
+
+// markGalleryCover menandai media dengan URL tersebut sebagai cover; URL di luar galeri berarti tanpa cover
+func markGalleryCover(tx *gorm.DB, eventID, url string) error {
+	if err := tx.Model(&models.EventMedia{}).
+		Where("event_id = ? AND is_cover = ?", eventID, true).
+		Update("is_cover", false).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.EventMedia{}).
+		Where("event_id = ? AND url = ?", eventID, url).
+		Update("is_cover", true).Error
+}
+
+// proposeCoverChange menambahkan item "image" ke change request pending event, atau membuat change
+// request baru. Item image yang masih pending diganti supaya hanya ada satu usulan cover.
+func proposeCoverChange(tx *gorm.DB, userID string, event models.Event, url string) error {
+	var changeRequest models.EventChangeRequest
+	err := tx.Where("event_id = ? AND status = ?", event.EventID, "pending").First(&changeRequest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		changeRequest = models.EventChangeRequest{
+			ChangeRequestID: utils.GenerateChangeRequestID(),
+			EventID:         event.EventID,
+			RequestedBy:     userID,
+			Status:          "pending",
+			Note:            "Gallery cover change",
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+		if err := tx.Create(&changeRequest).Error; err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	var item models.EventChangeRequestItem
+	err = tx.Where("change_request_id = ? AND field = ? AND status = ?", changeRequest.ChangeRequestID, "image", "pending").
+		First(&item).Error
+	if err == nil {
+		return tx.Model(&models.EventChangeRequestItem{}).
+			Where("item_id = ?", item.ItemID).
+			Updates(map[string]interface{}{"new_value": url, "updated_at": time.Now()}).Error
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return tx.Create(&models.EventChangeRequestItem{
+		ItemID:          utils.GenerateChangeRequestItemID(),
+		ChangeRequestID: changeRequest.ChangeRequestID,
+		Field:           "image",
+		OldValue:        event.Image,
+		NewValue:        url,
+		Status:          "pending",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}).Error
+}
+
+func mediaIDs(media []models.EventMedia) []string {
+	ids := make([]string, 0, len(media))
+	for _, m := range media {
+		ids = append(ids, m.MediaID)
+	}
+	return ids
+}
+
+// withoutSharedMedia membuang media yang file-nya masih dipakai di tempat lain: media galeri lain
+// (misal galeri event hasil duplikasi), image/flyer event lain, atau image/flyer di template event.
+func withoutSharedMedia(db *gorm.DB, media []models.EventMedia) []models.EventMedia {
+	var mediaIDs, publicIDs, urls, eventIDs []string
+	for _, m := range media {
+		mediaIDs = append(mediaIDs, m.MediaID)
+		eventIDs = append(eventIDs, m.EventID)
+		if m.PublicID != "" {
+			publicIDs = append(publicIDs, m.PublicID)
+			urls = append(urls, m.URL)
+		}
+	}
+	if len(publicIDs) == 0 {
+		return media
+	}
+
+	var shared []string
+	db.Model(&models.EventMedia{}).
+		Where("public_id IN ? AND media_id NOT IN ?", publicIDs, mediaIDs).
+		Distinct().Pluck("public_id", &shared)
+
+	// Image/flyer event sendiri diganti oleh caller, jadi hanya event lain yang dihitung
+	var sharedURLs []string
+	var images []struct{ Image, Flyer string }
+	db.Model(&models.Event{}).
+		Select("image", "flyer").
+		Where("(image IN ? OR flyer IN ?) AND event_id NOT IN ?", urls, urls, eventIDs).
+		Scan(&images)
+	for _, row := range images {
+		sharedURLs = append(sharedURLs, row.Image, row.Flyer)
+	}
+
+	escape := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	for _, url := range urls {
+		var templates int64
+		db.Model(&models.EventTemplate{}).
+			Where("data LIKE ?", "%"+escape.Replace(url)+"%").
+			Count(&templates)
+		if templates > 0 {
+			sharedURLs = append(sharedURLs, url)
+		}
+	}
+
+	filtered := make([]models.EventMedia, 0, len(media))
+	for _, m := range media {
+		if !containsString(shared, m.PublicID) && !containsString(sharedURLs, m.URL) {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
+
+// cleanupUploadedMedia menghapus file yang sudah terlanjur diupload ketika request gagal
+func cleanupUploadedMedia(media []models.EventMedia) {
+	for _, m := range media {
+		if m.PublicID == "" {
+			continue
+		}
+		if err := config.DeleteImage(context.Background(), m.PublicID); err != nil {
+			log.Printf("Failed to clean up uploaded image %s: %v", m.PublicID, err)
+		}
+	}
+}
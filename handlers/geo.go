@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultNearbyRadiusKm = 10.0
+	maxNearbyRadiusKm     = 500.0
+)
+
+// parseCoordinates membaca pasangan latitude/longitude dari form. Keduanya harus diisi bersamaan.
+func parseCoordinates(latStr, lngStr string) (*float64, *float64, error) {
+	latStr, lngStr = strings.TrimSpace(latStr), strings.TrimSpace(lngStr)
+	if latStr == "" && lngStr == "" {
+		return nil, nil, nil
+	}
+	if latStr == "" || lngStr == "" {
+		return nil, nil, errors.New("latitude and longitude must be provided together")
+	}
+
+	lat, err := strconv.ParseFloat(latStr, 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return nil, nil, errors.New("latitude must be a number between -90 and 90")
+	}
+	lng, err := strconv.ParseFloat(lngStr, 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return nil, nil, errors.New("longitude must be a number between -180 and 180")
+	}
+	return &lat, &lng, nil
+}
+
+// geocodeAddress mencoba mendapatkan koordinat dari venue/lokasi/district.
+// Kegagalan geocoding tidak menggagalkan request, event tetap tersimpan tanpa koordinat.
+func geocodeAddress(venue, location, district string) (*float64, *float64) {
+	var parts []string
+	for _, part := range []string{venue, location, district} {
+		if strings.TrimSpace(part) != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	address := strings.Join(parts, ", ")
+	lat, lng, err := config.Geocode(ctx, address)
+	if err != nil {
+		log.Printf("Geocoding failed for %q: %v", address, err)
+		return nil, nil
+	}
+	return &lat, &lng
+}
+
+type geoPoint struct{ lat, lng *float64 }
+
+func geoAddressKey(venue, location, district string) string {
+	return venue + "|" + location + "|" + district
+}
+
+// geocodeCached memanggil geocodeAddress sekali per alamat unik
+func geocodeCached(cache map[string]geoPoint, venue, location, district string) geoPoint {
+	key := geoAddressKey(venue, location, district)
+	point, ok := cache[key]
+	if !ok {
+		point.lat, point.lng = geocodeAddress(venue, location, district)
+		cache[key] = point
+	}
+	return point
+}
+
+// haversineExpr menghasilkan ekspresi SQL jarak (km) dari titik asal ke koordinat event.
+// Nilai lat/lng sudah divalidasi sebagai float sehingga aman disisipkan langsung.
+func haversineExpr(lat, lng float64) string {
+	return fmt.Sprintf(
+		"(6371 * ACOS(LEAST(1, COS(RADIANS(%[1]f)) * COS(RADIANS(events.latitude)) * COS(RADIANS(events.longitude) - RADIANS(%[2]f)) + SIN(RADIANS(%[1]f)) * SIN(RADIANS(events.latitude)))))",
+		lat, lng,
+	)
+}
+
+// haversineKm menghitung jarak (km) antara dua koordinat
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 6371 * 2 * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// parseGeoQuery mengisi parameter lokasi (lat, lng, radius, bbox) pada query listing
+func parseGeoQuery(c *fiber.Ctx, q *eventListQuery) error {
+	lat, lng, err := parseCoordinates(c.Query("lat"), c.Query("lng"))
+	if err != nil {
+		return err
+	}
+	q.Lat, q.Lng = lat, lng
+
+	if radius := c.Query("radius"); radius != "" {
+		if q.Lat == nil {
+			return errors.New("radius requires lat and lng")
+		}
+		v, err := strconv.ParseFloat(radius, 64)
+		if err != nil || v <= 0 || v > maxNearbyRadiusKm {
+			return fmt.Errorf("radius must be between 0 and %.0f km", maxNearbyRadiusKm)
+		}
+		q.RadiusKm = v
+	}
+
+	// bbox=min_lng,min_lat,max_lng,max_lat (urutan GeoJSON)
+	if bbox := c.Query("bbox"); bbox != "" {
+		parts := strings.Split(bbox, ",")
+		if len(parts) != 4 {
+			return errors.New("bbox must be min_lng,min_lat,max_lng,max_lat")
+		}
+		var values [4]float64
+		for i, part := range parts {
+			v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return errors.New("bbox must contain numbers only")
+			}
+			values[i] = v
+		}
+		if values[1] > values[3] || values[1] < -90 || values[3] > 90 {
+			return errors.New("bbox latitude range is invalid")
+		}
+		if values[0] < -180 || values[0] > 180 || values[2] < -180 || values[2] > 180 {
+			return errors.New("bbox longitude range is invalid")
+		}
+		q.BBox = &values
+	}
+
+	if q.Sort == "distance" && q.Lat == nil {
+		return errors.New("sort by distance requires lat and lng")
+	}
+	return nil
+}
+
+// GetNearbyEvents - Event publik di sekitar titik lat/lng dalam radius (km), diurutkan dari yang terdekat
+func GetNearbyEvents(c *fiber.Ctx) error {
+	if c.Query("lat") == "" || c.Query("lng") == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "lat and lng are required",
+		})
+	}
+
+	query, err := parseEventListQuery(c, "distance")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if query.RadiusKm == 0 {
+		query.RadiusKm = defaultNearbyRadiusKm
+	}
+
+	return respondEventList(c, query)
+}
+
+// GetEventsInBounds - Event publik di dalam bounding box peta
+func GetEventsInBounds(c *fiber.Ctx) error {
+	if c.Query("bbox") == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "bbox is required (min_lng,min_lat,max_lng,max_lat)",
+		})
+	}
+
+	query, err := parseEventListQuery(c, "date")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return respondEventList(c, query)
+}
+
+func respondEventList(c *fiber.Ctx, query eventListQuery) error {
+	events, pagination, err := listPublicEvents(query)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch events",
+		})
+	}
+
+	if events == nil {
+		events = []models.Event{}
+	}
+	if err := localizeEvents(config.DB, c, events); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch events",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":    "Events retrieved successfully",
+		"events":     events,
+		"pagination": pagination,
+	})
+}
@@ -0,0 +1,869 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	moderationEntityEvent     = "event"
+	moderationEntityOrganizer = "organizer"
+	moderationEntityVenue     = "venue"
+)
+
+// moderationReasonCodes adalah alasan penolakan standar. "other" wajib disertai comment.
+var moderationReasonCodes = map[string]string{
+	"incomplete_information": "Required information is missing or incomplete",
+	"invalid_documents":      "Identity or supporting documents are invalid or unreadable",
+	"misleading_information": "Details are misleading or inconsistent",
+	"prohibited_content":     "Content is not allowed on the platform",
+	"duplicate":              "Duplicate of an existing submission",
+	"policy_violation":       "Violates platform terms or policies",
+	"other":                  "Other (see comment)",
+}
+
+var errInvalidModeration = errors.New("invalid moderation decision")
+
+// errModerationAssigned dikembalikan jika item sedang di-assign ke admin lain
+var errModerationAssigned = errors.New("moderation item is assigned to another admin")
+
+type ModerationDecisionRequest struct {
+	Decision   string `json:"decision"` // approve, reject
+	ReasonCode string `json:"reason_code"`
+	Comment    string `json:"comment"`
+}
+
+type ModerationAssignRequest struct {
+	AdminID string `json:"admin_id"` // kosong = lepaskan assignment
+}
+
+// moderationSLA adalah target waktu keputusan, bisa diatur lewat MODERATION_SLA_HOURS (default 48 jam)
+func moderationSLA() time.Duration {
+	if v, err := strconv.Atoi(os.Getenv("MODERATION_SLA_HOURS")); err == nil && v > 0 {
+		return time.Duration(v) * time.Hour
+	}
+	return 48 * time.Hour
+}
+
+// validateModerationDecision mengembalikan status entity untuk keputusan yang valid
+func validateModerationDecision(decision, reasonCode, comment string) (string, error) {
+	switch decision {
+	case "approve", "approved":
+		return "approved", nil
+	case "reject", "rejected":
+		if _, ok := moderationReasonCodes[reasonCode]; !ok {
+			return "", fmt.Errorf("%w: reason_code is required for rejection, see /api/moderation/reasons", errInvalidModeration)
+		}
+		if reasonCode == "other" && comment == "" {
+			return "", fmt.Errorf("%w: comment is required when reason_code is 'other'", errInvalidModeration)
+		}
+		return "rejected", nil
+	}
+	return "", fmt.Errorf("%w: decision must be either 'approve' or 'reject'", errInvalidModeration)
+}
+
+func addModerationAction(tx *gorm.DB, itemID, actorID, action string, assignedTo *string, reasonCode, comment string) error {
+	return tx.Create(&models.ModerationAction{
+		ActionID:   utils.GenerateModerationActionID(),
+		ItemID:     itemID,
+		ActorID:    actorID,
+		Action:     action,
+		AssignedTo: assignedTo,
+		ReasonCode: reasonCode,
+		Comment:    comment,
+		CreatedAt:  time.Now(),
+	}).Error
+}
+
+// enqueueModeration memasukkan entity ke antrian. Kalau masih ada item pending, pengajuan ulang
+// hanya dicatat di riwayat supaya waktu tunggu (SLA) tetap dihitung dari pengajuan pertama.
+func enqueueModeration(tx *gorm.DB, entityType, entityID, submittedBy string) error {
+	var item models.ModerationItem
+	err := tx.Where("entity_type = ? AND entity_id = ? AND status = ?", entityType, entityID, "pending").
+		First(&item).Error
+	if err == nil {
+		return addModerationAction(tx, item.ItemID, submittedBy, "resubmitted", nil, "", "")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	item = models.ModerationItem{
+		ItemID:      utils.GenerateModerationItemID(),
+		EntityType:  entityType,
+		EntityID:    entityID,
+		SubmittedBy: submittedBy,
+		Status:      "pending",
+		SubmittedAt: time.Now(),
+	}
+	if err := tx.Create(&item).Error; err != nil {
+		return err
+	}
+	return addModerationAction(tx, item.ItemID, submittedBy, "submitted", nil, "", "")
+}
+
+// withdrawModeration menutup item pending, misal karena event dihapus sebelum direview
+func withdrawModeration(tx *gorm.DB, entityType, entityID, actorID string) error {
+	var items []models.ModerationItem
+	if err := tx.Where("entity_type = ? AND entity_id = ? AND status = ?", entityType, entityID, "pending").
+		Find(&items).Error; err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := tx.Model(&item).Update("status", "withdrawn").Error; err != nil {
+			return err
+		}
+		if err := addModerationAction(tx, item.ItemID, actorID, "withdrawn", nil, "", ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moderationAssignedResponse - 409 untuk keputusan atas item milik admin lain
+func moderationAssignedResponse(c *fiber.Ctx, item *models.ModerationItem) error {
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"error":       "Moderation item is assigned to another admin",
+		"assigned_to": item.AssignedTo,
+	})
+}
+
+// decideModeration menerapkan keputusan ke entity (status event / register_status organizer)
+// dan menutup item antrian. Item harus sudah dikunci oleh caller.
+// Item yang di-assign ke admin lain hanya boleh diputuskan oleh admin tersebut.
+func decideModeration(tx *gorm.DB, c *fiber.Ctx, item *models.ModerationItem, actorID, status, reasonCode, comment string) error {
+	if item.AssignedTo != nil && *item.AssignedTo != actorID {
+		return errModerationAssigned
+	}
+	if status == "approved" {
+		reasonCode = ""
+	}
+	entityComment := comment
+	if entityComment == "" && reasonCode != "" {
+		entityComment = moderationReasonCodes[reasonCode]
+	}
+
+	switch item.EntityType {
+	case moderationEntityEvent:
+		if err := tx.Model(&models.Event{}).Where("event_id = ?", item.EntityID).Updates(map[string]interface{}{
+			"status":           status,
+			"approval_comment": entityComment,
+		}).Error; err != nil {
+			return err
+		}
+	case moderationEntityOrganizer:
+		if err := tx.Model(&models.User{}).Where("user_id = ?", item.EntityID).Updates(map[string]interface{}{
+			"register_status":  status,
+			"register_comment": entityComment,
+		}).Error; err != nil {
+			return err
+		}
+	case moderationEntityVenue:
+		if err := tx.Model(&models.Venue{}).Where("venue_id = ?", item.EntityID).Updates(map[string]interface{}{
+			"status":         status,
+			"review_comment": entityComment,
+			"updated_at":     time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		// Event yang sudah memakai venue usulan yang ditolak tetap menyimpan teks venue-nya
+		if status == "rejected" {
+			if err := tx.Model(&models.Event{}).Where("venue_id = ?", item.EntityID).
+				UpdateColumn("venue_id", nil).Error; err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown moderation entity type %q", item.EntityType)
+	}
+
+	now := time.Now()
+	item.Status = status
+	item.DecidedBy = &actorID
+	item.DecidedAt = &now
+	item.ReasonCode = reasonCode
+	item.Comment = comment
+	if err := tx.Model(item).Updates(map[string]interface{}{
+		"status":      status,
+		"decided_by":  actorID,
+		"decided_at":  now,
+		"reason_code": reasonCode,
+		"comment":     comment,
+	}).Error; err != nil {
+		return err
+	}
+
+	if err := addModerationAction(tx, item.ItemID, actorID, status, nil, reasonCode, comment); err != nil {
+		return err
+	}
+
+	return recordAudit(tx, c, actorID, "moderation."+status, item.EntityType, item.EntityID, fiber.Map{
+		"item_id":     item.ItemID,
+		"reason_code": reasonCode,
+		"comment":     comment,
+	})
+}
+
+// lockOpenModerationItem mengambil item pending sebuah entity (dibuat kalau belum ada) dengan row lock.
+// Dipakai endpoint verifikasi lama supaya keputusannya tetap tercatat di antrian.
+func lockOpenModerationItem(tx *gorm.DB, entityType, entityID, submittedBy string) (*models.ModerationItem, error) {
+	var item models.ModerationItem
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("entity_type = ? AND entity_id = ? AND status = ?", entityType, entityID, "pending").
+		First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := enqueueModeration(tx, entityType, entityID, submittedBy); err != nil {
+			return nil, err
+		}
+		err = tx.Where("entity_type = ? AND entity_id = ? AND status = ?", entityType, entityID, "pending").
+			First(&item).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// notifyModerationResult memberi tahu pengaju tentang hasil moderasi
+func notifyModerationResult(item models.ModerationItem) {
+	var title, message, eventID string
+	switch item.EntityType {
+	case moderationEntityEvent:
+		var event models.Event
+		if err := config.DB.Where("event_id = ?", item.EntityID).First(&event).Error; err != nil {
+			return
+		}
+		eventID = event.EventID
+		title = "Event " + item.Status
+		message = fmt.Sprintf("Your event %s has been %s.", event.Name, item.Status)
+	case moderationEntityOrganizer:
+		title = "Organizer registration " + item.Status
+		message = fmt.Sprintf("Your organizer registration has been %s.", item.Status)
+	case moderationEntityVenue:
+		var venue models.Venue
+		if err := config.DB.Where("venue_id = ?", item.EntityID).First(&venue).Error; err != nil {
+			return
+		}
+		title = "Venue proposal " + item.Status
+		message = fmt.Sprintf("Your venue proposal %s has been %s.", venue.Name, item.Status)
+	}
+	if item.Status == "rejected" {
+		message += " Reason: " + moderationReasonCodes[item.ReasonCode] + "."
+		if item.Comment != "" {
+			message += " " + item.Comment
+		}
+	}
+	notifyUsers(config.DB, []string{item.SubmittedBy}, "moderation_"+item.Status, title, message, eventID)
+}
+
+// attachModerationEntities mengisi ringkasan event/organizer/venue pada setiap item
+func attachModerationEntities(db *gorm.DB, items []models.ModerationItem) error {
+	var eventIDs, userIDs, venueIDs []string
+	for _, item := range items {
+		switch item.EntityType {
+		case moderationEntityEvent:
+			eventIDs = append(eventIDs, item.EntityID)
+		case moderationEntityVenue:
+			venueIDs = append(venueIDs, item.EntityID)
+		default:
+			userIDs = append(userIDs, item.EntityID)
+		}
+	}
+
+	events := map[string]fiber.Map{}
+	if len(eventIDs) > 0 {
+		var rows []models.Event
+		if err := db.Where("event_id IN ?", eventIDs).Find(&rows).Error; err != nil {
+			return err
+		}
+		for _, event := range rows {
+			events[event.EventID] = fiber.Map{
+				"event_id":        event.EventID,
+				"name":            event.Name,
+				"owner_id":        event.OwnerID,
+				"organization_id": event.OrganizationID,
+				"status":          event.Status,
+				"date_start":      event.DateStart,
+				"category":        event.Category,
+				"venue":           event.Venue,
+			}
+		}
+	}
+
+	users := map[string]fiber.Map{}
+	if len(userIDs) > 0 {
+		var rows []models.User
+		if err := db.Where("user_id IN ?", userIDs).Find(&rows).Error; err != nil {
+			return err
+		}
+		for _, user := range rows {
+			users[user.UserID] = fiber.Map{
+				"user_id":           user.UserID,
+				"username":          user.Username,
+				"name":              user.Name,
+				"email":             user.Email,
+				"organization":      user.Organization,
+				"organization_type": user.OrganizationType,
+				"ktp":               user.KTP,
+				"register_status":   user.RegisterStatus,
+			}
+		}
+	}
+
+	venues := map[string]fiber.Map{}
+	if len(venueIDs) > 0 {
+		var rows []models.Venue
+		if err := db.Where("venue_id IN ?", venueIDs).Find(&rows).Error; err != nil {
+			return err
+		}
+		for _, venue := range rows {
+			venues[venue.VenueID] = fiber.Map{
+				"venue_id":     venue.VenueID,
+				"name":         venue.Name,
+				"address":      venue.Address,
+				"district":     venue.District,
+				"latitude":     venue.Latitude,
+				"longitude":    venue.Longitude,
+				"capacity":     venue.Capacity,
+				"facilities":   venue.Facilities,
+				"seat_map_url": venue.SeatMapURL,
+				"status":       venue.Status,
+			}
+		}
+	}
+
+	for i := range items {
+		var entity fiber.Map
+		var ok bool
+		switch items[i].EntityType {
+		case moderationEntityEvent:
+			entity, ok = events[items[i].EntityID]
+		case moderationEntityVenue:
+			entity, ok = venues[items[i].EntityID]
+		default:
+			entity, ok = users[items[i].EntityID]
+		}
+		if ok {
+			items[i].Entity = entity
+		}
+	}
+	return nil
+}
+
+// InitModerationQueue melakukan backfill antrian untuk event dan organizer pending
+// yang dibuat sebelum antrian moderasi ada.
+func InitModerationQueue(db *gorm.DB) error {
+	openItems := db.Model(&models.ModerationItem{}).Select("entity_id").Where("status = ?", "pending")
+
+	var events []models.Event
+	if err := db.Where("status = ? AND event_id NOT IN (?)", "pending", openItems).Find(&events).Error; err != nil {
+		return err
+	}
+	var organizers []models.User
+	if err := db.Where("role = ? AND register_status = ? AND user_id NOT IN (?)", "organizer", "pending", openItems).
+		Find(&organizers).Error; err != nil {
+		return err
+	}
+
+	var items []models.ModerationItem
+	for _, event := range events {
+		items = append(items, models.ModerationItem{
+			ItemID:      utils.GenerateModerationItemID(),
+			EntityType:  moderationEntityEvent,
+			EntityID:    event.EventID,
+			SubmittedBy: event.OwnerID,
+			Status:      "pending",
+			SubmittedAt: event.UpdatedAt,
+		})
+	}
+	for _, user := range organizers {
+		items = append(items, models.ModerationItem{
+			ItemID:      utils.GenerateModerationItemID(),
+			EntityType:  moderationEntityOrganizer,
+			EntityID:    user.UserID,
+			SubmittedBy: user.UserID,
+			Status:      "pending",
+			SubmittedAt: user.CreatedAt,
+		})
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	return db.CreateInBatches(&items, 200).Error
+}
+
+// GetModerationReasons - Daftar reason code penolakan
+func GetModerationReasons(c *fiber.Ctx) error {
+	reasons := make([]fiber.Map, 0, len(moderationReasonCodes))
+	for code, label := range moderationReasonCodes {
+		reasons = append(reasons, fiber.Map{"code": code, "label": label})
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		return reasons[i]["code"].(string) < reasons[j]["code"].(string)
+	})
+
+	return c.JSON(fiber.Map{
+		"message": "Moderation reasons retrieved successfully",
+		"reasons": reasons,
+	})
+}
+
+// GetModerationQueue - Antrian moderasi. Filter: entity_type, status (default pending),
+// assigned_to (me, unassigned, atau admin id), reason_code, overdue=true, sort (oldest|newest), limit, offset
+func GetModerationQueue(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	query := config.DB.Model(&models.ModerationItem{})
+
+	status := c.Query("status", "pending")
+	if status != "all" {
+		query = query.Where("status = ?", status)
+	}
+	if entityType := c.Query("entity_type"); entityType != "" {
+		if entityType != moderationEntityEvent && entityType != moderationEntityOrganizer && entityType != moderationEntityVenue {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "entity_type must be one of 'event', 'organizer' or 'venue'",
+			})
+		}
+		query = query.Where("entity_type = ?", entityType)
+	}
+	switch assignedTo := c.Query("assigned_to"); assignedTo {
+	case "":
+	case "me":
+		query = query.Where("assigned_to = ?", user.UserID)
+	case "unassigned":
+		query = query.Where("assigned_to IS NULL")
+	default:
+		query = query.Where("assigned_to = ?", assignedTo)
+	}
+	if reasonCode := c.Query("reason_code"); reasonCode != "" {
+		query = query.Where("reason_code = ?", reasonCode)
+	}
+	if c.Query("overdue") == "true" {
+		query = query.Where("status = ? AND submitted_at < ?", "pending", time.Now().Add(-moderationSLA()))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to count moderation queue",
+		})
+	}
+
+	order := "submitted_at ASC"
+	if c.Query("sort") == "newest" {
+		order = "submitted_at DESC"
+	}
+	limit, err := strconv.Atoi(c.Query("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		limit = 50
+	}
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	var items []models.ModerationItem
+	if err := query.Order(order).Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch moderation queue",
+		})
+	}
+	if err := attachModerationEntities(config.DB, items); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load moderation entities",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Moderation queue retrieved successfully",
+		"items":   items,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// GetModerationItem - Detail item beserta seluruh riwayat keputusan untuk entity yang sama
+func GetModerationItem(c *fiber.Ctx) error {
+	var item models.ModerationItem
+	if err := config.DB.Preload("History", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Where("item_id = ?", c.Params("id")).First(&item).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Moderation item not found",
+		})
+	}
+
+	items := []models.ModerationItem{item}
+	if err := attachModerationEntities(config.DB, items); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load moderation entity",
+		})
+	}
+
+	// Pengajuan sebelumnya untuk entity yang sama (misal ditolak lalu diajukan ulang)
+	var previous []models.ModerationItem
+	if err := config.DB.Preload("History", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Where("entity_type = ? AND entity_id = ? AND item_id <> ?", item.EntityType, item.EntityID, item.ItemID).
+		Order("submitted_at DESC").
+		Find(&previous).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load previous submissions",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":              "Moderation item retrieved successfully",
+		"item":                 items[0],
+		"previous_submissions": previous,
+	})
+}
+
+// ClaimModerationItem - Admin mengambil item pending yang belum di-assign
+func ClaimModerationItem(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	itemID := c.Params("id")
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	now := time.Now()
+	result := tx.Model(&models.ModerationItem{}).
+		Where("item_id = ? AND status = ? AND assigned_to IS NULL", itemID, "pending").
+		Updates(map[string]interface{}{
+			"assigned_to": user.UserID,
+			"assigned_at": now,
+		})
+	if result.Error != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to claim moderation item",
+		})
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		var item models.ModerationItem
+		if err := config.DB.Where("item_id = ?", itemID).First(&item).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Moderation item not found",
+			})
+		}
+		if item.Status != "pending" {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Moderation item has already been decided",
+			})
+		}
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":       "Moderation item is already assigned",
+			"assigned_to": item.AssignedTo,
+		})
+	}
+
+	if err := addModerationAction(tx, itemID, user.UserID, "claimed", &user.UserID, "", ""); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record moderation history",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Moderation item claimed",
+		"item_id": itemID,
+	})
+}
+
+// AssignModerationItem - Assign item pending ke admin tertentu, admin_id kosong untuk melepas
+func AssignModerationItem(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req ModerationAssignRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+
+	if req.AdminID != "" {
+		var assignee models.User
+		if err := config.DB.Where("user_id = ? AND role = ?", req.AdminID, "admin").First(&assignee).Error; err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "admin_id must refer to an admin user",
+			})
+		}
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	var item models.ModerationItem
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ?", c.Params("id")).
+		First(&item).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Moderation item not found",
+		})
+	}
+	if item.Status != "pending" {
+		tx.Rollback()
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Moderation item has already been decided",
+		})
+	}
+
+	updateData := map[string]interface{}{
+		"assigned_to": nil,
+		"assigned_at": nil,
+	}
+	action := "released"
+	var assignedTo *string
+	if req.AdminID != "" {
+		now := time.Now()
+		updateData["assigned_to"] = req.AdminID
+		updateData["assigned_at"] = now
+		action = "assigned"
+		assignedTo = &req.AdminID
+	}
+
+	if err := tx.Model(&item).Updates(updateData).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to assign moderation item",
+		})
+	}
+
+	if err := addModerationAction(tx, item.ItemID, user.UserID, action, assignedTo, "", ""); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record moderation history",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":     "Moderation item " + action,
+		"item_id":     item.ItemID,
+		"assigned_to": assignedTo,
+	})
+}
+
+// DecideModerationItem - Approve atau reject item. Item yang sudah di-claim admin lain tidak bisa diputus.
+func DecideModerationItem(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req ModerationDecisionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	status, err := validateModerationDecision(req.Decision, req.ReasonCode, req.Comment)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	var item models.ModerationItem
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ?", c.Params("id")).
+		First(&item).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Moderation item not found",
+		})
+	}
+	if item.Status != "pending" {
+		tx.Rollback()
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Moderation item has already been decided",
+		})
+	}
+	if err := decideModeration(tx, c, &item, user.UserID, status, req.ReasonCode, req.Comment); err != nil {
+		tx.Rollback()
+		if errors.Is(err, errModerationAssigned) {
+			return moderationAssignedResponse(c, &item)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to apply moderation decision",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	if item.EntityType == moderationEntityEvent {
+		SyncEventLifecycleJobs(config.DB, item.EntityID)
+	}
+	notifyModerationResult(item)
+
+	return c.JSON(fiber.Map{
+		"message": "Moderation decision recorded",
+		"item":    item,
+	})
+}
+
+// GetModerationMetrics - Ukuran backlog, waktu keputusan, dan kepatuhan SLA.
+// ?days=30 menentukan rentang keputusan yang dihitung.
+func GetModerationMetrics(c *fiber.Ctx) error {
+	days, err := strconv.Atoi(c.Query("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		days = 30
+	}
+	now := time.Now()
+	since := now.AddDate(0, 0, -days)
+	sla := moderationSLA()
+
+	var pending []models.ModerationItem
+	if err := config.DB.Where("status = ?", "pending").Find(&pending).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch moderation backlog",
+		})
+	}
+
+	backlog := fiber.Map{}
+	for _, entityType := range []string{moderationEntityEvent, moderationEntityOrganizer, moderationEntityVenue} {
+		total, unassigned, overdue := 0, 0, 0
+		var oldest *time.Time
+		for i, item := range pending {
+			if item.EntityType != entityType {
+				continue
+			}
+			total++
+			if item.AssignedTo == nil {
+				unassigned++
+			}
+			if now.Sub(item.SubmittedAt) > sla {
+				overdue++
+			}
+			if oldest == nil || item.SubmittedAt.Before(*oldest) {
+				oldest = &pending[i].SubmittedAt
+			}
+		}
+		entry := fiber.Map{
+			"pending":    total,
+			"unassigned": unassigned,
+			"overdue":    overdue,
+		}
+		if oldest != nil {
+			entry["oldest_submitted_at"] = *oldest
+			entry["oldest_age_hours"] = roundHours(now.Sub(*oldest))
+		}
+		backlog[entityType] = entry
+	}
+
+	var decided []models.ModerationItem
+	if err := config.DB.Where("status IN ? AND decided_at >= ?", []string{"approved", "rejected"}, since).
+		Find(&decided).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch moderation decisions",
+		})
+	}
+
+	durations := make([]time.Duration, 0, len(decided))
+	byDecision := map[string]int{}
+	byReason := map[string]int{}
+	byAdmin := map[string]int{}
+	withinSLA := 0
+	for _, item := range decided {
+		duration := item.DecidedAt.Sub(item.SubmittedAt)
+		durations = append(durations, duration)
+		if duration <= sla {
+			withinSLA++
+		}
+		byDecision[item.Status]++
+		if item.ReasonCode != "" {
+			byReason[item.ReasonCode]++
+		}
+		if item.DecidedBy != nil {
+			byAdmin[*item.DecidedBy]++
+		}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	timeToDecision := fiber.Map{"count": len(durations)}
+	slaCompliance := 0.0
+	if len(durations) > 0 {
+		var sum time.Duration
+		for _, d := range durations {
+			sum += d
+		}
+		timeToDecision["average_hours"] = roundHours(sum / time.Duration(len(durations)))
+		timeToDecision["median_hours"] = roundHours(percentileDuration(durations, 0.5))
+		timeToDecision["p90_hours"] = roundHours(percentileDuration(durations, 0.9))
+		timeToDecision["max_hours"] = roundHours(durations[len(durations)-1])
+		slaCompliance = float64(withinSLA) / float64(len(durations)) * 100
+	}
+
+	return c.JSON(fiber.Map{
+		"message":          "Moderation metrics retrieved successfully",
+		"period_days":      days,
+		"sla_hours":        sla.Hours(),
+		"backlog":          backlog,
+		"backlog_total":    len(pending),
+		"time_to_decision": timeToDecision,
+		"sla_compliance":   slaCompliance,
+		"decisions":        byDecision,
+		"reasons":          byReason,
+		"decisions_by":     byAdmin,
+	})
+}
+
+// percentileDuration mengambil persentil dari slice yang sudah terurut (nearest rank)
+func percentileDuration(sorted []time.Duration, p float64) time.Duration {
+	index := int(float64(len(sorted))*p+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
+
+func roundHours(d time.Duration) float64 {
+	return float64(int(d.Hours()*100+0.5)) / 100
+}
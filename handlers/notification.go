@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// notifyUsers membuat notifikasi yang sama untuk banyak user sekaligus.
+// Kegagalan hanya di-log supaya aksi utama (cancel, refund, dll) tidak ikut gagal.
+func notifyUsers(db *gorm.DB, userIDs []string, notifType, title, message, eventID string) {
+	seen := make(map[string]bool)
+	var notifications []models.Notification
+	for _, userID := range userIDs {
+		if userID == "" || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		notifications = append(notifications, models.Notification{
+			NotificationID: utils.GenerateNotificationID(),
+			UserID:         userID,
+			Type:           notifType,
+			Title:          title,
+			Message:        message,
+			EventID:        eventID,
+			CreatedAt:      time.Now(),
+		})
+	}
+
+	if len(notifications) == 0 {
+		return
+	}
+
+	if err := db.CreateInBatches(&notifications, 500).Error; err != nil {
+		log.Printf("Failed to create %d notifications (%s): %v", len(notifications), notifType, err)
+	}
+}
+
+// eventTicketHolderIDs mengembalikan user yang memegang tiket aktif/terpakai untuk sebuah event
+func eventTicketHolderIDs(db *gorm.DB, eventID string, statuses []string) ([]string, error) {
+	var ownerIDs []string
+	err := db.Model(&models.Ticket{}).
+		Where("event_id = ? AND status IN ?", eventID, statuses).
+		Distinct().
+		Pluck("owner_id", &ownerIDs).Error
+	return ownerIDs, err
+}
+
+// GetNotifications - Notifikasi user terbaru, ?unread=true untuk yang belum dibaca saja
+func GetNotifications(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	limit, err := strconv.Atoi(c.Query("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	query := config.DB.Where("user_id = ?", user.UserID)
+	if c.Query("unread") == "true" {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	if err := query.Order("created_at DESC").Limit(limit).Find(&notifications).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch notifications",
+		})
+	}
+
+	var unread int64
+	config.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", user.UserID).
+		Count(&unread)
+
+	return c.JSON(fiber.Map{
+		"message":       "Notifications retrieved successfully",
+		"notifications": notifications,
+		"unread_count":  unread,
+	})
+}
+
+// MarkNotificationRead - Tandai satu notifikasi sudah dibaca
+func MarkNotificationRead(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	result := config.DB.Model(&models.Notification{}).
+		Where("notification_id = ? AND user_id = ?", c.Params("id"), user.UserID).
+		Where("read_at IS NULL").
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update notification",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Notification marked as read",
+	})
+}
+
+// MarkAllNotificationsRead - Tandai semua notifikasi user sudah dibaca
+func MarkAllNotificationsRead(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	result := config.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", user.UserID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update notifications",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "All notifications marked as read",
+		"updated": result.RowsAffected,
+	})
+}
@@ -0,0 +1,929 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Permission yang dicek pada event/series milik organisasi
+const (
+	permEventView        = "event.view"
+	permEventManage      = "event.manage"
+	permEventDelete      = "event.delete"
+	permReportView       = "report.view"
+	permRefundView       = "refund.view"
+	permAttendeeExport   = "attendee.export"
+	permOrganizationEdit = "organization.edit"
+	permMemberManage     = "member.manage"
+)
+
+var organizationRolePermissions = map[string]map[string]bool{
+	"owner": {
+		permEventView: true, permEventManage: true, permEventDelete: true, permReportView: true,
+		permRefundView: true, permAttendeeExport: true, permOrganizationEdit: true, permMemberManage: true,
+	},
+	"manager": {
+		permEventView: true, permEventManage: true, permReportView: true, permAttendeeExport: true,
+		permOrganizationEdit: true,
+	},
+	"finance": {
+		permEventView: true, permReportView: true, permRefundView: true,
+	},
+	"viewer": {
+		permEventView: true,
+	},
+}
+
+type OrganizationRequest struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+type OrganizationMemberRequest struct {
+	Email    string `json:"email"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+type OrganizationInvitationResponse struct {
+	Decision string `json:"decision"` // accept, decline
+}
+
+// activeMembership mengembalikan keanggotaan aktif user di sebuah organisasi
+func activeMembership(db *gorm.DB, organizationID, userID string) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	if err := db.Where("organization_id = ? AND user_id = ? AND status = ?", organizationID, userID, "active").
+		First(&member).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// hasOrganizationPermission mengecek role user di organisasi. Admin selalu diizinkan.
+func hasOrganizationPermission(db *gorm.DB, user models.User, organizationID, permission string) bool {
+	if user.Role == "admin" {
+		return true
+	}
+	member, err := activeMembership(db, organizationID, user.UserID)
+	if err != nil {
+		return false
+	}
+	return organizationRolePermissions[member.Role][permission]
+}
+
+// canAccessOwned mengecek izin atas resource milik organisasi. Resource lama tanpa organisasi
+// hanya bisa diakses pembuatnya.
+func canAccessOwned(db *gorm.DB, user models.User, ownerID string, organizationID *string, permission string) bool {
+	if user.Role == "admin" {
+		return true
+	}
+	if organizationID == nil || *organizationID == "" {
+		return ownerID == user.UserID
+	}
+	return hasOrganizationPermission(db, user, *organizationID, permission)
+}
+
+func canAccessEvent(db *gorm.DB, user models.User, event models.Event, permission string) bool {
+	return canAccessOwned(db, user, event.OwnerID, event.OrganizationID, permission)
+}
+
+func canAccessSeries(db *gorm.DB, user models.User, series models.EventSeries, permission string) bool {
+	return canAccessOwned(db, user, series.OwnerID, series.OrganizationID, permission)
+}
+
+// memberOrganizationIDs mengembalikan organisasi tempat user menjadi anggota aktif
+func memberOrganizationIDs(db *gorm.DB, userID string) ([]string, error) {
+	var organizationIDs []string
+	err := db.Model(&models.OrganizationMember{}).
+		Where("user_id = ? AND status = ?", userID, "active").
+		Pluck("organization_id", &organizationIDs).Error
+	return organizationIDs, err
+}
+
+// ownedByUserOrOrganizations membatasi query event/series ke milik user atau organisasinya
+func ownedByUserOrOrganizations(db *gorm.DB, userID string, organizationIDs []string) *gorm.DB {
+	if len(organizationIDs) == 0 {
+		return db.Where("owner_id = ?", userID)
+	}
+	return db.Where("owner_id = ? OR organization_id IN ?", userID, organizationIDs)
+}
+
+// personalOrganization mengembalikan organisasi default milik user, dibuat dari field
+// Organization di profil kalau belum ada.
+func personalOrganization(db *gorm.DB, user models.User) (models.Organization, error) {
+	var organization models.Organization
+	err := db.Joins("JOIN organization_members om ON om.organization_id = organizations.organization_id").
+		Where("organizations.created_by = ? AND om.user_id = ? AND om.role = ? AND om.status = ?", user.UserID, user.UserID, "owner", "active").
+		Order("organizations.created_at ASC").
+		First(&organization).Error
+	if err == nil {
+		return organization, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return organization, err
+	}
+
+	organization = models.Organization{
+		OrganizationID: utils.GenerateOrganizationID(),
+		Name:           firstNonEmpty(user.Organization, user.Name, user.Username),
+		Type:           user.OrganizationType,
+		Description:    user.OrganizationDescription,
+		CreatedBy:      user.UserID,
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&organization).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.OrganizationMember{
+			MemberID:       utils.GenerateOrganizationMemberID(),
+			OrganizationID: organization.OrganizationID,
+			UserID:         user.UserID,
+			Role:           "owner",
+			Status:         "active",
+			InvitedBy:      user.UserID,
+			JoinedAt:       &organization.CreatedAt,
+		}).Error
+	})
+	return organization, err
+}
+
+// resolveEventOrganization menentukan organisasi pemilik event baru. Tanpa organization_id,
+// event masuk ke organisasi default milik pembuatnya.
+func resolveEventOrganization(db *gorm.DB, user models.User, organizationID string) (string, int, error) {
+	if organizationID == "" {
+		organization, err := personalOrganization(db, user)
+		if err != nil {
+			return "", fiber.StatusInternalServerError, errors.New("Failed to resolve organization")
+		}
+		return organization.OrganizationID, 0, nil
+	}
+
+	var organization models.Organization
+	if err := db.Where("organization_id = ?", organizationID).First(&organization).Error; err != nil {
+		return "", fiber.StatusNotFound, errors.New("Organization not found")
+	}
+	if !hasOrganizationPermission(db, user, organization.OrganizationID, permEventManage) {
+		return "", fiber.StatusForbidden, errors.New("Not authorized to create events for this organization")
+	}
+	return organization.OrganizationID, 0, nil
+}
+
+// InitOrganizations memindahkan organizer lama (satu user = satu organisasi) ke entitas Organization
+// dan menautkan event/series mereka yang belum punya organisasi.
+func InitOrganizations(db *gorm.DB) error {
+	var ownerIDs []string
+	if err := db.Model(&models.Event{}).
+		Where("organization_id IS NULL").
+		Distinct().
+		Pluck("owner_id", &ownerIDs).Error; err != nil {
+		return err
+	}
+
+	var seriesOwnerIDs []string
+	if err := db.Model(&models.EventSeries{}).
+		Where("organization_id IS NULL").
+		Distinct().
+		Pluck("owner_id", &seriesOwnerIDs).Error; err != nil {
+		return err
+	}
+
+	var users []models.User
+	if err := db.Where("role = ? OR user_id IN ?", "organizer", append(ownerIDs, seriesOwnerIDs...)).
+		Find(&users).Error; err != nil {
+		return err
+	}
+
+	migrated := 0
+	for _, user := range users {
+		organization, err := personalOrganization(db, user)
+		if err != nil {
+			return err
+		}
+		result := db.Model(&models.Event{}).
+			Where("owner_id = ? AND organization_id IS NULL", user.UserID).
+			Update("organization_id", organization.OrganizationID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := db.Model(&models.EventSeries{}).
+			Where("owner_id = ? AND organization_id IS NULL", user.UserID).
+			Update("organization_id", organization.OrganizationID).Error; err != nil {
+			return err
+		}
+		migrated += int(result.RowsAffected)
+	}
+
+	log.Println(" --  Organizations ensured for " + strconv.Itoa(len(users)) + " organizers, " + strconv.Itoa(migrated) + " events linked")
+	return nil
+}
+
+// countActiveOwners dipakai untuk memastikan organisasi tidak pernah kehilangan owner terakhir
+func countActiveOwners(tx *gorm.DB, organizationID string) (int64, error) {
+	var count int64
+	err := tx.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND role = ? AND status = ?", organizationID, "owner", "active").
+		Count(&count).Error
+	return count, err
+}
+
+// CreateOrganization - Membuat organisasi baru, pembuatnya otomatis menjadi owner
+func CreateOrganization(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req OrganizationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "name is required",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	now := time.Now()
+	organization := models.Organization{
+		OrganizationID: utils.GenerateOrganizationID(),
+		Name:           req.Name,
+		Type:           req.Type,
+		Description:    req.Description,
+		CreatedBy:      user.UserID,
+	}
+	if err := tx.Create(&organization).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create organization",
+		})
+	}
+
+	owner := models.OrganizationMember{
+		MemberID:       utils.GenerateOrganizationMemberID(),
+		OrganizationID: organization.OrganizationID,
+		UserID:         user.UserID,
+		Role:           "owner",
+		Status:         "active",
+		InvitedBy:      user.UserID,
+		JoinedAt:       &now,
+	}
+	if err := tx.Create(&owner).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create organization owner",
+		})
+	}
+
+	if err := recordAudit(tx, c, user.UserID, "organization.create", "organization", organization.OrganizationID, req); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":      "Organization created successfully",
+		"organization": organization,
+	})
+}
+
+// GetMyOrganizations - Organisasi tempat user menjadi anggota beserta undangan yang belum dijawab
+func GetMyOrganizations(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var memberships []models.OrganizationMember
+	if err := config.DB.Preload("Organization").
+		Where("user_id = ?", user.UserID).
+		Order("created_at ASC").
+		Find(&memberships).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch organizations",
+		})
+	}
+
+	organizations := []models.OrganizationMember{}
+	invitations := []models.OrganizationMember{}
+	for _, membership := range memberships {
+		if membership.Status == "invited" {
+			invitations = append(invitations, membership)
+		} else {
+			organizations = append(organizations, membership)
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message":       "Organizations retrieved successfully",
+		"organizations": organizations,
+		"invitations":   invitations,
+	})
+}
+
+// GetOrganization - Detail organisasi dan anggotanya, hanya untuk anggota atau admin
+func GetOrganization(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var organization models.Organization
+	if err := config.DB.Preload("Members", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Preload("Members.User").
+		Where("organization_id = ?", c.Params("id")).
+		First(&organization).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Organization not found",
+		})
+	}
+
+	if !hasOrganizationPermission(config.DB, user, organization.OrganizationID, permEventView) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to view this organization",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":      "Organization retrieved successfully",
+		"organization": organization,
+	})
+}
+
+// UpdateOrganization - Edit profil organisasi (owner dan manager)
+func UpdateOrganization(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var organization models.Organization
+	if err := config.DB.Where("organization_id = ?", c.Params("id")).First(&organization).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Organization not found",
+		})
+	}
+
+	if !hasOrganizationPermission(config.DB, user, organization.OrganizationID, permOrganizationEdit) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to update this organization",
+		})
+	}
+
+	var req OrganizationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+
+	updateData := map[string]interface{}{}
+	if name := strings.TrimSpace(req.Name); name != "" {
+		updateData["name"] = name
+	}
+	if req.Type != "" {
+		updateData["type"] = req.Type
+	}
+	if req.Description != "" {
+		updateData["description"] = req.Description
+	}
+	if len(updateData) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "No fields to update",
+		})
+	}
+
+	// Audit mencatat nilai lama dan baru dari field yang benar-benar berubah
+	previous := map[string]string{
+		"name":        organization.Name,
+		"type":        organization.Type,
+		"description": organization.Description,
+	}
+	changes := fiber.Map{}
+	for field, value := range updateData {
+		if previous[field] != value {
+			changes[field] = fiber.Map{"from": previous[field], "to": value}
+		}
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	if err := tx.Model(&organization).Updates(updateData).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update organization",
+		})
+	}
+
+	if err := recordAudit(tx, c, user.UserID, "organization.update", "organization", organization.OrganizationID, changes); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":      "Organization updated successfully",
+		"organization": organization,
+	})
+}
+
+// InviteOrganizationMember - Owner mengundang user (berdasarkan email atau username) dengan role tertentu
+func InviteOrganizationMember(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	organizationID := c.Params("id")
+
+	var organization models.Organization
+	if err := config.DB.Where("organization_id = ?", organizationID).First(&organization).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Organization not found",
+		})
+	}
+
+	if !hasOrganizationPermission(config.DB, user, organization.OrganizationID, permMemberManage) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to manage members of this organization",
+		})
+	}
+
+	var req OrganizationMemberRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if _, ok := organizationRolePermissions[req.Role]; !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "role must be one of: owner, manager, finance, viewer",
+		})
+	}
+
+	var invitee models.User
+	query := config.DB
+	switch {
+	case strings.TrimSpace(req.Email) != "":
+		query = query.Where("email = ?", strings.TrimSpace(req.Email))
+	case strings.TrimSpace(req.Username) != "":
+		query = query.Where("username = ?", strings.TrimSpace(req.Username))
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "email or username is required",
+		})
+	}
+	if err := query.First(&invitee).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	var existing int64
+	config.DB.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", organization.OrganizationID, invitee.UserID).
+		Count(&existing)
+	if existing > 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "User is already a member or has a pending invitation",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	member := models.OrganizationMember{
+		MemberID:       utils.GenerateOrganizationMemberID(),
+		OrganizationID: organization.OrganizationID,
+		UserID:         invitee.UserID,
+		Role:           req.Role,
+		Status:         "invited",
+		InvitedBy:      user.UserID,
+	}
+	if err := tx.Create(&member).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to invite member",
+		})
+	}
+
+	if err := recordAudit(tx, c, user.UserID, "organization.member_invite", "organization", organization.OrganizationID, fiber.Map{
+		"member_id": member.MemberID,
+		"user_id":   invitee.UserID,
+		"role":      member.Role,
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	notifyUsers(config.DB, []string{invitee.UserID}, "organization_invitation", "Organization invitation",
+		"You have been invited to join "+organization.Name+" as "+member.Role+".", "")
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Invitation sent successfully",
+		"member":  member,
+	})
+}
+
+// RespondToOrganizationInvitation - User menerima (accept) atau menolak (decline) undangan organisasi
+func RespondToOrganizationInvitation(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req OrganizationInvitationResponse
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if req.Decision != "accept" && req.Decision != "decline" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "decision must be either 'accept' or 'decline'",
+		})
+	}
+
+	var member models.OrganizationMember
+	if err := config.DB.Where("member_id = ? AND user_id = ? AND status = ?", c.Params("member_id"), user.UserID, "invited").
+		First(&member).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Invitation not found",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	if req.Decision == "accept" {
+		now := time.Now()
+		if err := tx.Model(&member).Updates(map[string]interface{}{
+			"status":    "active",
+			"joined_at": now,
+		}).Error; err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to accept invitation",
+			})
+		}
+	} else if err := tx.Delete(&member).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to decline invitation",
+		})
+	}
+
+	if err := recordAudit(tx, c, user.UserID, "organization.invitation_"+req.Decision, "organization", member.OrganizationID, fiber.Map{
+		"member_id": member.MemberID,
+		"role":      member.Role,
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	message := "Invitation accepted"
+	if req.Decision == "decline" {
+		message = "Invitation declined"
+	}
+
+	return c.JSON(fiber.Map{
+		"message": message,
+		"member":  member,
+	})
+}
+
+// UpdateOrganizationMember - Owner mengganti role anggota. Owner terakhir tidak bisa diturunkan.
+func UpdateOrganizationMember(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	organizationID := c.Params("id")
+
+	if !hasOrganizationPermission(config.DB, user, organizationID, permMemberManage) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to manage members of this organization",
+		})
+	}
+
+	var req OrganizationMemberRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if _, ok := organizationRolePermissions[req.Role]; !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "role must be one of: owner, manager, finance, viewer",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	// Kunci semua anggota organisasi supaya dua owner tidak saling menurunkan bersamaan
+	var members []models.OrganizationMember
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("organization_id = ?", organizationID).
+		Find(&members).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch members",
+		})
+	}
+
+	var member *models.OrganizationMember
+	for i := range members {
+		if members[i].MemberID == c.Params("member_id") {
+			member = &members[i]
+		}
+	}
+	if member == nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Member not found",
+		})
+	}
+
+	if member.Role == "owner" && req.Role != "owner" && member.Status == "active" {
+		owners, err := countActiveOwners(tx, organizationID)
+		if err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to check organization owners",
+			})
+		}
+		if owners <= 1 {
+			tx.Rollback()
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Organization must keep at least one owner",
+			})
+		}
+	}
+
+	previousRole := member.Role
+	if err := tx.Model(member).Update("role", req.Role).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update member",
+		})
+	}
+
+	if err := recordAudit(tx, c, user.UserID, "organization.member_update", "organization", organizationID, fiber.Map{
+		"member_id": member.MemberID,
+		"user_id":   member.UserID,
+		"from_role": previousRole,
+		"to_role":   req.Role,
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Member updated successfully",
+		"member":  member,
+	})
+}
+
+// RemoveOrganizationMember - Owner mengeluarkan anggota, atau anggota keluar sendiri
+func RemoveOrganizationMember(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	organizationID := c.Params("id")
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	var members []models.OrganizationMember
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("organization_id = ?", organizationID).
+		Find(&members).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch members",
+		})
+	}
+
+	var member *models.OrganizationMember
+	for i := range members {
+		if members[i].MemberID == c.Params("member_id") {
+			member = &members[i]
+		}
+	}
+	if member == nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Member not found",
+		})
+	}
+
+	if member.UserID != user.UserID && !hasOrganizationPermission(tx, user, organizationID, permMemberManage) {
+		tx.Rollback()
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to manage members of this organization",
+		})
+	}
+
+	if member.Role == "owner" && member.Status == "active" {
+		owners, err := countActiveOwners(tx, organizationID)
+		if err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to check organization owners",
+			})
+		}
+		if owners <= 1 {
+			tx.Rollback()
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Organization must keep at least one owner",
+			})
+		}
+	}
+
+	if err := tx.Delete(member).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to remove member",
+		})
+	}
+
+	if err := recordAudit(tx, c, user.UserID, "organization.member_remove", "organization", organizationID, fiber.Map{
+		"member_id": member.MemberID,
+		"user_id":   member.UserID,
+		"role":      member.Role,
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Member removed successfully",
+	})
+}
+
+// GetOrganizationEvents - Semua event milik organisasi
+func GetOrganizationEvents(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	organizationID := c.Params("id")
+
+	if !hasOrganizationPermission(config.DB, user, organizationID, permEventView) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to view events of this organization",
+		})
+	}
+
+	var events []models.Event
+	if err := config.DB.Preload("Owner").Preload("TicketCategories").
+		Where("organization_id = ?", organizationID).
+		Order("created_at DESC").
+		Find(&events).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch organization events",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Events retrieved successfully",
+		"events":  events,
+	})
+}
+
+// GetOrganizationReport - Ringkasan penjualan dan refund seluruh event organisasi (payout bersih)
+func GetOrganizationReport(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	organizationID := c.Params("id")
+
+	if !hasOrganizationPermission(config.DB, user, organizationID, permReportView) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to view reports of this organization",
+		})
+	}
+
+	var events []models.Event
+	if err := config.DB.Where("organization_id = ?", organizationID).
+		Order("date_start DESC").
+		Find(&events).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch organization events",
+		})
+	}
+
+	eventIDs := make([]string, 0, len(events))
+	var totalSales float64
+	var totalTicketsSold uint
+	eventReports := make([]fiber.Map, 0, len(events))
+	for _, event := range events {
+		eventIDs = append(eventIDs, event.EventID)
+		totalSales += event.TotalSales
+		totalTicketsSold += event.TotalTicketsSold
+		eventReports = append(eventReports, fiber.Map{
+			"event_id":           event.EventID,
+			"name":               event.Name,
+			"status":             event.Status,
+			"date_start":         event.DateStart,
+			"total_sales":        event.TotalSales,
+			"total_tickets_sold": event.TotalTicketsSold,
+		})
+	}
+
+	type refundTotal struct {
+		Status string
+		Count  int64
+		Amount float64
+	}
+	var refundTotals []refundTotal
+	if len(eventIDs) > 0 {
+		if err := config.DB.Model(&models.Refund{}).
+			Select("status, COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount").
+			Where("event_id IN ?", eventIDs).
+			Group("status").
+			Scan(&refundTotals).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch refund summary",
+			})
+		}
+	}
+
+	refunds := fiber.Map{}
+	for _, total := range refundTotals {
+		refunds[total.Status] = fiber.Map{"count": total.Count, "amount": total.Amount}
+	}
+
+	// TotalSales event sudah dikurangi refund, jadi payout bersih = total_sales
+	return c.JSON(fiber.Map{
+		"message":            "Organization report retrieved successfully",
+		"organization_id":    organizationID,
+		"total_events":       len(events),
+		"total_sales":        totalSales,
+		"total_tickets_sold": totalTicketsSold,
+		"refunds":            refunds,
+		"events":             eventReports,
+	})
+}
@@ -65,6 +65,19 @@ func PaymentMidtrans(c *fiber.Ctx) error {
 			})
 		}
 
+		if err := checkSaleWindow(config.DB, ticketCategory); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		// Validasi kode akses untuk category hidden
+		if _, err := resolveAccessCodeByID(config.DB, ticketCategory, item.AccessCodeID); err != nil {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Access code rejected for ticket category " + ticketCategory.Name + ": " + err.Error(),
+			})
+		}
+
 		total += item.PriceTotal
 
 		// Prepare transaction detail
@@ -72,6 +85,7 @@ func PaymentMidtrans(c *fiber.Ctx) error {
 			TransactionDetailID: utils.GenerateTransactionDetailID(),
 			TicketCategoryID:    item.TicketCategoryID,
 			OwnerID:             user.UserID,
+			AccessCodeID:        item.AccessCodeID,
 			Quantity:            item.Quantity,
 			Subtotal:            item.PriceTotal,
 		}
@@ -115,6 +129,16 @@ func PaymentMidtrans(c *fiber.Ctx) error {
 			})
 		}
 
+		// Satu checkout dihitung satu pemakaian kode akses
+		if detail.AccessCodeID != "" {
+			if err := consumeAccessCode(tx, detail.AccessCodeID); err != nil {
+				tx.Rollback()
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error": "Access code rejected: " + err.Error(),
+				})
+			}
+		}
+
 		// Get event ID from ticket category untuk membuat tickets
 		var ticketCategory models.TicketCategory
 		if err := tx.First(&ticketCategory, "ticket_category_id = ?", detail.TicketCategoryID).Error; err != nil {
@@ -344,6 +368,9 @@ func handleSettlement(c *fiber.Ctx, orderID string, notifPayload map[string]inte
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch transaction details"})
 	}
 
+	// Event yang dibatalkan saat pembayaran masih pending langsung di-refund setelah settlement
+	cancelledEvents := make(map[string]bool)
+
 	// Process each transaction detail
 	for _, detail := range transactionDetails {
 		// Update ticket category sold count
@@ -377,6 +404,9 @@ func handleSettlement(c *fiber.Ctx, orderID string, notifPayload map[string]inte
 			log.Printf("Failed to update event sold count: %v", err)
 			return c.Status(500).JSON(fiber.Map{"error": "Failed to update event sold count"})
 		}
+		if event.Status == "cancelled" {
+			cancelledEvents[event.EventID] = true
+		}
 
 		// Update tickets status from pending to active
 		if err := tx.Model(&models.Ticket{}).
@@ -408,6 +438,14 @@ func handleSettlement(c *fiber.Ctx, orderID string, notifPayload map[string]inte
 		}
 	}
 
+	for eventID := range cancelledEvents {
+		if _, err := refundEventOrders(tx, eventID, "", "event_cancelled", "cancelled"); err != nil {
+			tx.Rollback()
+			log.Printf("Failed to refund order for cancelled event %s: %v", eventID, err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to refund cancelled event"})
+		}
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		log.Printf("Failed to commit transaction: %v", err)
@@ -463,13 +501,22 @@ func handleFailure(c *fiber.Ctx, orderID string, status string) error {
 	}
 
 	for _, detail := range transactionDetails {
-		if err := tx.Model(&models.Ticket{}).
+		ticketResult := tx.Model(&models.Ticket{}).
 			Where("ticket_category_id = ? AND owner_id = ? AND status = ?",
 				detail.TicketCategoryID, detail.OwnerID, "pending").
-			Update("status", "payment_failed").Error; err != nil {
+			Update("status", "payment_failed")
+		if ticketResult.Error != nil {
 			tx.Rollback()
 			return c.Status(500).JSON(fiber.Map{"error": "Failed to update ticket status"})
 		}
+
+		// Kembalikan pemakaian kode akses hanya jika tiket pending benar-benar dibatalkan
+		if detail.AccessCodeID != "" && ticketResult.RowsAffected > 0 {
+			if err := releaseAccessCode(tx, detail.AccessCodeID); err != nil {
+				tx.Rollback()
+				return c.Status(500).JSON(fiber.Map{"error": "Failed to release access code"})
+			}
+		}
 	}
 
 	if err := tx.Commit().Error; err != nil {
@@ -0,0 +1,385 @@
+package handlers
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Bobot sinyal rekomendasi. Pembelian lebih kuat dari like, dan interaksi lama meluruh
+// dengan half-life interactionHalfLife.
+const (
+	likeSignalWeight     = 1.0
+	purchaseSignalWeight = 2.0
+	interactionHalfLife  = 90 * 24 * time.Hour
+
+	coOccurrenceWeight = 0.5
+	affinityWeight     = 0.3
+	recencyWeight      = 0.1
+	popularityWeight   = 0.1
+
+	maxRecommendationCandidates = 500
+	maxNeighborUsers            = 1000
+)
+
+var purchasedTicketStatuses = []string{"active", "used"}
+
+type eventInteraction struct {
+	UserID    string
+	EventID   string
+	CreatedAt *time.Time
+}
+
+type eventRecommendation struct {
+	Event   models.Event `json:"event"`
+	Score   float64      `json:"score"`
+	Reasons []string     `json:"reasons"`
+}
+
+// decayedWeight menurunkan bobot interaksi berdasarkan umurnya
+func decayedWeight(base float64, at *time.Time, now time.Time) float64 {
+	if at == nil || at.IsZero() {
+		return base * 0.5
+	}
+	age := now.Sub(*at)
+	if age < 0 {
+		age = 0
+	}
+	return base * math.Pow(0.5, float64(age)/float64(interactionHalfLife))
+}
+
+// loadInteractions mengambil like dan pembelian (tiket non-komplimen) untuk sekumpulan user
+func loadInteractions(db *gorm.DB, userIDs []string) ([]eventInteraction, []eventInteraction, error) {
+	var likes []eventInteraction
+	if err := db.Model(&models.EventLike{}).
+		Select("user_id, event_id, created_at").
+		Where("user_id IN ?", userIDs).
+		Scan(&likes).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var purchases []eventInteraction
+	if err := db.Model(&models.Ticket{}).
+		Select("owner_id AS user_id, event_id, MAX(created_at) AS created_at").
+		Where("owner_id IN ? AND status IN ? AND is_complimentary = ?", userIDs, purchasedTicketStatuses, false).
+		Group("owner_id, event_id").
+		Scan(&purchases).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return likes, purchases, nil
+}
+
+// eventAudienceSizes memperkirakan jumlah user yang berinteraksi dengan tiap event (liker + pembeli)
+func eventAudienceSizes(db *gorm.DB, eventIDs []string) (map[string]float64, error) {
+	type countRow struct {
+		EventID string
+		Total   int64
+	}
+	sizes := make(map[string]float64, len(eventIDs))
+	if len(eventIDs) == 0 {
+		return sizes, nil
+	}
+
+	var likeCounts []countRow
+	if err := db.Model(&models.EventLike{}).
+		Select("event_id, COUNT(*) AS total").
+		Where("event_id IN ?", eventIDs).
+		Group("event_id").
+		Scan(&likeCounts).Error; err != nil {
+		return nil, err
+	}
+	var buyerCounts []countRow
+	if err := db.Model(&models.Ticket{}).
+		Select("event_id, COUNT(DISTINCT owner_id) AS total").
+		Where("event_id IN ? AND status IN ? AND is_complimentary = ?", eventIDs, purchasedTicketStatuses, false).
+		Group("event_id").
+		Scan(&buyerCounts).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range append(likeCounts, buyerCounts...) {
+		sizes[row.EventID] += float64(row.Total)
+	}
+	return sizes, nil
+}
+
+// neighborUserIDs mengembalikan user lain yang juga menyukai atau membeli event seed
+func neighborUserIDs(db *gorm.DB, seedEventIDs []string, excludeUserID string) ([]string, error) {
+	var likers []string
+	if err := db.Model(&models.EventLike{}).
+		Where("event_id IN ? AND user_id <> ?", seedEventIDs, excludeUserID).
+		Distinct().
+		Limit(maxNeighborUsers).
+		Pluck("user_id", &likers).Error; err != nil {
+		return nil, err
+	}
+	var buyers []string
+	if err := db.Model(&models.Ticket{}).
+		Where("event_id IN ? AND owner_id <> ? AND status IN ? AND is_complimentary = ?", seedEventIDs, excludeUserID, purchasedTicketStatuses, false).
+		Distinct().
+		Limit(maxNeighborUsers).
+		Pluck("owner_id", &buyers).Error; err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var neighbors []string
+	for _, userID := range append(likers, buyers...) {
+		if !seen[userID] && len(neighbors) < maxNeighborUsers {
+			seen[userID] = true
+			neighbors = append(neighbors, userID)
+		}
+	}
+	return neighbors, nil
+}
+
+// recommendEvents menghitung skor event mendatang untuk user. Tanpa riwayat interaksi
+// (cold start) skor hanya berasal dari popularitas dan kedekatan tanggal.
+func recommendEvents(db *gorm.DB, user models.User, limit int) ([]eventRecommendation, string, error) {
+	now := time.Now()
+
+	// 1. Sinyal milik user: bobot per event seed
+	likes, purchases, err := loadInteractions(db, []string{user.UserID})
+	if err != nil {
+		return nil, "", err
+	}
+	seedWeights := map[string]float64{}
+	for _, like := range likes {
+		seedWeights[like.EventID] += decayedWeight(likeSignalWeight, like.CreatedAt, now)
+	}
+	for _, purchase := range purchases {
+		seedWeights[purchase.EventID] += decayedWeight(purchaseSignalWeight, purchase.CreatedAt, now)
+	}
+	seedIDs := make([]string, 0, len(seedWeights))
+	for eventID := range seedWeights {
+		seedIDs = append(seedIDs, eventID)
+	}
+
+	// 2. Kandidat: event mendatang yang belum pernah disukai/dibeli user dan bukan miliknya
+	candidateQuery := db.Preload("Owner").Preload("TicketCategories", publicTicketCategories).
+		Where("status IN ? AND date_end >= ? AND owner_id <> ?", liveEventStatuses, now, user.UserID)
+	if len(seedIDs) > 0 {
+		candidateQuery = candidateQuery.Where("event_id NOT IN ?", seedIDs)
+	}
+	var candidates []models.Event
+	if err := candidateQuery.Order("date_start ASC").Limit(maxRecommendationCandidates).Find(&candidates).Error; err != nil {
+		return nil, "", err
+	}
+	if len(candidates) == 0 {
+		return []eventRecommendation{}, "none", nil
+	}
+
+	// Popularitas dan kedekatan tanggal dipakai di semua strategi
+	maxPopularity := 0.0
+	for _, event := range candidates {
+		maxPopularity = math.Max(maxPopularity, math.Log1p(float64(event.TotalLikes+event.TotalTicketsSold)))
+	}
+	baseScore := func(event models.Event) (float64, float64) {
+		popularity := 0.0
+		if maxPopularity > 0 {
+			popularity = math.Log1p(float64(event.TotalLikes+event.TotalTicketsSold)) / maxPopularity
+		}
+		daysUntil := math.Max(event.DateStart.Sub(now).Hours()/24, 0)
+		recency := 1 / (1 + daysUntil/30)
+		return popularity, recency
+	}
+
+	strategy := "personalized"
+	if len(seedIDs) == 0 {
+		strategy = "popular"
+	}
+
+	// 3. Co-occurrence item-to-item: cosine antara event seed dan kandidat dari user lain
+	coScores := map[string]float64{}
+	coSources := map[string]string{}
+	if strategy == "personalized" {
+		neighbors, err := neighborUserIDs(db, seedIDs, user.UserID)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(neighbors) > 0 {
+			neighborLikes, neighborPurchases, err := loadInteractions(db, neighbors)
+			if err != nil {
+				return nil, "", err
+			}
+			userEvents := map[string]map[string]bool{}
+			for _, interaction := range append(neighborLikes, neighborPurchases...) {
+				if userEvents[interaction.UserID] == nil {
+					userEvents[interaction.UserID] = map[string]bool{}
+				}
+				userEvents[interaction.UserID][interaction.EventID] = true
+			}
+
+			candidateSet := make(map[string]bool, len(candidates))
+			audienceIDs := append([]string{}, seedIDs...)
+			for _, event := range candidates {
+				candidateSet[event.EventID] = true
+				audienceIDs = append(audienceIDs, event.EventID)
+			}
+			audience, err := eventAudienceSizes(db, audienceIDs)
+			if err != nil {
+				return nil, "", err
+			}
+
+			coCounts := map[string]map[string]float64{} // seed -> kandidat -> jumlah user bersama
+			for _, events := range userEvents {
+				for seedID := range events {
+					if _, isSeed := seedWeights[seedID]; !isSeed {
+						continue
+					}
+					for eventID := range events {
+						if !candidateSet[eventID] {
+							continue
+						}
+						if coCounts[seedID] == nil {
+							coCounts[seedID] = map[string]float64{}
+						}
+						coCounts[seedID][eventID]++
+					}
+				}
+			}
+
+			bestContribution := map[string]float64{}
+			for seedID, counts := range coCounts {
+				for eventID, count := range counts {
+					denominator := math.Sqrt(math.Max(audience[seedID], count) * math.Max(audience[eventID], count))
+					if denominator == 0 {
+						continue
+					}
+					contribution := seedWeights[seedID] * count / denominator
+					coScores[eventID] += contribution
+					if contribution > bestContribution[eventID] {
+						bestContribution[eventID] = contribution
+						coSources[eventID] = seedID
+					}
+				}
+			}
+		}
+	}
+	maxCoScore := 0.0
+	for _, score := range coScores {
+		maxCoScore = math.Max(maxCoScore, score)
+	}
+
+	// 4. Afinitas kategori dan district dari event yang pernah disukai/dibeli
+	categoryShare := map[string]float64{}
+	districtShare := map[string]float64{}
+	if strategy == "personalized" {
+		var seedEvents []models.Event
+		if err := db.Select("event_id, category, district").Where("event_id IN ?", seedIDs).Find(&seedEvents).Error; err != nil {
+			return nil, "", err
+		}
+		total := 0.0
+		for _, event := range seedEvents {
+			weight := seedWeights[event.EventID]
+			total += weight
+			if event.Category != "" {
+				categoryShare[event.Category] += weight
+			}
+			if event.District != "" {
+				districtShare[event.District] += weight
+			}
+		}
+		if total > 0 {
+			for key := range categoryShare {
+				categoryShare[key] /= total
+			}
+			for key := range districtShare {
+				districtShare[key] /= total
+			}
+		}
+	}
+
+	recommendations := make([]eventRecommendation, 0, len(candidates))
+	for _, event := range candidates {
+		popularity, recency := baseScore(event)
+		reasons := []string{}
+		var score float64
+
+		if strategy == "popular" {
+			score = 0.7*popularity + 0.3*recency
+			reasons = append(reasons, "popular")
+		} else {
+			coScore := 0.0
+			if maxCoScore > 0 {
+				coScore = coScores[event.EventID] / maxCoScore
+			}
+			affinity := 0.7*categoryShare[event.Category] + 0.3*districtShare[event.District]
+			score = coOccurrenceWeight*coScore + affinityWeight*affinity + recencyWeight*recency + popularityWeight*popularity
+
+			if coScore > 0 {
+				reasons = append(reasons, "similar_to:"+coSources[event.EventID])
+			}
+			if categoryShare[event.Category] > 0 {
+				reasons = append(reasons, "category:"+event.Category)
+			}
+			if districtShare[event.District] > 0 {
+				reasons = append(reasons, "district:"+event.District)
+			}
+			if len(reasons) == 0 {
+				reasons = append(reasons, "popular")
+			}
+		}
+		if recency >= 0.5 {
+			reasons = append(reasons, "starting_soon")
+		}
+
+		recommendations = append(recommendations, eventRecommendation{
+			Event:   event,
+			Score:   math.Round(score*10000) / 10000,
+			Reasons: reasons,
+		})
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Score > recommendations[j].Score
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, strategy, nil
+}
+
+// GetRecommendedEvents - Rekomendasi event mendatang berdasarkan like dan pembelian user. ?limit= (default 10, max 50)
+func GetRecommendedEvents(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	limit, err := strconv.Atoi(c.Query("limit", "10"))
+	if err != nil || limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	recommendations, strategy, err := recommendEvents(config.DB, user, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to compute recommendations",
+		})
+	}
+
+	events := make([]models.Event, len(recommendations))
+	for i := range recommendations {
+		events[i] = recommendations[i].Event
+	}
+	if err := localizeEvents(config.DB, c, events); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to compute recommendations",
+		})
+	}
+	for i := range recommendations {
+		recommendations[i].Event = events[i]
+	}
+
+	return c.JSON(fiber.Map{
+		"message":         "Recommendations retrieved successfully",
+		"strategy":        strategy,
+		"recommendations": recommendations,
+	})
+}
@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+)
+
+const jobTypeRefund = "refund"
+
+type refundJobPayload struct {
+	RefundID string `json:"refund_id"`
+}
+
+// paidEventOrder adalah bagian transaksi paid yang membeli tiket untuk satu event
+type paidEventOrder struct {
+	TransactionID    string
+	OwnerID          string
+	TicketCategoryID string
+	Quantity         uint
+	Subtotal         float64
+}
+
+func init() {
+	RegisterJobHandler(jobTypeRefund, handleRefundJob)
+}
+
+// refundEventOrders membuat refund untuk setiap transaksi paid pada event (opsional hanya milik ownerID),
+// mengurangi statistik penjualan, dan mengubah status tiket non-komplimen milik pembeli menjadi ticketStatus.
+// Transaksi yang sudah punya refund untuk event ini dilewati sehingga aman dipanggil ulang.
+func refundEventOrders(tx *gorm.DB, eventID, ownerID, reason, ticketStatus string) ([]models.Refund, error) {
+	query := tx.Table("transaction_details td").
+		Select("td.transaction_id, td.owner_id, td.ticket_category_id, td.quantity, td.subtotal").
+		Joins("JOIN ticket_categories tc ON tc.ticket_category_id = td.ticket_category_id").
+		Joins("JOIN transaction_histories th ON th.transaction_id = td.transaction_id").
+		Where("tc.event_id = ? AND th.transaction_status IN ?", eventID, []string{"paid", "partially_refunded"}).
+		Where("NOT EXISTS (SELECT 1 FROM refunds r WHERE r.transaction_id = td.transaction_id AND r.event_id = ?)", eventID)
+	if ownerID != "" {
+		query = query.Where("td.owner_id = ?", ownerID)
+	}
+
+	var orders []paidEventOrder
+	if err := query.Scan(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	type refundKey struct{ transactionID, ownerID string }
+	grouped := make(map[refundKey]*models.Refund)
+	var keys []refundKey
+	soldByCategory := make(map[string]uint)
+	var totalQuantity uint
+	var totalAmount float64
+
+	for _, order := range orders {
+		key := refundKey{order.TransactionID, order.OwnerID}
+		refund, ok := grouped[key]
+		if !ok {
+			refund = &models.Refund{
+				RefundID:      utils.GenerateRefundID(),
+				TransactionID: order.TransactionID,
+				EventID:       eventID,
+				OwnerID:       order.OwnerID,
+				Reason:        reason,
+				Status:        "pending",
+				CreatedAt:     time.Now(),
+				UpdatedAt:     time.Now(),
+			}
+			grouped[key] = refund
+			keys = append(keys, key)
+		}
+		refund.Amount += order.Subtotal
+		refund.TicketCount += order.Quantity
+
+		soldByCategory[order.TicketCategoryID] += order.Quantity
+		totalQuantity += order.Quantity
+		totalAmount += order.Subtotal
+	}
+
+	refunds := make([]models.Refund, 0, len(keys))
+	for _, key := range keys {
+		refund := grouped[key]
+		if refund.Amount == 0 {
+			// Tiket gratis tidak perlu refund ke payment gateway
+			refund.Status = "not_required"
+		}
+		if err := tx.Create(refund).Error; err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, *refund)
+	}
+
+	for categoryID, quantity := range soldByCategory {
+		if err := tx.Model(&models.TicketCategory{}).
+			Where("ticket_category_id = ?", categoryID).
+			Update("sold", gorm.Expr("GREATEST(CAST(sold AS SIGNED) - ?, 0)", quantity)).Error; err != nil {
+			return nil, err
+		}
+	}
+	if totalQuantity > 0 {
+		if err := tx.Model(&models.Event{}).
+			Where("event_id = ?", eventID).
+			Updates(map[string]interface{}{
+				"total_tickets_sold": gorm.Expr("GREATEST(CAST(total_tickets_sold AS SIGNED) - ?, 0)", totalQuantity),
+				"total_sales":        gorm.Expr("GREATEST(total_sales - ?, 0)", totalAmount),
+			}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	ticketQuery := tx.Model(&models.Ticket{}).
+		Where("event_id = ? AND is_complimentary = ? AND status IN ?", eventID, false, []string{"active", "pending"})
+	if ownerID != "" {
+		ticketQuery = ticketQuery.Where("owner_id = ?", ownerID)
+	}
+	if err := ticketQuery.Updates(map[string]interface{}{
+		"status":     ticketStatus,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	for _, refund := range refunds {
+		if refund.Status != "pending" {
+			continue
+		}
+		if err := ScheduleJob(tx, jobTypeRefund, "refund:"+refund.RefundID, time.Now(), refundJobPayload{RefundID: refund.RefundID}); err != nil {
+			return nil, err
+		}
+	}
+
+	return refunds, nil
+}
+
+// handleRefundJob memanggil refund Midtrans. RefundKey = refund_id sehingga retry tidak menggandakan refund.
+func handleRefundJob(db *gorm.DB, job models.ScheduledJob) error {
+	var payload refundJobPayload
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return err
+	}
+
+	var refund models.Refund
+	if err := db.Preload("Event").Where("refund_id = ?", payload.RefundID).First(&refund).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+	if refund.Status != "pending" {
+		return nil
+	}
+
+	var client coreapi.Client
+	client.New(os.Getenv("MIDTRANS_SERVER_KEY"), midtrans.Sandbox)
+
+	resp, midErr := client.RefundTransaction(refund.TransactionID, &coreapi.RefundReq{
+		RefundKey: refund.RefundID,
+		Amount:    int64(refund.Amount),
+		Reason:    refund.Reason,
+	})
+	if midErr != nil {
+		status := "pending"
+		if job.Attempts >= job.MaxAttempts {
+			status = "failed"
+		}
+		db.Model(&models.Refund{}).
+			Where("refund_id = ?", refund.RefundID).
+			Updates(map[string]interface{}{
+				"status":     status,
+				"last_error": midErr.Error(),
+				"updated_at": time.Now(),
+			})
+		return fmt.Errorf("midtrans refund failed: %s", midErr.Error())
+	}
+
+	response, _ := json.Marshal(resp)
+	now := time.Now()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Refund{}).
+			Where("refund_id = ?", refund.RefundID).
+			Updates(map[string]interface{}{
+				"status":           "succeeded",
+				"gateway_response": string(response),
+				"last_error":       "",
+				"refunded_at":      &now,
+				"updated_at":       now,
+			}).Error; err != nil {
+			return err
+		}
+		return updateRefundedTransactionStatus(tx, refund.TransactionID)
+	})
+	if err != nil {
+		return err
+	}
+
+	notifyUsers(db, []string{refund.OwnerID}, "refund_succeeded", "Refund processed",
+		fmt.Sprintf("Your refund of Rp %.0f for %s has been processed.", refund.Amount, refund.Event.Name), refund.EventID)
+	return nil
+}
+
+// updateRefundedTransactionStatus menandai transaksi refunded / partially_refunded sesuai total refund
+func updateRefundedTransactionStatus(tx *gorm.DB, transactionID string) error {
+	var transaction models.TransactionHistory
+	if err := tx.Where("transaction_id = ?", transactionID).First(&transaction).Error; err != nil {
+		return err
+	}
+
+	var refunded float64
+	if err := tx.Model(&models.Refund{}).
+		Where("transaction_id = ? AND status = ?", transactionID, "succeeded").
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&refunded).Error; err != nil {
+		return err
+	}
+
+	status := "partially_refunded"
+	if refunded >= transaction.PriceTotal {
+		status = "refunded"
+	}
+	return tx.Model(&models.TransactionHistory{}).
+		Where("transaction_id = ?", transactionID).
+		Update("transaction_status", status).Error
+}
+
+// GetMyRefunds - Daftar refund milik user
+func GetMyRefunds(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var refunds []models.Refund
+	if err := config.DB.Preload("Event").
+		Where("owner_id = ?", user.UserID).
+		Order("created_at DESC").
+		Find(&refunds).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch refunds",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Refunds retrieved successfully",
+		"refunds": refunds,
+	})
+}
+
+// GetEventRefunds - Daftar refund sebuah event untuk organizer atau admin
+func GetEventRefunds(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", c.Params("id")).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if !canAccessEvent(config.DB, user, event, permRefundView) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to view refunds for this event",
+		})
+	}
+
+	var refunds []models.Refund
+	if err := config.DB.Where("event_id = ?", event.EventID).
+		Order("created_at DESC").
+		Find(&refunds).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch refunds",
+		})
+	}
+
+	summary := fiber.Map{}
+	for _, refund := range refunds {
+		entry, _ := summary[refund.Status].(fiber.Map)
+		if entry == nil {
+			entry = fiber.Map{"count": 0, "amount": 0.0}
+		}
+		entry["count"] = entry["count"].(int) + 1
+		entry["amount"] = entry["amount"].(float64) + refund.Amount
+		summary[refund.Status] = entry
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Refunds retrieved successfully",
+		"refunds": refunds,
+		"summary": summary,
+	})
+}
+
+// RetryRefund - Admin menjadwalkan ulang refund yang gagal
+func RetryRefund(c *fiber.Ctx) error {
+	admin := c.Locals("user").(models.User)
+
+	var refund models.Refund
+	if err := config.DB.Where("refund_id = ?", c.Params("id")).First(&refund).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Refund not found",
+		})
+	}
+
+	if refund.Status != "failed" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Only failed refunds can be retried",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	if err := tx.Model(&models.Refund{}).
+		Where("refund_id = ?", refund.RefundID).
+		Updates(map[string]interface{}{"status": "pending", "updated_at": time.Now()}).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update refund",
+		})
+	}
+
+	if err := ScheduleJob(tx, jobTypeRefund, "refund:"+refund.RefundID, time.Now(), refundJobPayload{RefundID: refund.RefundID}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to schedule refund",
+		})
+	}
+
+	if err := recordAudit(tx, c, admin.UserID, "refund.retry", "refund", refund.RefundID, fiber.Map{
+		"previous_error": refund.LastError,
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	log.Printf("Refund %s rescheduled by %s", refund.RefundID, admin.UserID)
+	return c.JSON(fiber.Map{
+		"message": "Refund rescheduled",
+	})
+}
@@ -0,0 +1,470 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	registrationTypeText   = "text"
+	registrationTypeChoice = "choice"
+
+	maxRegistrationAnswerLength = 1000
+)
+
+var errInvalidRegistration = errors.New("invalid registration")
+
+type RegistrationQuestionRequest struct {
+	Label             string   `json:"label"`
+	Type              string   `json:"type"` // text, choice
+	Options           []string `json:"options"`
+	Required          *bool    `json:"required"`
+	Position          *int     `json:"position"`
+	TicketCategoryIDs []string `json:"ticket_category_ids"` // kosong = semua kategori
+}
+
+type RegistrationAnswerRequest struct {
+	Answers []struct {
+		QuestionID string `json:"question_id"`
+		Value      string `json:"value"`
+	} `json:"answers"`
+}
+
+// registrationQuestionsFor memilih pertanyaan yang berlaku untuk ticket category tertentu
+func registrationQuestionsFor(questions []models.RegistrationQuestion, ticketCategoryID string) []models.RegistrationQuestion {
+	result := []models.RegistrationQuestion{}
+	for _, question := range questions {
+		if len(question.TicketCategoryIDs) == 0 || containsString(question.TicketCategoryIDs, ticketCategoryID) {
+			result = append(result, question)
+		}
+	}
+	return result
+}
+
+func loadRegistrationQuestions(db *gorm.DB, eventID string) ([]models.RegistrationQuestion, error) {
+	var questions []models.RegistrationQuestion
+	err := db.Where("event_id = ?", eventID).Order("position ASC, created_at ASC").Find(&questions).Error
+	return questions, err
+}
+
+// applyRegistrationQuestion memvalidasi request dan menyalinnya ke pertanyaan
+func applyRegistrationQuestion(db *gorm.DB, question *models.RegistrationQuestion, req RegistrationQuestionRequest) error {
+	if label := strings.TrimSpace(req.Label); label != "" {
+		question.Label = label
+	}
+	if question.Label == "" {
+		return fmt.Errorf("%w: label is required", errInvalidRegistration)
+	}
+	if len(question.Label) > 200 {
+		return fmt.Errorf("%w: label must be at most 200 characters", errInvalidRegistration)
+	}
+
+	if req.Type != "" {
+		question.Type = req.Type
+	}
+	if question.Type == "" {
+		question.Type = registrationTypeText
+	}
+	if req.Options != nil {
+		question.Options = nil
+		for _, option := range req.Options {
+			if option = strings.TrimSpace(option); option != "" && !containsString(question.Options, option) {
+				question.Options = append(question.Options, option)
+			}
+		}
+	}
+	switch question.Type {
+	case registrationTypeText:
+		question.Options = []string{}
+	case registrationTypeChoice:
+		if len(question.Options) < 2 {
+			return fmt.Errorf("%w: a choice question needs at least two options", errInvalidRegistration)
+		}
+	default:
+		return fmt.Errorf("%w: type must be either 'text' or 'choice'", errInvalidRegistration)
+	}
+
+	if req.Required != nil {
+		question.Required = *req.Required
+	}
+	if req.Position != nil {
+		question.Position = *req.Position
+	}
+
+	if req.TicketCategoryIDs != nil {
+		question.TicketCategoryIDs = []string{}
+		if len(req.TicketCategoryIDs) > 0 {
+			var count int64
+			if err := db.Model(&models.TicketCategory{}).
+				Where("event_id = ? AND ticket_category_id IN ?", question.EventID, req.TicketCategoryIDs).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if int(count) != len(req.TicketCategoryIDs) {
+				return fmt.Errorf("%w: one or more ticket categories not found in this event", errInvalidRegistration)
+			}
+			question.TicketCategoryIDs = req.TicketCategoryIDs
+		}
+	}
+	if question.TicketCategoryIDs == nil {
+		question.TicketCategoryIDs = []string{}
+	}
+	return nil
+}
+
+// loadRegistrationEvent memuat event dari parameter :id dan mengecek izin organizer
+func loadRegistrationEvent(c *fiber.Ctx, perm string) (models.Event, int, string) {
+	user := c.Locals("user").(models.User)
+
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", c.Params("id")).First(&event).Error; err != nil {
+		return event, fiber.StatusNotFound, "Event not found"
+	}
+	if !canAccessEvent(config.DB, user, event, perm) {
+		return event, fiber.StatusForbidden, "Not authorized to manage registration questions for this event"
+	}
+	return event, 0, ""
+}
+
+// CreateRegistrationQuestion - Organizer menambah pertanyaan pendaftaran untuk pemegang tiket
+func CreateRegistrationQuestion(c *fiber.Ctx) error {
+	event, status, message := loadRegistrationEvent(c, permEventManage)
+	if status != 0 {
+		return c.Status(status).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+
+	var req RegistrationQuestionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+
+	question := models.RegistrationQuestion{
+		QuestionID: utils.GenerateRegistrationQuestionID(),
+		EventID:    event.EventID,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	if req.Position == nil {
+		var count int64
+		config.DB.Model(&models.RegistrationQuestion{}).Where("event_id = ?", event.EventID).Count(&count)
+		question.Position = int(count)
+	}
+	if err := applyRegistrationQuestion(config.DB, &question, req); err != nil {
+		if errors.Is(err, errInvalidRegistration) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to validate registration question",
+		})
+	}
+
+	if err := config.DB.Create(&question).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create registration question",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":  "Registration question created successfully",
+		"question": question,
+	})
+}
+
+// GetRegistrationQuestions - Daftar pertanyaan pendaftaran event beserta jumlah jawabannya
+func GetRegistrationQuestions(c *fiber.Ctx) error {
+	event, status, message := loadRegistrationEvent(c, permEventView)
+	if status != 0 {
+		return c.Status(status).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+
+	questions, err := loadRegistrationQuestions(config.DB, event.EventID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch registration questions",
+		})
+	}
+
+	var counts []struct {
+		QuestionID string
+		Total      int64
+	}
+	config.DB.Model(&models.RegistrationAnswer{}).
+		Select("question_id, COUNT(*) AS total").
+		Where("event_id = ?", event.EventID).
+		Group("question_id").
+		Scan(&counts)
+	answered := make(map[string]int64, len(counts))
+	for _, row := range counts {
+		answered[row.QuestionID] = row.Total
+	}
+
+	result := make([]fiber.Map, 0, len(questions))
+	for _, question := range questions {
+		result = append(result, fiber.Map{
+			"question":     question,
+			"answer_count": answered[question.QuestionID],
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":   "Registration questions retrieved successfully",
+		"questions": result,
+	})
+}
+
+// UpdateRegistrationQuestion - Mengubah pertanyaan pendaftaran. Jawaban yang sudah ada tetap disimpan.
+func UpdateRegistrationQuestion(c *fiber.Ctx) error {
+	event, status, message := loadRegistrationEvent(c, permEventManage)
+	if status != 0 {
+		return c.Status(status).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+
+	var question models.RegistrationQuestion
+	if err := config.DB.Where("question_id = ? AND event_id = ?", c.Params("question_id"), event.EventID).
+		First(&question).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Registration question not found",
+		})
+	}
+
+	var req RegistrationQuestionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if err := applyRegistrationQuestion(config.DB, &question, req); err != nil {
+		if errors.Is(err, errInvalidRegistration) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to validate registration question",
+		})
+	}
+	question.UpdatedAt = time.Now()
+
+	if err := config.DB.Save(&question).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update registration question",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":  "Registration question updated successfully",
+		"question": question,
+	})
+}
+
+// DeleteRegistrationQuestion - Menghapus pertanyaan pendaftaran beserta jawabannya
+func DeleteRegistrationQuestion(c *fiber.Ctx) error {
+	event, status, message := loadRegistrationEvent(c, permEventManage)
+	if status != 0 {
+		return c.Status(status).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+
+	var question models.RegistrationQuestion
+	if err := config.DB.Where("question_id = ? AND event_id = ?", c.Params("question_id"), event.EventID).
+		First(&question).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Registration question not found",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	if err := tx.Where("question_id = ?", question.QuestionID).Delete(&models.RegistrationAnswer{}).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete registration answers",
+		})
+	}
+	if err := tx.Delete(&question).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete registration question",
+		})
+	}
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Registration question deleted successfully",
+	})
+}
+
+// loadOwnedTicket memuat tiket dari parameter :id milik user yang login
+func loadOwnedTicket(c *fiber.Ctx) (models.Ticket, bool) {
+	user := c.Locals("user").(models.User)
+
+	var ticket models.Ticket
+	err := config.DB.Where("ticket_id = ? AND owner_id = ?", c.Params("id"), user.UserID).First(&ticket).Error
+	return ticket, err == nil
+}
+
+// GetTicketRegistration - Pertanyaan pendaftaran untuk tiket milik user beserta jawabannya
+func GetTicketRegistration(c *fiber.Ctx) error {
+	ticket, ok := loadOwnedTicket(c)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Ticket not found",
+		})
+	}
+
+	questions, err := loadRegistrationQuestions(config.DB, ticket.EventID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch registration questions",
+		})
+	}
+	var answers []models.RegistrationAnswer
+	if err := config.DB.Where("ticket_id = ?", ticket.TicketID).Find(&answers).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch registration answers",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":   "Registration retrieved successfully",
+		"ticket_id": ticket.TicketID,
+		"questions": registrationQuestionsFor(questions, ticket.TicketCategoryID),
+		"answers":   answers,
+	})
+}
+
+// SubmitTicketRegistration - Pemegang tiket mengisi atau mengubah jawaban pendaftaran.
+// Jawaban yang tidak dikirim tetap seperti sebelumnya; pertanyaan wajib harus sudah terjawab.
+func SubmitTicketRegistration(c *fiber.Ctx) error {
+	ticket, ok := loadOwnedTicket(c)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Ticket not found",
+		})
+	}
+	if ticket.Status != "active" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Registration can only be changed for active tickets",
+		})
+	}
+
+	var req RegistrationAnswerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+
+	questions, err := loadRegistrationQuestions(config.DB, ticket.EventID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch registration questions",
+		})
+	}
+	questions = registrationQuestionsFor(questions, ticket.TicketCategoryID)
+	byID := make(map[string]models.RegistrationQuestion, len(questions))
+	for _, question := range questions {
+		byID[question.QuestionID] = question
+	}
+
+	var existing []models.RegistrationAnswer
+	if err := config.DB.Where("ticket_id = ?", ticket.TicketID).Find(&existing).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch registration answers",
+		})
+	}
+	values := make(map[string]string, len(existing))
+	for _, answer := range existing {
+		values[answer.QuestionID] = answer.Value
+	}
+
+	for _, item := range req.Answers {
+		question, ok := byID[item.QuestionID]
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Registration question not found for this ticket: " + item.QuestionID,
+			})
+		}
+		value := strings.TrimSpace(item.Value)
+		if len(value) > maxRegistrationAnswerLength {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Answer to %q must be at most %d characters", question.Label, maxRegistrationAnswerLength),
+			})
+		}
+		if value != "" && question.Type == registrationTypeChoice && !containsString(question.Options, value) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Answer to %q must be one of the options", question.Label),
+			})
+		}
+		values[question.QuestionID] = value
+	}
+
+	var missing []string
+	for _, question := range questions {
+		if question.Required && values[question.QuestionID] == "" {
+			missing = append(missing, question.Label)
+		}
+	}
+	if len(missing) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Required registration questions are not answered",
+			"missing": missing,
+		})
+	}
+
+	answers := make([]models.RegistrationAnswer, 0, len(req.Answers))
+	for _, item := range req.Answers {
+		answers = append(answers, models.RegistrationAnswer{
+			TicketID:   ticket.TicketID,
+			QuestionID: item.QuestionID,
+			EventID:    ticket.EventID,
+			Value:      values[item.QuestionID],
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		})
+	}
+	if len(answers) > 0 {
+		if err := config.DB.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "ticket_id"}, {Name: "question_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+		}).Create(&answers).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to save registration answers",
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Registration saved successfully",
+		"answers": answers,
+	})
+}
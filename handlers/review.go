@@ -0,0 +1,706 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxReviewCommentLength = 2000
+
+var reviewReportReasons = map[string]bool{
+	"spam":      true,
+	"offensive": true,
+	"off_topic": true,
+	"fake":      true,
+	"personal":  true, // berisi data pribadi
+	"other":     true,
+}
+
+type ReviewRequest struct {
+	Rating  uint   `json:"rating"`
+	Comment string `json:"comment"`
+}
+
+type ReviewReplyRequest struct {
+	Reply string `json:"reply"` // kosong untuk menghapus balasan
+}
+
+type ReviewModerationRequest struct {
+	Action string `json:"action"` // hide, unhide
+	Reason string `json:"reason"`
+}
+
+type ReviewReportRequest struct {
+	Reason  string `json:"reason"`
+	Comment string `json:"comment"`
+}
+
+type ratingSummary struct {
+	Average float64 `json:"average"`
+	Count   int64   `json:"count"`
+}
+
+func validateReviewRequest(req ReviewRequest) error {
+	if req.Rating < 1 || req.Rating > 5 {
+		return errors.New("rating must be between 1 and 5")
+	}
+	if len(req.Comment) > maxReviewCommentLength {
+		return errors.New("comment must be at most 2000 characters")
+	}
+	return nil
+}
+
+// visibleRating menghitung rata-rata rating review yang tidak disembunyikan
+func visibleRating(db *gorm.DB, column, value string) (ratingSummary, error) {
+	var summary ratingSummary
+	err := db.Model(&models.EventReview{}).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Where(column+" = ? AND status = ?", value, "visible").
+		Scan(&summary).Error
+	summary.Average = math.Round(summary.Average*100) / 100
+	return summary, err
+}
+
+// refreshReviewAggregates memperbarui rating_average/rating_count event dan organisasinya.
+// Dipanggil di transaksi yang sama setiap kali review dibuat, diubah, dihapus, atau disembunyikan.
+func refreshReviewAggregates(tx *gorm.DB, event models.Event) error {
+	eventRating, err := visibleRating(tx, "event_id", event.EventID)
+	if err != nil {
+		return err
+	}
+	if err := tx.Model(&models.Event{}).Where("event_id = ?", event.EventID).UpdateColumns(map[string]interface{}{
+		"rating_average": eventRating.Average,
+		"rating_count":   eventRating.Count,
+	}).Error; err != nil {
+		return err
+	}
+
+	if event.OrganizationID == nil {
+		return nil
+	}
+	organizationRating, err := visibleRating(tx, "organization_id", *event.OrganizationID)
+	if err != nil {
+		return err
+	}
+	return tx.Model(&models.Organization{}).Where("organization_id = ?", *event.OrganizationID).UpdateColumns(map[string]interface{}{
+		"rating_average": organizationRating.Average,
+		"rating_count":   organizationRating.Count,
+	}).Error
+}
+
+// ratingDistribution menghitung jumlah review visible per bintang
+func ratingDistribution(db *gorm.DB, column, value string) (map[string]int64, error) {
+	type row struct {
+		Rating uint
+		Total  int64
+	}
+	var rows []row
+	if err := db.Model(&models.EventReview{}).
+		Select("rating, COUNT(*) AS total").
+		Where(column+" = ? AND status = ?", value, "visible").
+		Group("rating").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	distribution := map[string]int64{"1": 0, "2": 0, "3": 0, "4": 0, "5": 0}
+	for _, r := range rows {
+		distribution[strconv.Itoa(int(r.Rating))] = r.Total
+	}
+	return distribution, nil
+}
+
+// loadEventReview mengambil review milik event tertentu
+func loadEventReview(db *gorm.DB, eventID, reviewID string) (models.EventReview, models.Event, error) {
+	var event models.Event
+	if err := db.Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		return models.EventReview{}, event, err
+	}
+	var review models.EventReview
+	err := db.Where("review_id = ? AND event_id = ?", reviewID, eventID).First(&review).Error
+	return review, event, err
+}
+
+// respondReviewList mengembalikan review visible beserta ringkasan rating
+func respondReviewList(c *fiber.Ctx, column, value string) error {
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	order := "created_at DESC"
+	switch c.Query("sort") {
+	case "", "newest":
+	case "highest":
+		order = "rating DESC, created_at DESC"
+	case "lowest":
+		order = "rating ASC, created_at DESC"
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "sort must be one of: newest, highest, lowest",
+		})
+	}
+
+	query := config.DB.Where(column+" = ? AND status = ?", value, "visible")
+	if rating, err := strconv.Atoi(c.Query("rating")); err == nil && rating >= 1 && rating <= 5 {
+		query = query.Where("rating = ?", rating)
+	}
+
+	var reviews []models.EventReview
+	if err := query.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("user_id, username, name, profile_pict")
+	}).Order(order).Limit(limit).Offset(offset).Find(&reviews).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch reviews",
+		})
+	}
+
+	summary, err := visibleRating(config.DB, column, value)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to compute rating",
+		})
+	}
+	distribution, err := ratingDistribution(config.DB, column, value)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to compute rating distribution",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":      "Reviews retrieved successfully",
+		"rating":       summary,
+		"distribution": distribution,
+		"reviews":      reviews,
+		"limit":        limit,
+		"offset":       offset,
+	})
+}
+
+// GetEventReviews - Review publik sebuah event. ?sort=newest|highest|lowest, ?rating=, ?limit=, ?offset=
+func GetEventReviews(c *fiber.Ctx) error {
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", c.Params("id")).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+	return respondReviewList(c, "event_id", event.EventID)
+}
+
+// GetOrganizationReviews - Rating agregat dan review publik seluruh event organisasi
+func GetOrganizationReviews(c *fiber.Ctx) error {
+	var organization models.Organization
+	if err := config.DB.Where("organization_id = ?", c.Params("id")).First(&organization).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Organization not found",
+		})
+	}
+	return respondReviewList(c, "organization_id", organization.OrganizationID)
+}
+
+// CreateEventReview - Rating dan ulasan, hanya untuk pemegang tiket berstatus used setelah event selesai
+func CreateEventReview(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req ReviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	req.Comment = strings.TrimSpace(req.Comment)
+	if err := validateReviewRequest(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var event models.Event
+	if err := config.DB.Where("event_id = ?", c.Params("id")).First(&event).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if event.Status == "cancelled" || time.Now().Before(event.DateEnd) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Reviews open after the event has ended",
+		})
+	}
+
+	var usedTickets int64
+	config.DB.Model(&models.Ticket{}).
+		Where("event_id = ? AND owner_id = ? AND status = ?", event.EventID, user.UserID, "used").
+		Count(&usedTickets)
+	if usedTickets == 0 {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Only attendees who checked in can review this event",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	review := models.EventReview{
+		ReviewID:       utils.GenerateReviewID(),
+		EventID:        event.EventID,
+		UserID:         user.UserID,
+		OrganizationID: event.OrganizationID,
+		Rating:         req.Rating,
+		Comment:        req.Comment,
+		Status:         "visible",
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&review)
+	if result.Error != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create review",
+		})
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "You have already reviewed this event",
+		})
+	}
+
+	if err := refreshReviewAggregates(tx, event); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update event rating",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Review created successfully",
+		"review":  review,
+	})
+}
+
+// UpdateEventReview - Pemilik review mengubah rating/ulasannya. Review yang disembunyikan admin tidak bisa diubah.
+func UpdateEventReview(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req ReviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	req.Comment = strings.TrimSpace(req.Comment)
+	if err := validateReviewRequest(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	review, event, err := loadEventReview(config.DB, c.Params("id"), c.Params("review_id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Review not found",
+		})
+	}
+	if review.UserID != user.UserID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to update this review",
+		})
+	}
+	if review.Status == "hidden" {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Review has been hidden by a moderator and can no longer be edited",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	if err := tx.Model(&review).Updates(map[string]interface{}{
+		"rating":  req.Rating,
+		"comment": req.Comment,
+	}).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update review",
+		})
+	}
+
+	if err := refreshReviewAggregates(tx, event); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update event rating",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Review updated successfully",
+		"review":  review,
+	})
+}
+
+// DeleteEventReview - Pemilik review atau admin menghapus review
+func DeleteEventReview(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	review, event, err := loadEventReview(config.DB, c.Params("id"), c.Params("review_id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Review not found",
+		})
+	}
+	if review.UserID != user.UserID && user.Role != "admin" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to delete this review",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	if err := tx.Where("review_id = ?", review.ReviewID).Delete(&models.ReviewReport{}).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete review reports",
+		})
+	}
+	if err := tx.Delete(&review).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete review",
+		})
+	}
+
+	if err := refreshReviewAggregates(tx, event); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update event rating",
+		})
+	}
+
+	if user.Role == "admin" && review.UserID != user.UserID {
+		if err := recordAudit(tx, c, user.UserID, "review.delete", "review", review.ReviewID, fiber.Map{
+			"event_id": event.EventID,
+			"user_id":  review.UserID,
+		}); err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to record audit log",
+			})
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Review deleted successfully",
+	})
+}
+
+// ReplyToEventReview - Organizer membalas review secara publik
+func ReplyToEventReview(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req ReviewReplyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	req.Reply = strings.TrimSpace(req.Reply)
+	if len(req.Reply) > maxReviewCommentLength {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "reply must be at most 2000 characters",
+		})
+	}
+
+	review, event, err := loadEventReview(config.DB, c.Params("id"), c.Params("review_id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Review not found",
+		})
+	}
+	if !canAccessEvent(config.DB, user, event, permEventManage) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to reply to reviews of this event",
+		})
+	}
+
+	updateData := map[string]interface{}{
+		"reply":      req.Reply,
+		"reply_by":   nil,
+		"replied_at": nil,
+	}
+	if req.Reply != "" {
+		updateData["reply_by"] = user.UserID
+		updateData["replied_at"] = time.Now()
+	}
+	if err := config.DB.Model(&review).Updates(updateData).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save reply",
+		})
+	}
+
+	if req.Reply != "" {
+		notifyUsers(config.DB, []string{review.UserID}, "review_reply", "The organizer replied to your review",
+			"The organizer of "+event.Name+" replied to your review.", event.EventID)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Reply saved successfully",
+		"review":  review,
+	})
+}
+
+// ReportEventReview - User melaporkan review yang melanggar (spam, offensive, dll)
+func ReportEventReview(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req ReviewReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if !reviewReportReasons[req.Reason] {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "reason must be one of: spam, offensive, off_topic, fake, personal, other",
+		})
+	}
+
+	review, _, err := loadEventReview(config.DB, c.Params("id"), c.Params("review_id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Review not found",
+		})
+	}
+	if review.UserID == user.UserID {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "You cannot report your own review",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	report := models.ReviewReport{
+		ReportID:   utils.GenerateReviewReportID(),
+		ReviewID:   review.ReviewID,
+		ReporterID: user.UserID,
+		Reason:     req.Reason,
+		Comment:    strings.TrimSpace(req.Comment),
+		Status:     "open",
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&report)
+	if result.Error != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to report review",
+		})
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "You have already reported this review",
+		})
+	}
+
+	if err := tx.Model(&models.EventReview{}).
+		Where("review_id = ?", review.ReviewID).
+		UpdateColumn("report_count", gorm.Expr("report_count + 1")).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to report review",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Review reported successfully",
+		"report":  report,
+	})
+}
+
+// GetReviewReports - Admin melihat laporan review. ?status=open (default), resolved, dismissed, all
+func GetReviewReports(c *fiber.Ctx) error {
+	query := config.DB.Preload("Review").Preload("Review.User")
+	if status := c.Query("status", "open"); status != "all" {
+		query = query.Where("status = ?", status)
+	}
+
+	var reports []models.ReviewReport
+	if err := query.Order("created_at ASC").Limit(200).Find(&reports).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch review reports",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Review reports retrieved successfully",
+		"reports": reports,
+	})
+}
+
+// ModerateEventReview - Admin menyembunyikan atau menampilkan kembali review.
+// Laporan terbuka ditandai resolved (hide) atau dismissed (unhide).
+func ModerateEventReview(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req ReviewModerationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if req.Action != "hide" && req.Action != "unhide" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "action must be either 'hide' or 'unhide'",
+		})
+	}
+	if req.Action == "hide" && strings.TrimSpace(req.Reason) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "reason is required when hiding a review",
+		})
+	}
+
+	review, event, err := loadEventReview(config.DB, c.Params("id"), c.Params("review_id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Review not found",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	now := time.Now()
+	updateData := map[string]interface{}{
+		"status":        "visible",
+		"hidden_reason": "",
+		"hidden_by":     nil,
+		"hidden_at":     nil,
+	}
+	reportStatus := "dismissed"
+	if req.Action == "hide" {
+		updateData["status"] = "hidden"
+		updateData["hidden_reason"] = strings.TrimSpace(req.Reason)
+		updateData["hidden_by"] = user.UserID
+		updateData["hidden_at"] = now
+		reportStatus = "resolved"
+	}
+
+	if err := tx.Model(&review).Updates(updateData).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to moderate review",
+		})
+	}
+
+	if err := tx.Model(&models.ReviewReport{}).
+		Where("review_id = ? AND status = ?", review.ReviewID, "open").
+		Updates(map[string]interface{}{
+			"status":      reportStatus,
+			"resolved_by": user.UserID,
+			"resolved_at": now,
+		}).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to resolve review reports",
+		})
+	}
+
+	if err := refreshReviewAggregates(tx, event); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update event rating",
+		})
+	}
+
+	if err := recordAudit(tx, c, user.UserID, "review."+req.Action, "review", review.ReviewID, fiber.Map{
+		"event_id": event.EventID,
+		"user_id":  review.UserID,
+		"reason":   req.Reason,
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	if req.Action == "hide" {
+		notifyUsers(config.DB, []string{review.UserID}, "review_hidden", "Your review was hidden",
+			"Your review of "+event.Name+" was hidden by a moderator. Reason: "+strings.TrimSpace(req.Reason), event.EventID)
+	}
+
+	message := "Review restored"
+	if req.Action == "hide" {
+		message = "Review hidden"
+	}
+
+	return c.JSON(fiber.Map{
+		"message": message,
+		"review":  review,
+	})
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+)
+
+// JobHandler mengeksekusi satu job. Handler harus idempotent karena job bisa
+// dijalankan ulang jika lease habis sebelum selesai (misal instance mati).
+type JobHandler func(db *gorm.DB, job models.ScheduledJob) error
+
+const (
+	defaultSchedulerInterval = 15 * time.Second
+	jobLeaseDuration         = 2 * time.Minute
+	jobBatchSize             = 20
+)
+
+var (
+	jobHandlersMu sync.RWMutex
+	jobHandlers   = map[string]JobHandler{}
+)
+
+// schedulerInstanceID membedakan instance yang memegang lease sebuah job
+var schedulerInstanceID = func() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}()
+
+// RegisterJobHandler mendaftarkan handler untuk satu tipe job
+func RegisterJobHandler(jobType string, handler JobHandler) {
+	jobHandlersMu.Lock()
+	defer jobHandlersMu.Unlock()
+	jobHandlers[jobType] = handler
+}
+
+// ScheduleJob membuat atau menjadwalkan ulang job berdasarkan key.
+// Job dengan key yang sama (termasuk yang sudah done) di-reset ke pending dengan run_at baru.
+func ScheduleJob(db *gorm.DB, jobType, key string, runAt time.Time, payload interface{}) error {
+	job, err := newScheduledJob(jobType, key, runAt, payload)
+	if err != nil {
+		return err
+	}
+
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "job_key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"type":         job.Type,
+			"payload":      job.Payload,
+			"run_at":       job.RunAt,
+			"status":       "pending",
+			"attempts":     0,
+			"locked_by":    "",
+			"locked_until": nil,
+			"last_error":   "",
+			"updated_at":   time.Now(),
+		}),
+	}).Create(&job).Error
+}
+
+// EnsureJob membuat job hanya jika key belum ada, dipakai untuk backfill saat boot
+// supaya instance yang baru start tidak me-reset job yang sedang dijalankan instance lain.
+func EnsureJob(db *gorm.DB, jobType, key string, runAt time.Time, payload interface{}) error {
+	job, err := newScheduledJob(jobType, key, runAt, payload)
+	if err != nil {
+		return err
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&job).Error
+}
+
+// CancelJob membatalkan job yang belum dieksekusi
+func CancelJob(db *gorm.DB, key string) error {
+	return db.Model(&models.ScheduledJob{}).
+		Where("job_key = ? AND status = ?", key, "pending").
+		Updates(map[string]interface{}{"status": "cancelled", "updated_at": time.Now()}).Error
+}
+
+func newScheduledJob(jobType, key string, runAt time.Time, payload interface{}) (models.ScheduledJob, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return models.ScheduledJob{}, err
+	}
+
+	return models.ScheduledJob{
+		JobID:       utils.GenerateJobID(),
+		Type:        jobType,
+		JobKey:      key,
+		Payload:     string(data),
+		RunAt:       runAt.UTC(),
+		Status:      "pending",
+		MaxAttempts: 5,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}, nil
+}
+
+// StartScheduler menjalankan polling job yang sudah jatuh tempo.
+// Interval bisa diatur lewat SCHEDULER_INTERVAL_SECONDS.
+func StartScheduler(db *gorm.DB) {
+	interval := defaultSchedulerInterval
+	if v, err := strconv.Atoi(os.Getenv("SCHEDULER_INTERVAL_SECONDS")); err == nil && v > 0 {
+		interval = time.Duration(v) * time.Second
+	}
+
+	log.Printf(" --  Scheduler %s started (interval %s)", schedulerInstanceID, interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runDueJobs(db)
+			<-ticker.C
+		}
+	}()
+}
+
+func runDueJobs(db *gorm.DB) {
+	for {
+		var jobs []models.ScheduledJob
+		now := time.Now().UTC()
+		if err := db.Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?)", "pending", now, "running", now).
+			Order("run_at ASC").
+			Limit(jobBatchSize).
+			Find(&jobs).Error; err != nil {
+			log.Println("Scheduler: failed to fetch due jobs:", err)
+			return
+		}
+
+		claimed := 0
+		for _, job := range jobs {
+			if !claimJob(db, &job) {
+				continue
+			}
+			claimed++
+			executeJob(db, job)
+		}
+
+		// Berhenti jika batch tidak penuh atau semua job sudah diambil instance lain
+		if len(jobs) < jobBatchSize || claimed == 0 {
+			return
+		}
+	}
+}
+
+// claimJob mengambil lease job secara atomik. Hanya satu instance yang berhasil
+// karena UPDATE bersyarat pada status/locked_until.
+func claimJob(db *gorm.DB, job *models.ScheduledJob) bool {
+	now := time.Now().UTC()
+	lockedUntil := now.Add(jobLeaseDuration)
+
+	result := db.Model(&models.ScheduledJob{}).
+		Where("job_id = ?", job.JobID).
+		Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?)", "pending", now, "running", now).
+		Updates(map[string]interface{}{
+			"status":       "running",
+			"locked_by":    schedulerInstanceID,
+			"locked_until": lockedUntil,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"updated_at":   now,
+		})
+	if result.Error != nil {
+		log.Println("Scheduler: failed to claim job", job.JobID, result.Error)
+		return false
+	}
+	if result.RowsAffected == 0 {
+		return false
+	}
+
+	job.Status = "running"
+	job.LockedBy = schedulerInstanceID
+	job.LockedUntil = &lockedUntil
+	job.Attempts++
+	return true
+}
+
+func executeJob(db *gorm.DB, job models.ScheduledJob) {
+	jobHandlersMu.RLock()
+	handler, ok := jobHandlers[job.Type]
+	jobHandlersMu.RUnlock()
+
+	var err error
+	if !ok {
+		err = fmt.Errorf("no handler registered for job type %s", job.Type)
+	} else {
+		err = runJobHandler(handler, db, job)
+	}
+
+	// Update hanya jika lease masih milik instance ini; job yang dijadwalkan ulang
+	// selama eksekusi sudah di-reset ke pending dan tidak boleh ditimpa
+	finish := db.Model(&models.ScheduledJob{}).
+		Where("job_id = ? AND status = ? AND locked_by = ?", job.JobID, "running", schedulerInstanceID)
+
+	if err == nil {
+		finish.Updates(map[string]interface{}{
+			"status":       "done",
+			"locked_until": nil,
+			"last_error":   "",
+			"updated_at":   time.Now(),
+		})
+		return
+	}
+
+	log.Printf("Scheduler: job %s (%s) failed on attempt %d: %v", job.JobID, job.Type, job.Attempts, err)
+
+	if job.Attempts >= job.MaxAttempts {
+		finish.Updates(map[string]interface{}{
+			"status":       "failed",
+			"locked_until": nil,
+			"last_error":   err.Error(),
+			"updated_at":   time.Now(),
+		})
+		return
+	}
+
+	// Backoff eksponensial: 30s, 60s, 120s, ...
+	backoff := time.Duration(30*(1<<(job.Attempts-1))) * time.Second
+	finish.Updates(map[string]interface{}{
+		"status":       "pending",
+		"run_at":       time.Now().UTC().Add(backoff),
+		"locked_by":    "",
+		"locked_until": nil,
+		"last_error":   err.Error(),
+		"updated_at":   time.Now(),
+	})
+}
+
+func runJobHandler(handler JobHandler, db *gorm.DB, job models.ScheduledJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(db, job)
+}
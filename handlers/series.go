@@ -0,0 +1,865 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SeriesTicketTemplate adalah template ticket category yang dibuat ulang untuk setiap occurrence.
+// Offset dihitung dalam menit relatif terhadap waktu mulai occurrence.
+type SeriesTicketTemplate struct {
+	Name               string  `json:"name"`
+	Price              float64 `json:"price"`
+	Quota              uint    `json:"quota"`
+	CompQuota          uint    `json:"comp_quota"`
+	IsHidden           bool    `json:"is_hidden"`
+	Description        string  `json:"description"`
+	StartOffsetMinutes int     `json:"start_offset_minutes"`
+	EndOffsetMinutes   *int    `json:"end_offset_minutes"` // kosong = sampai occurrence selesai
+}
+
+// SeriesOccurrenceRequest dipakai untuk daftar occurrence eksplisit (misal tur multi kota)
+type SeriesOccurrenceRequest struct {
+	DateStart string `json:"date_start"`
+	DateEnd   string `json:"date_end"`
+	Location  string `json:"location"`
+	Venue     string `json:"venue"`
+	District  string `json:"district"`
+}
+
+type seriesOccurrence struct {
+	start     time.Time
+	end       time.Time
+	location  string
+	venue     string
+	district  string
+	latitude  *float64
+	longitude *float64
+}
+
+type SeriesUpdateRequest struct {
+	Scope            string                 `json:"scope"` // one, future
+	EventID          string                 `json:"event_id"`
+	Name             string                 `json:"name"`
+	Description      string                 `json:"description"`
+	Rules            string                 `json:"rules"`
+	Location         string                 `json:"location"`
+	Venue            string                 `json:"venue"`
+	District         string                 `json:"district"`
+	Category         string                 `json:"category"`       // ID atau nama kategori
+	ChildCategory    string                 `json:"child_category"` // ID atau nama sub kategori
+	CategoryID       string                 `json:"category_id"`
+	ChildCategoryID  string                 `json:"child_category_id"`
+	DateStart        string                 `json:"date_start"` // hanya untuk scope one
+	DateEnd          string                 `json:"date_end"`   // hanya untuk scope one
+	TicketCategories []SeriesTicketTemplate `json:"ticket_categories"`
+}
+
+// CreateEventSeries - Membuat event series dari recurrence rule (RRULE subset) atau daftar occurrence.
+// Setiap occurrence menjadi event tersendiri berstatus pending dengan ticket category dari template.
+func CreateEventSeries(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	name := c.FormValue("name")
+	location := c.FormValue("location")
+	venue := c.FormValue("venue")
+	district := c.FormValue("district")
+	description := c.FormValue("description")
+	rules := c.FormValue("rules")
+	category := firstNonEmpty(c.FormValue("category_id"), c.FormValue("category"))
+	childCategory := firstNonEmpty(c.FormValue("child_category_id"), c.FormValue("child_category"))
+	recurrenceRule := c.FormValue("recurrence_rule")
+	firstStartStr := c.FormValue("first_start")
+	durationStr := c.FormValue("duration_minutes")
+	occurrencesJSON := c.FormValue("occurrences")
+	ticketCategoriesJSON := c.FormValue("ticket_categories")
+
+	if name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Missing required field: name",
+		})
+	}
+
+	parentCategory, childEventCategory, err := resolveEventCategory(config.DB, category, childCategory, nil)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// Venue direktori menjadi alamat default semua occurrence
+	var directoryVenue *models.Venue
+	if id := c.FormValue("venue_id"); id != "" {
+		resolved, status, err := resolveEventVenue(config.DB, user, id)
+		if err != nil {
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		directoryVenue = &resolved
+		venue, location, district = resolved.Name, resolved.Address, resolved.District
+	}
+
+	var templates []SeriesTicketTemplate
+	if ticketCategoriesJSON != "" {
+		if err := json.Unmarshal([]byte(ticketCategoriesJSON), &templates); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid ticket categories JSON format: " + err.Error(),
+			})
+		}
+	}
+	if err := validateSeriesTemplates(templates); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// Recurrence dihitung pada jam lokal zona series, lalu disimpan dalam UTC
+	loc, err := utils.LoadTimeZone(c.FormValue("time_zone"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var occurrences []seriesOccurrence
+	var durationMinutes uint
+
+	switch {
+	case occurrencesJSON != "":
+		var reqs []SeriesOccurrenceRequest
+		if err := json.Unmarshal([]byte(occurrencesJSON), &reqs); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid occurrences JSON format: " + err.Error(),
+			})
+		}
+		for i, req := range reqs {
+			start, err := utils.ParseEventTime(req.DateStart, loc)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": fmt.Sprintf("Invalid date_start in occurrence %d: %v", i+1, err),
+				})
+			}
+			end, err := utils.ParseEventTime(req.DateEnd, loc)
+			if err != nil || !end.After(start) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": fmt.Sprintf("Invalid date_end in occurrence %d. Must be after date_start", i+1),
+				})
+			}
+			occurrences = append(occurrences, seriesOccurrence{
+				start:    start,
+				end:      end,
+				location: firstNonEmpty(req.Location, location),
+				venue:    firstNonEmpty(req.Venue, venue),
+				district: firstNonEmpty(req.District, district),
+			})
+		}
+	case recurrenceRule != "":
+		rule, err := utils.ParseRecurrenceRule(recurrenceRule, loc)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid recurrence_rule: " + err.Error(),
+			})
+		}
+		firstStart, err := utils.ParseEventTime(firstStartStr, loc)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid first_start format: " + err.Error(),
+			})
+		}
+		duration, err := strconv.Atoi(durationStr)
+		if err != nil || duration < 1 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "duration_minutes must be a positive number",
+			})
+		}
+		durationMinutes = uint(duration)
+
+		// BYDAY dan pergantian hari mengikuti kalender lokal, bukan UTC
+		for _, start := range rule.Occurrences(firstStart.In(loc)) {
+			occurrences = append(occurrences, seriesOccurrence{
+				start:    start.UTC(),
+				end:      start.Add(time.Duration(duration) * time.Minute).UTC(),
+				location: location,
+				venue:    venue,
+				district: district,
+			})
+		}
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Either recurrence_rule (with first_start and duration_minutes) or occurrences is required",
+		})
+	}
+
+	if len(occurrences) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Recurrence produced no occurrences",
+		})
+	}
+	if len(occurrences) > utils.MaxRecurrenceOccurrences {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("A series can have at most %d occurrences", utils.MaxRecurrenceOccurrences),
+		})
+	}
+	for i, occ := range occurrences {
+		if occ.location == "" || occ.venue == "" || occ.district == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Occurrence %d is missing location, venue or district", i+1),
+			})
+		}
+	}
+
+	organizationID, status, err := resolveEventOrganization(config.DB, user, c.FormValue("organization_id"))
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// Upload gambar sekali untuk seluruh series
+	var imageURL, flyerURL string
+	if imageFile, err := c.FormFile("image"); err == nil {
+		if file, err := imageFile.Open(); err == nil {
+			defer file.Close()
+			folder := fmt.Sprintf("ticketing-app/events/%s/images", user.UserID)
+			imageURL, err = config.UploadImage(context.Background(), file, folder)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "Failed to upload event image",
+				})
+			}
+		}
+	}
+	if flyerFile, err := c.FormFile("flyer"); err == nil {
+		if file, err := flyerFile.Open(); err == nil {
+			defer file.Close()
+			folder := fmt.Sprintf("ticketing-app/events/%s/flyers", user.UserID)
+			flyerURL, err = config.UploadImage(context.Background(), file, folder)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "Failed to upload event flyer",
+				})
+			}
+		}
+	}
+
+	latitude, longitude, err := parseCoordinates(c.FormValue("latitude"), c.FormValue("longitude"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if directoryVenue != nil && latitude == nil {
+		latitude, longitude = directoryVenue.Latitude, directoryVenue.Longitude
+	}
+
+	templateJSON, _ := json.Marshal(templates)
+
+	series := models.EventSeries{
+		SeriesID:        utils.GenerateSeriesID(),
+		OwnerID:         user.UserID,
+		OrganizationID:  &organizationID,
+		Name:            name,
+		Description:     description,
+		Rules:           rules,
+		Location:        location,
+		Venue:           venue,
+		District:        district,
+		Image:           imageURL,
+		Flyer:           flyerURL,
+		RecurrenceRule:  recurrenceRule,
+		TimeZone:        loc.String(),
+		DurationMinutes: durationMinutes,
+		TicketTemplate:  string(templateJSON),
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	if parentCategory != nil {
+		series.CategoryID, series.Category = &parentCategory.EventCategoryID, parentCategory.EventCategoryName
+	}
+	if childEventCategory != nil {
+		series.ChildCategoryID, series.ChildCategory = &childEventCategory.ChildEventCategoryID, childEventCategory.ChildEventCategoryName
+	}
+
+	// Geocode per alamat unik supaya tur multi kota mendapat koordinat masing-masing.
+	// Dilakukan sebelum transaksi dibuka karena memanggil layanan eksternal.
+	geocoded := map[string]geoPoint{}
+	for i, occ := range occurrences {
+		occurrences[i].latitude, occurrences[i].longitude = latitude, longitude
+		sameAddress := occ.venue == venue && occ.location == location && occ.district == district
+		if latitude == nil || !sameAddress {
+			point := geocodeCached(geocoded, occ.venue, occ.location, occ.district)
+			occurrences[i].latitude, occurrences[i].longitude = point.lat, point.lng
+		}
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	if err := tx.Create(&series).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create event series: " + err.Error(),
+		})
+	}
+
+	for _, occ := range occurrences {
+		sameAddress := occ.venue == venue && occ.location == location && occ.district == district
+		var venueID *string
+		if directoryVenue != nil && sameAddress {
+			venueID = &directoryVenue.VenueID
+		}
+
+		event := models.Event{
+			EventID:        utils.GenerateEventID(),
+			Name:           name,
+			OwnerID:        user.UserID,
+			OrganizationID: &organizationID,
+			Status:         "pending",
+			DateStart:      occ.start,
+			DateEnd:        occ.end,
+			TimeZone:       loc.String(),
+			Location:       occ.location,
+			Venue:          occ.venue,
+			VenueID:        venueID,
+			District:       occ.district,
+			Latitude:       occ.latitude,
+			Longitude:      occ.longitude,
+			Description:    description,
+			Rules:          rules,
+			Image:          imageURL,
+			Flyer:          flyerURL,
+			SeriesID:       &series.SeriesID,
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
+		}
+		applyEventCategory(&event, parentCategory, childEventCategory)
+
+		if err := tx.Create(&event).Error; err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to create occurrence: " + err.Error(),
+			})
+		}
+
+		for _, tmpl := range templates {
+			ticketCategory := ticketCategoryFromTemplate(tmpl, event)
+			if err := tx.Create(&ticketCategory).Error; err != nil {
+				tx.Rollback()
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "Failed to create ticket category: " + err.Error(),
+				})
+			}
+		}
+
+		if err := assignEventSlug(tx, event.EventID); err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to generate occurrence slug",
+			})
+		}
+
+		if err := enqueueModeration(tx, moderationEntityEvent, event.EventID, user.UserID); err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to queue occurrence for review",
+			})
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction: " + err.Error(),
+		})
+	}
+
+	var created models.EventSeries
+	if err := config.DB.Preload("Events", func(db *gorm.DB) *gorm.DB {
+		return db.Order("date_start ASC")
+	}).Preload("Events.TicketCategories").
+		Where("series_id = ?", series.SeriesID).
+		First(&created).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load series data: " + err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":           "Event series created successfully",
+		"series":            created,
+		"total_occurrences": len(created.Events),
+	})
+}
+
+// GetEventSeries - Halaman publik series beserta daftar tanggal yang akan datang
+func GetEventSeries(c *fiber.Ctx) error {
+	seriesID := c.Params("id")
+
+	var series models.EventSeries
+	if err := config.DB.Preload("Owner").Where("series_id = ?", seriesID).First(&series).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event series not found",
+		})
+	}
+
+	var upcoming []models.Event
+	if err := config.DB.Preload("TicketCategories", publicTicketCategories).
+		Where("series_id = ? AND status IN ? AND date_end >= ?", series.SeriesID, publicEventStatuses, time.Now()).
+		Order("date_start ASC").
+		Find(&upcoming).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch series occurrences",
+		})
+	}
+	if err := localizeEvents(config.DB, c, upcoming); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch series occurrences",
+		})
+	}
+
+	upcomingDates := make([]fiber.Map, 0)
+	for _, event := range upcoming {
+		upcomingDates = append(upcomingDates, fiber.Map{
+			"event_id":   event.EventID,
+			"name":       event.Name,
+			"date_start": event.DateStart,
+			"date_end":   event.DateEnd,
+			"venue":      event.Venue,
+			"location":   event.Location,
+			"district":   event.District,
+			"status":     event.Status,
+			"min_price":  minPublicPrice(event),
+		})
+	}
+
+	if upcoming == nil {
+		upcoming = []models.Event{}
+	}
+
+	return c.JSON(fiber.Map{
+		"series":         series,
+		"upcoming_dates": upcomingDates,
+		"events":         upcoming,
+	})
+}
+
+// GetMyEventSeries - Semua series milik organizer beserta seluruh occurrence
+func GetMyEventSeries(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	organizationIDs, err := memberOrganizationIDs(config.DB, user.UserID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch your organizations",
+		})
+	}
+
+	var series []models.EventSeries
+	if err := ownedByUserOrOrganizations(config.DB, user.UserID, organizationIDs).
+		Preload("Events", func(db *gorm.DB) *gorm.DB {
+			return db.Order("date_start ASC")
+		}).
+		Order("created_at DESC").
+		Find(&series).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch your event series",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Event series retrieved successfully",
+		"series":  series,
+	})
+}
+
+// UpdateEventSeries - Edit satu occurrence (scope "one") atau semua occurrence mendatang (scope "future").
+// Occurrence yang sudah diedit sendiri (series_exception) tidak ikut diubah oleh scope "future".
+func UpdateEventSeries(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	seriesID := c.Params("id")
+
+	var series models.EventSeries
+	if err := config.DB.Where("series_id = ?", seriesID).First(&series).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event series not found",
+		})
+	}
+
+	if !canAccessSeries(config.DB, user, series, permEventManage) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to update this event series",
+		})
+	}
+
+	var req SeriesUpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if err := validateSeriesTemplates(req.TicketCategories); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// Kategori divalidasi sekali terhadap kategori series, lalu diterapkan ke semua target
+	var categoryColumns map[string]interface{}
+	categoryInput := firstNonEmpty(req.CategoryID, req.Category)
+	childCategoryInput := firstNonEmpty(req.ChildCategoryID, req.ChildCategory)
+	if categoryInput != "" || childCategoryInput != "" {
+		current := models.Event{CategoryID: series.CategoryID, ChildCategoryID: series.ChildCategoryID}
+		parentCategory, childEventCategory, err := resolveEventUpdateCategory(config.DB, categoryInput, childCategoryInput, current)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		categoryColumns = eventCategoryColumns(parentCategory, childEventCategory)
+	}
+
+	var targets []models.Event
+	switch req.Scope {
+	case "one":
+		if req.EventID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "event_id is required for scope 'one'",
+			})
+		}
+		var event models.Event
+		if err := config.DB.Where("event_id = ? AND series_id = ?", req.EventID, series.SeriesID).First(&event).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Occurrence not found in this series",
+			})
+		}
+		targets = append(targets, event)
+	case "future":
+		if req.DateStart != "" || req.DateEnd != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "date_start and date_end can only be changed with scope 'one'",
+			})
+		}
+		pivot := time.Now()
+		if req.EventID != "" {
+			var event models.Event
+			if err := config.DB.Where("event_id = ? AND series_id = ?", req.EventID, series.SeriesID).First(&event).Error; err != nil {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": "Occurrence not found in this series",
+				})
+			}
+			pivot = event.DateStart
+		}
+		if err := config.DB.Where("series_id = ? AND date_start >= ? AND series_exception = ?", series.SeriesID, pivot, false).
+			Order("date_start ASC").
+			Find(&targets).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch series occurrences",
+			})
+		}
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "scope must be either 'one' or 'future'",
+		})
+	}
+
+	// Alamat baru di-geocode sebelum transaksi dibuka karena memanggil layanan eksternal
+	geocoded := map[string]geoPoint{}
+	if req.Location != "" || req.Venue != "" || req.District != "" {
+		for _, event := range targets {
+			if event.Status == "pending" || event.Status == "rejected" {
+				venue, location, district := seriesUpdateAddress(req, event)
+				geocodeCached(geocoded, venue, location, district)
+			}
+		}
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	updated := make([]string, 0)
+	skipped := make([]fiber.Map, 0)
+
+	for _, event := range targets {
+		// Sama seperti UpdateEvent: hanya occurrence pending atau rejected yang bisa diedit langsung
+		if event.Status != "pending" && event.Status != "rejected" {
+			skipped = append(skipped, fiber.Map{
+				"event_id": event.EventID,
+				"status":   event.Status,
+				"reason":   "Occurrence is live or finished and cannot be edited directly",
+			})
+			continue
+		}
+
+		updateData, err := seriesUpdateData(req, event, geocoded)
+		if err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		for column, value := range categoryColumns {
+			updateData[column] = value
+		}
+		updateData["status"] = "pending"
+		updateData["updated_at"] = time.Now()
+		if req.Scope == "one" {
+			updateData["series_exception"] = true
+		}
+
+		if err := tx.Model(&models.Event{}).Where("event_id = ?", event.EventID).Updates(updateData).Error; err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update occurrence: " + err.Error(),
+			})
+		}
+
+		if len(req.TicketCategories) > 0 {
+			// Sale window dihitung dari tanggal occurrence yang baru
+			if t, ok := updateData["date_start"].(time.Time); ok {
+				event.DateStart = t
+			}
+			if t, ok := updateData["date_end"].(time.Time); ok {
+				event.DateEnd = t
+			}
+			if err := syncSeriesTicketCategories(tx, event, req.TicketCategories); err != nil {
+				tx.Rollback()
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			}
+		}
+
+		if err := assignEventSlug(tx, event.EventID); err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to generate occurrence slug",
+			})
+		}
+
+		if err := enqueueModeration(tx, moderationEntityEvent, event.EventID, user.UserID); err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to queue occurrence for review",
+			})
+		}
+
+		updated = append(updated, event.EventID)
+	}
+
+	// Scope future juga memperbarui template series untuk occurrence yang dibuat berikutnya
+	if req.Scope == "future" {
+		seriesData := map[string]interface{}{"updated_at": time.Now()}
+		for column, value := range map[string]string{
+			"name":        req.Name,
+			"description": req.Description,
+			"rules":       req.Rules,
+			"location":    req.Location,
+			"venue":       req.Venue,
+			"district":    req.District,
+		} {
+			if value != "" {
+				seriesData[column] = value
+			}
+		}
+		for column, value := range categoryColumns {
+			seriesData[column] = value
+		}
+		if len(req.TicketCategories) > 0 {
+			templateJSON, _ := json.Marshal(req.TicketCategories)
+			seriesData["ticket_template"] = string(templateJSON)
+		}
+		if err := tx.Model(&models.EventSeries{}).Where("series_id = ?", series.SeriesID).Updates(seriesData).Error; err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update series template",
+			})
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	for _, eventID := range updated {
+		SyncEventLifecycleJobs(config.DB, eventID)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Event series updated",
+		"scope":   req.Scope,
+		"updated": updated,
+		"skipped": skipped,
+	})
+}
+
+func seriesUpdateAddress(req SeriesUpdateRequest, event models.Event) (venue, location, district string) {
+	return firstNonEmpty(req.Venue, event.Venue), firstNonEmpty(req.Location, event.Location), firstNonEmpty(req.District, event.District)
+}
+
+// seriesUpdateData menyusun kolom yang diubah; koordinat diambil dari geocoded yang sudah diisi di luar transaksi
+func seriesUpdateData(req SeriesUpdateRequest, event models.Event, geocoded map[string]geoPoint) (map[string]interface{}, error) {
+	updateData := map[string]interface{}{}
+	for column, value := range map[string]string{
+		"name":        req.Name,
+		"description": req.Description,
+		"rules":       req.Rules,
+		"location":    req.Location,
+		"venue":       req.Venue,
+		"district":    req.District,
+	} {
+		if value != "" {
+			updateData[column] = value
+		}
+	}
+
+	if req.Location != "" || req.Venue != "" || req.District != "" {
+		// Alamat teks bebas melepas occurrence dari venue direktori
+		updateData["venue_id"] = nil
+		if point := geocoded[geoAddressKey(seriesUpdateAddress(req, event))]; point.lat != nil {
+			updateData["latitude"] = *point.lat
+			updateData["longitude"] = *point.lng
+		}
+	}
+
+	dateStart, dateEnd := event.DateStart, event.DateEnd
+	if req.DateStart != "" {
+		t, err := utils.ParseEventTime(req.DateStart, utils.TimeZoneOrDefault(event.TimeZone))
+		if err != nil {
+			return nil, errors.New("invalid date_start format")
+		}
+		dateStart = t
+		updateData["date_start"] = t
+	}
+	if req.DateEnd != "" {
+		t, err := utils.ParseEventTime(req.DateEnd, utils.TimeZoneOrDefault(event.TimeZone))
+		if err != nil {
+			return nil, errors.New("invalid date_end format")
+		}
+		dateEnd = t
+		updateData["date_end"] = t
+	}
+	if !dateEnd.After(dateStart) {
+		return nil, errors.New("date_end must be after date_start")
+	}
+
+	return updateData, nil
+}
+
+// syncSeriesTicketCategories memperbarui ticket category occurrence secara in-place berdasarkan nama,
+// membuat category baru jika belum ada. Quota tidak boleh lebih kecil dari tiket yang sudah terjual.
+func syncSeriesTicketCategories(tx *gorm.DB, event models.Event, templates []SeriesTicketTemplate) error {
+	var existing []models.TicketCategory
+	if err := tx.Where("event_id = ?", event.EventID).Find(&existing).Error; err != nil {
+		return err
+	}
+
+	byName := make(map[string]models.TicketCategory)
+	for _, tc := range existing {
+		byName[tc.Name] = tc
+	}
+
+	for _, tmpl := range templates {
+		generated := ticketCategoryFromTemplate(tmpl, event)
+
+		current, ok := byName[tmpl.Name]
+		if !ok {
+			if err := tx.Create(&generated).Error; err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := checkTicketCategoryQuota(current, tmpl.Quota, tmpl.CompQuota); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.TicketCategory{}).
+			Where("ticket_category_id = ?", current.TicketCategoryID).
+			Updates(map[string]interface{}{
+				"price":           tmpl.Price,
+				"quota":           tmpl.Quota,
+				"comp_quota":      tmpl.CompQuota,
+				"is_hidden":       tmpl.IsHidden,
+				"description":     tmpl.Description,
+				"date_time_start": generated.DateTimeStart,
+				"date_time_end":   generated.DateTimeEnd,
+				"updated_at":      time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func ticketCategoryFromTemplate(tmpl SeriesTicketTemplate, event models.Event) models.TicketCategory {
+	dateTimeStart := event.DateStart.Add(time.Duration(tmpl.StartOffsetMinutes) * time.Minute)
+	dateTimeEnd := event.DateEnd
+	if tmpl.EndOffsetMinutes != nil {
+		dateTimeEnd = event.DateStart.Add(time.Duration(*tmpl.EndOffsetMinutes) * time.Minute)
+	}
+
+	return models.TicketCategory{
+		TicketCategoryID: utils.GenerateTicketCategoryID(),
+		EventID:          event.EventID,
+		Name:             tmpl.Name,
+		Price:            tmpl.Price,
+		Quota:            tmpl.Quota,
+		CompQuota:        tmpl.CompQuota,
+		IsHidden:         tmpl.IsHidden,
+		Description:      tmpl.Description,
+		DateTimeStart:    dateTimeStart,
+		DateTimeEnd:      dateTimeEnd,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+}
+
+func validateSeriesTemplates(templates []SeriesTicketTemplate) error {
+	seen := make(map[string]bool)
+	for _, tmpl := range templates {
+		if tmpl.Name == "" {
+			return errors.New("ticket category name is required")
+		}
+		if seen[tmpl.Name] {
+			return errors.New("Duplicate ticket category name : " + tmpl.Name)
+		}
+		seen[tmpl.Name] = true
+		if tmpl.EndOffsetMinutes != nil && *tmpl.EndOffsetMinutes <= tmpl.StartOffsetMinutes {
+			return errors.New("end_offset_minutes must be greater than start_offset_minutes for " + tmpl.Name)
+		}
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
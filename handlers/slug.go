@@ -0,0 +1,410 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxSlugSuffix = 1000
+
+// eventSlugBase membentuk slug dasar dari nama event. Occurrence series diberi tanggal
+// supaya tidak menjadi nama-2, nama-3, dst.
+func eventSlugBase(event models.Event) string {
+	base := utils.Slugify(event.Name)
+	if base == "" {
+		base = "event"
+	}
+	if event.SeriesID != nil {
+		base += "-" + event.DateStart.In(utils.TimeZoneOrDefault(event.TimeZone)).Format("2006-01-02")
+	}
+	return base
+}
+
+// slugMatchesBase mengecek apakah slug event masih sesuai base. Slug bersufiks hanya diterima
+// jika memang dibuat dari base tersebut, sehingga nama yang berakhiran angka (misal "Konser 2024"
+// diganti menjadi "Konser") tetap mendapat slug baru.
+func slugMatchesBase(tx *gorm.DB, event models.Event, base string) (bool, error) {
+	if event.Slug == base {
+		return true, nil
+	}
+	if !strings.HasPrefix(event.Slug, base+"-") {
+		return false, nil
+	}
+
+	var current models.EventSlug
+	err := tx.Where("slug = ? AND event_id = ?", event.Slug, event.EventID).First(&current).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if current.Base != "" {
+		return current.Base == base, nil
+	}
+
+	// Slug lama tanpa base: sufiks hanya sah jika base memang dipakai event lain
+	if _, err := strconv.Atoi(strings.TrimPrefix(event.Slug, base+"-")); err != nil {
+		return false, nil
+	}
+	var taken int64
+	if err := tx.Model(&models.EventSlug{}).
+		Where("slug = ? AND event_id <> ?", base, event.EventID).
+		Count(&taken).Error; err != nil {
+		return false, err
+	}
+	return taken > 0, nil
+}
+
+// assignEventSlug memastikan event punya slug unik yang sesuai namanya. Slug tetap stabil selama
+// nama tidak berubah; slug lama disimpan di event_slugs sebagai riwayat untuk redirect.
+func assignEventSlug(tx *gorm.DB, eventID string) error {
+	var event models.Event
+	if err := tx.Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		return err
+	}
+
+	base := eventSlugBase(event)
+	if event.Slug != "" {
+		matches, err := slugMatchesBase(tx, event, base)
+		if err != nil {
+			return err
+		}
+		if matches {
+			return nil
+		}
+	}
+
+	slug := ""
+	for i := 1; i <= maxSlugSuffix && slug == ""; i++ {
+		candidate := base
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, i)
+		}
+
+		var existing models.EventSlug
+		err := tx.Where("slug = ?", candidate).First(&existing).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Create(&models.EventSlug{
+				Slug:      candidate,
+				EventID:   event.EventID,
+				Base:      base,
+				CreatedAt: time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+			slug = candidate
+		case err != nil:
+			return err
+		case existing.EventID == event.EventID:
+			// Nama dikembalikan ke nama lama: pakai lagi slug lama milik event ini
+			if err := tx.Model(&existing).UpdateColumn("base", base).Error; err != nil {
+				return err
+			}
+			slug = candidate
+		}
+	}
+	if slug == "" {
+		slug = base + "-" + strings.TrimPrefix(event.EventID, "event-")[:8]
+		if err := tx.Create(&models.EventSlug{Slug: slug, EventID: event.EventID, Base: base, CreatedAt: time.Now()}).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&models.Event{}).Where("event_id = ?", event.EventID).UpdateColumn("slug", slug).Error
+}
+
+// InitEventSlugs memberi slug pada event lama yang belum punya
+func InitEventSlugs(db *gorm.DB) error {
+	var eventIDs []string
+	if err := db.Model(&models.Event{}).Where("slug = ? OR slug IS NULL", "").Pluck("event_id", &eventIDs).Error; err != nil {
+		return err
+	}
+	for _, eventID := range eventIDs {
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			return assignEventSlug(tx, eventID)
+		}); err != nil {
+			return err
+		}
+	}
+	if len(eventIDs) > 0 {
+		log.Println(" --  Slugs generated for " + strconv.Itoa(len(eventIDs)) + " events")
+	}
+	return nil
+}
+
+// resolveEventSlug mencari event dari slug saat ini atau slug lama
+func resolveEventSlug(db *gorm.DB, slug string) (models.Event, bool, error) {
+	var event models.Event
+	if err := db.Where("slug = ?", slug).First(&event).Error; err == nil {
+		return event, true, nil
+	}
+
+	var history models.EventSlug
+	if err := db.Where("slug = ?", slug).First(&history).Error; err != nil {
+		return event, false, err
+	}
+	if err := db.Where("event_id = ?", history.EventID).First(&event).Error; err != nil {
+		return event, false, err
+	}
+	return event, false, nil
+}
+
+// publicEventURL membentuk URL halaman event di frontend (PUBLIC_BASE_URL, default host request)
+func publicEventURL(c *fiber.Ctx, slug string) string {
+	base := os.Getenv("PUBLIC_BASE_URL")
+	if base == "" {
+		base = c.BaseURL()
+	}
+	return strings.TrimRight(base, "/") + "/events/" + slug
+}
+
+// GetEventBySlug - Detail event berdasarkan slug. Slug lama dijawab 301 ke slug terbaru.
+func GetEventBySlug(c *fiber.Ctx) error {
+	event, current, err := resolveEventSlug(config.DB, c.Params("slug"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+
+	if !current {
+		location := "/api/events/slug/" + event.Slug
+		if query := string(c.Request().URI().QueryString()); query != "" {
+			location += "?" + query
+		}
+		c.Set(fiber.HeaderLocation, location)
+		return c.Status(fiber.StatusMovedPermanently).JSON(fiber.Map{
+			"message":  "Event slug has changed",
+			"slug":     event.Slug,
+			"location": location,
+		})
+	}
+
+	return respondEventDetail(c, event.EventID)
+}
+
+// GetEventShareMetadata - Metadata OpenGraph, Twitter card, dan JSON-LD (schema.org Event).
+// ?format=html mengembalikan tag <meta> dan <script type="application/ld+json"> siap pakai.
+func GetEventShareMetadata(c *fiber.Ctx) error {
+	event, current, err := resolveEventSlug(config.DB, c.Params("slug"))
+	if err != nil || !containsString(publicEventStatuses, event.Status) && event.Status != "cancelled" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Event not found",
+		})
+	}
+	if !current {
+		location := "/api/events/slug/" + event.Slug + "/meta"
+		if query := string(c.Request().URI().QueryString()); query != "" {
+			location += "?" + query
+		}
+		c.Set(fiber.HeaderLocation, location)
+		return c.Status(fiber.StatusMovedPermanently).JSON(fiber.Map{
+			"message":  "Event slug has changed",
+			"slug":     event.Slug,
+			"location": location,
+		})
+	}
+
+	if err := config.DB.Preload("Owner").Preload("Organization").
+		Preload("TicketCategories", publicTicketCategories).
+		Preload("Gallery", galleryOrder).
+		Where("event_id = ?", event.EventID).
+		First(&event).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load event",
+		})
+	}
+
+	if err := localizeEvent(config.DB, c, &event); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load event",
+		})
+	}
+
+	canonicalURL := publicEventURL(c, event.Slug)
+	description := shareDescription(event)
+
+	openGraph := fiber.Map{
+		"og:type":        "website",
+		"og:title":       event.Name,
+		"og:description": description,
+		"og:url":         canonicalURL,
+		"og:image":       event.Image,
+		"og:site_name":   "Ticketing",
+		"og:locale":      event.Locale,
+	}
+	twitter := fiber.Map{
+		"twitter:card":        "summary_large_image",
+		"twitter:title":       event.Name,
+		"twitter:description": description,
+		"twitter:image":       event.Image,
+	}
+	jsonLD := eventJSONLD(event, canonicalURL, description)
+
+	if c.Query("format") == "html" {
+		return c.Type("html").SendString(shareMetadataHTML(event.Name, canonicalURL, openGraph, twitter, jsonLD))
+	}
+
+	return c.JSON(fiber.Map{
+		"message":       "Event metadata retrieved successfully",
+		"slug":          event.Slug,
+		"canonical_url": canonicalURL,
+		"title":         event.Name,
+		"description":   description,
+		"opengraph":     openGraph,
+		"twitter":       twitter,
+		"json_ld":       jsonLD,
+	})
+}
+
+// shareDescription memotong deskripsi event untuk preview link
+func shareDescription(event models.Event) string {
+	description := strings.Join(strings.Fields(event.Description), " ")
+	if description == "" {
+		description = fmt.Sprintf("%s at %s, %s on %s", event.Name, event.Venue, event.District, event.DateStart.In(utils.TimeZoneOrDefault(event.TimeZone)).Format("2 January 2006"))
+	}
+	if runes := []rune(description); len(runes) > 200 {
+		description = strings.TrimSpace(string(runes[:197])) + "..."
+	}
+	return description
+}
+
+// eventJSONLD membentuk data terstruktur schema.org Event
+func eventJSONLD(event models.Event, canonicalURL, description string) fiber.Map {
+	// schema.org menyarankan waktu lokal lokasi event beserta offset-nya
+	loc := utils.TimeZoneOrDefault(event.TimeZone)
+	status := "https://schema.org/EventScheduled"
+	switch {
+	case event.Status == "cancelled":
+		status = "https://schema.org/EventCancelled"
+	case event.PostponedAt != nil:
+		status = "https://schema.org/EventRescheduled"
+	}
+
+	images := []string{}
+	if event.Image != "" {
+		images = append(images, event.Image)
+	}
+	for _, media := range event.Gallery {
+		if media.URL != event.Image {
+			images = append(images, media.URL)
+		}
+	}
+
+	place := fiber.Map{
+		"@type": "Place",
+		"name":  event.Venue,
+		"address": fiber.Map{
+			"@type":           "PostalAddress",
+			"streetAddress":   event.Location,
+			"addressLocality": event.District,
+			"addressCountry":  "ID",
+		},
+	}
+	if event.Latitude != nil && event.Longitude != nil {
+		place["geo"] = fiber.Map{
+			"@type":     "GeoCoordinates",
+			"latitude":  *event.Latitude,
+			"longitude": *event.Longitude,
+		}
+	}
+
+	organizerName := event.Owner.Name
+	if event.Organization != nil && event.Organization.Name != "" {
+		organizerName = event.Organization.Name
+	}
+
+	offers := make([]fiber.Map, 0, len(event.TicketCategories))
+	for _, tc := range event.TicketCategories {
+		availability := "https://schema.org/InStock"
+		if tc.Quota > 0 && tc.Sold >= tc.Quota {
+			availability = "https://schema.org/SoldOut"
+		}
+		offers = append(offers, fiber.Map{
+			"@type":         "Offer",
+			"name":          tc.Name,
+			"price":         tc.Price,
+			"priceCurrency": "IDR",
+			"availability":  availability,
+			"validFrom":     utils.FormatLocal(tc.DateTimeStart, loc),
+			"url":           canonicalURL,
+		})
+	}
+
+	data := fiber.Map{
+		"@context":            "https://schema.org",
+		"@type":               "Event",
+		"inLanguage":          event.Locale,
+		"name":                event.Name,
+		"description":         description,
+		"startDate":           utils.FormatLocal(event.DateStart, loc),
+		"endDate":             utils.FormatLocal(event.DateEnd, loc),
+		"eventStatus":         status,
+		"eventAttendanceMode": "https://schema.org/OfflineEventAttendanceMode",
+		"location":            place,
+		"image":               images,
+		"url":                 canonicalURL,
+		"organizer": fiber.Map{
+			"@type": "Organization",
+			"name":  organizerName,
+		},
+		"offers": offers,
+	}
+	if event.OriginalDateStart != nil {
+		data["previousStartDate"] = utils.FormatLocal(*event.OriginalDateStart, loc)
+	}
+	if event.RatingCount > 0 {
+		data["aggregateRating"] = fiber.Map{
+			"@type":       "AggregateRating",
+			"ratingValue": event.RatingAverage,
+			"reviewCount": event.RatingCount,
+		}
+	}
+	return data
+}
+
+// shareMetadataHTML merender tag meta untuk crawler yang tidak menjalankan JavaScript
+func shareMetadataHTML(title, canonicalURL string, openGraph, twitter, jsonLD fiber.Map) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html><head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<title>" + html.EscapeString(title) + "</title>\n")
+	b.WriteString("<link rel=\"canonical\" href=\"" + html.EscapeString(canonicalURL) + "\">\n")
+	for _, tags := range []struct {
+		attr   string
+		values fiber.Map
+		keys   []string
+	}{
+		{"property", openGraph, []string{"og:type", "og:title", "og:description", "og:url", "og:image", "og:site_name", "og:locale"}},
+		{"name", twitter, []string{"twitter:card", "twitter:title", "twitter:description", "twitter:image"}},
+	} {
+		for _, key := range tags.keys {
+			value, _ := tags.values[key].(string)
+			if value == "" {
+				continue
+			}
+			b.WriteString(fmt.Sprintf("<meta %s=\"%s\" content=\"%s\">\n", tags.attr, key, html.EscapeString(value)))
+		}
+	}
+	// json.Marshal meng-escape <, >, & sehingga aman di dalam tag script
+	data, _ := json.Marshal(jsonLD)
+	b.WriteString("<script type=\"application/ld+json\">" + string(data) + "</script>\n")
+	b.WriteString("<meta http-equiv=\"refresh\" content=\"0; url=" + html.EscapeString(canonicalURL) + "\">\n")
+	b.WriteString("</head><body></body></html>\n")
+	return b.String()
+}
@@ -22,26 +22,122 @@ type ticketResponse struct {
 	Status         string                  `json:"status"`     // ADDED: Status tiket
 	UsedAt         *time.Time              `json:"used_at"`    // ADDED: Waktu check-in
 	CreatedAt      time.Time               `json:"created_at"` // ADDED: Waktu pembuatan
+	Complimentary  bool                    `json:"is_complimentary"`
+	Announcements  []announcementResponse  `json:"announcements"`
 }
 
 type ticketCategoryResponse struct {
-	TicketCategoryID string    `json:"ticket_category_id"` // ADDED
-	Name             string    `json:"name"`
-	DateTimeStart    time.Time `json:"date_time_start"`
-	DateTimeEnd      time.Time `json:"date_time_end"`
-	Price            float64   `json:"price"`
-	Description      string    `json:"description"`
+	TicketCategoryID   string    `json:"ticket_category_id"` // ADDED
+	Name               string    `json:"name"`
+	DateTimeStart      time.Time `json:"date_time_start"`
+	DateTimeEnd        time.Time `json:"date_time_end"`
+	DateTimeStartLocal string    `json:"date_time_start_local"`
+	DateTimeEndLocal   string    `json:"date_time_end_local"`
+	Price              float64   `json:"price"`
+	Description        string    `json:"description"`
 }
 
 type eventResponse struct {
-	EventID   string    `json:"event_id"` // ADDED
-	Name      string    `json:"name"`
-	Location  string    `json:"location"`
-	Venue     string    `json:"venue"` // Fixed: lowercase
-	City      string    `json:"city"`  // ADDED (from District)
-	DateStart time.Time `json:"date_start"`
-	DateEnd   time.Time `json:"date_end"`
-	Image     string    `json:"image"` // ADDED
+	EventID        string    `json:"event_id"` // ADDED
+	Name           string    `json:"name"`
+	Location       string    `json:"location"`
+	Venue          string    `json:"venue"` // Fixed: lowercase
+	City           string    `json:"city"`  // ADDED (from District)
+	DateStart      time.Time `json:"date_start"`
+	DateEnd        time.Time `json:"date_end"`
+	TimeZone       string    `json:"time_zone"`
+	DateStartLocal string    `json:"date_start_local"`
+	DateEndLocal   string    `json:"date_end_local"`
+	Image          string    `json:"image"` // ADDED
+}
+
+// localizeTicketEvent menerjemahkan event dan category satu tiket ke bahasa request
+func localizeTicketEvent(c *fiber.Ctx, event *models.Event, ticketCategory *models.TicketCategory) {
+	event.TicketCategories = []models.TicketCategory{*ticketCategory}
+	if err := localizeEvent(config.DB, c, event); err != nil {
+		log.Printf("Failed to localize event %s: %v", event.EventID, err)
+	}
+	*ticketCategory = event.TicketCategories[0]
+}
+
+// loadTicketEvents memuat event dan category semua tiket sekaligus, sudah diterjemahkan
+// ke bahasa request
+func loadTicketEvents(c *fiber.Ctx, tickets []models.Ticket) (map[string]models.Event, map[string]models.TicketCategory, error) {
+	eventIDs := make([]string, 0, len(tickets))
+	categoryIDs := make([]string, 0, len(tickets))
+	seenEvents := make(map[string]bool)
+	seenCategories := make(map[string]bool)
+	for _, ticket := range tickets {
+		if !seenEvents[ticket.EventID] {
+			seenEvents[ticket.EventID] = true
+			eventIDs = append(eventIDs, ticket.EventID)
+		}
+		if !seenCategories[ticket.TicketCategoryID] {
+			seenCategories[ticket.TicketCategoryID] = true
+			categoryIDs = append(categoryIDs, ticket.TicketCategoryID)
+		}
+	}
+
+	events := make(map[string]models.Event, len(eventIDs))
+	categories := make(map[string]models.TicketCategory, len(categoryIDs))
+	if len(eventIDs) == 0 {
+		return events, categories, nil
+	}
+
+	var loaded []models.Event
+	if err := config.DB.Preload("TicketCategories", "ticket_category_id IN ?", categoryIDs).
+		Where("event_id IN ?", eventIDs).
+		Find(&loaded).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := localizeEvents(config.DB, c, loaded); err != nil {
+		log.Printf("Failed to localize ticket events: %v", err)
+	}
+	for _, event := range loaded {
+		for _, tc := range event.TicketCategories {
+			categories[tc.TicketCategoryID] = tc
+		}
+		event.TicketCategories = nil
+		events[event.EventID] = event
+	}
+	return events, categories, nil
+}
+
+// newTicketEventResponses membentuk ringkasan event dan category tiket, waktu dalam UTC dan
+// zona event. Event dan category sudah diterjemahkan oleh caller.
+func newTicketEventResponses(event models.Event, ticketCategory models.TicketCategory) (eventResponse, ticketCategoryResponse) {
+	loc := utils.TimeZoneOrDefault(event.TimeZone)
+	return eventResponse{
+		EventID:        event.EventID,
+		Name:           event.Name,
+		Location:       event.Location,
+		Venue:          event.Venue,
+		City:           event.District, // Using District as City
+		DateStart:      event.DateStart,
+		DateEnd:        event.DateEnd,
+		TimeZone:       loc.String(),
+		DateStartLocal: utils.FormatLocal(event.DateStart, loc),
+		DateEndLocal:   utils.FormatLocal(event.DateEnd, loc),
+		Image:          event.Image,
+	}, ticketCategoryResponse{
+		TicketCategoryID:   ticketCategory.TicketCategoryID,
+		Name:               ticketCategory.Name,
+		DateTimeStart:      ticketCategory.DateTimeStart,
+		DateTimeEnd:        ticketCategory.DateTimeEnd,
+		DateTimeStartLocal: utils.FormatLocal(ticketCategory.DateTimeStart, loc),
+		DateTimeEndLocal:   utils.FormatLocal(ticketCategory.DateTimeEnd, loc),
+		Price:              ticketCategory.Price,
+		Description:        ticketCategory.Description,
+	}
+}
+
+// computedTicketStatus menandai tiket aktif sebagai expired setelah event selesai.
+// DateEnd disimpan sebagai instant UTC sehingga perbandingan tidak bergantung zona server.
+func computedTicketStatus(ticket models.Ticket, event models.Event) string {
+	if ticket.Status == "active" && event.DateEnd.Before(time.Now()) {
+		return "expired"
+	}
+	return ticket.Status
 }
 
 // GetTickets - Mengambil SEMUA tiket milik user (tidak hanya active)
@@ -65,50 +161,47 @@ func GetTickets(c *fiber.Ctx) error {
 		})
 	}
 
+	// Pengumuman organizer dimuat sekali untuk semua event tiket
+	eventIDs := make([]string, 0, len(tickets))
+	for _, ticket := range tickets {
+		eventIDs = append(eventIDs, ticket.EventID)
+	}
+	announcements, err := ticketAnnouncements(config.DB, eventIDs)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch announcements",
+		})
+	}
+
+	// Event dan category dimuat serta diterjemahkan sekali untuk semua tiket
+	events, ticketCategories, err := loadTicketEvents(c, tickets)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch event",
+		})
+	}
+
 	var ticketResponses []ticketResponse
 
 	for _, ticket := range tickets {
-		var ticketCategory models.TicketCategory
-		if err := config.DB.First(&ticketCategory, "ticket_category_id = ?", ticket.TicketCategoryID).Error; err != nil {
+		ticketCategory, ok := ticketCategories[ticket.TicketCategoryID]
+		if !ok {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to fetch ticket category",
 			})
 		}
 
-		var event models.Event
-		if err := config.DB.First(&event, "event_id = ?", ticket.EventID).Error; err != nil {
+		event, ok := events[ticket.EventID]
+		if !ok {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to fetch event",
 			})
 		}
 
 		// Determine computed status
-		computedStatus := ticket.Status
+		computedStatus := computedTicketStatus(ticket, event)
 
-		// Check if event has ended (expired)
-		if ticket.Status == "active" && event.DateEnd.Before(time.Now()) {
-			computedStatus = "expired"
-		}
-
-		ticketCategoryResponse := ticketCategoryResponse{
-			TicketCategoryID: ticketCategory.TicketCategoryID,
-			Name:             ticketCategory.Name,
-			DateTimeStart:    ticketCategory.DateTimeStart,
-			DateTimeEnd:      ticketCategory.DateTimeEnd,
-			Price:            ticketCategory.Price,
-			Description:      ticketCategory.Description,
-		}
-
-		eventResponse := eventResponse{
-			EventID:   event.EventID,
-			Name:      event.Name,
-			Location:  event.Location,
-			Venue:     event.Venue,
-			City:      event.District, // Using District as City
-			DateStart: event.DateStart,
-			DateEnd:   event.DateEnd,
-			Image:     event.Image,
-		}
+		eventResponse, ticketCategoryResponse := newTicketEventResponses(event, ticketCategory)
 
 		// Determine used_at (jika status used, gunakan UpdatedAt sebagai waktu check-in)
 		var usedAt *time.Time
@@ -125,6 +218,8 @@ func GetTickets(c *fiber.Ctx) error {
 			Status:         computedStatus,
 			UsedAt:         usedAt,
 			CreatedAt:      ticket.CreatedAt,
+			Complimentary:  ticket.IsComplimentary,
+			Announcements:  announcementsForCategory(announcements[ticket.EventID], ticket.TicketCategoryID),
 		}
 		ticketResponses = append(ticketResponses, ticketResponse)
 	}
@@ -190,7 +285,9 @@ func CheckInTicket(c *fiber.Ctx) error {
 		}
 	}
 
+	checkedInAt := time.Now()
 	ticket.Status = "used"
+	ticket.CheckedInAt = &checkedInAt
 	if err := tx.Save(&ticket).Error; err != nil {
 		tx.Rollback()
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -231,7 +328,7 @@ func CheckInTicket(c *fiber.Ctx) error {
 			"ticket_id":       ticket.TicketID,
 			"code":            ticket.Code,
 			"status":          ticket.Status,
-			"checked_in_at":   ticket.UpdatedAt,
+			"checked_in_at":   ticket.CheckedInAt,
 			"ticket_category": ticketCategory.Name,
 			"date_start":      ticketCategory.DateTimeStart,
 			"date_end":        ticketCategory.DateTimeEnd,
@@ -274,25 +371,8 @@ func GetTicketCode(c *fiber.Ctx) error {
 		})
 	}
 
-	ticketCategoryResponse := ticketCategoryResponse{
-		TicketCategoryID: ticketCategory.TicketCategoryID,
-		Name:             ticketCategory.Name,
-		DateTimeStart:    ticketCategory.DateTimeStart,
-		DateTimeEnd:      ticketCategory.DateTimeEnd,
-		Price:            ticketCategory.Price,
-		Description:      ticketCategory.Description,
-	}
-
-	eventResponse := eventResponse{
-		EventID:   event.EventID,
-		Name:      event.Name,
-		Location:  event.Location,
-		Venue:     event.Venue,
-		City:      event.District,
-		DateStart: event.DateStart,
-		DateEnd:   event.DateEnd,
-		Image:     event.Image,
-	}
+	localizeTicketEvent(c, &event, &ticketCategory)
+	eventResponse, ticketCategoryResponse := newTicketEventResponses(event, ticketCategory)
 
 	// check if ticket is expired
 	if !ticket.ExpiresAt.IsZero() && ticket.ExpiresAt.Before(time.Now()) {
@@ -323,9 +403,13 @@ func GetTicketCode(c *fiber.Ctx) error {
 	}
 
 	// Determine computed status
-	computedStatus := ticket.Status
-	if ticket.Status == "active" && event.DateEnd.Before(time.Now()) {
-		computedStatus = "expired"
+	computedStatus := computedTicketStatus(ticket, event)
+
+	announcements, err := ticketAnnouncements(config.DB, []string{event.EventID})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch announcements",
+		})
 	}
 
 	// prepare response data
@@ -337,6 +421,8 @@ func GetTicketCode(c *fiber.Ctx) error {
 		Tag:            ticket.Tag,
 		Status:         computedStatus,
 		CreatedAt:      ticket.CreatedAt,
+		Complimentary:  ticket.IsComplimentary,
+		Announcements:  announcementsForCategory(announcements[event.EventID], ticket.TicketCategoryID),
 	}
 
 	return c.JSON(fiber.Map{
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+)
+
+// errInvalidTicketCategory menandai kesalahan input (400), bukan kesalahan database
+var errInvalidTicketCategory = errors.New("invalid ticket category")
+
+// checkTicketCategoryQuota memastikan quota baru tidak lebih kecil dari tiket yang sudah terjual / dibagikan
+func checkTicketCategoryQuota(tc models.TicketCategory, quota, compQuota uint) error {
+	if quota < tc.Sold {
+		return fmt.Errorf("%w: quota for %s cannot be lower than tickets already sold (%d)", errInvalidTicketCategory, tc.Name, tc.Sold)
+	}
+	if compQuota < tc.CompIssued {
+		return fmt.Errorf("%w: comp_quota for %s cannot be lower than complimentary tickets already issued (%d)", errInvalidTicketCategory, tc.Name, tc.CompIssued)
+	}
+	return nil
+}
+
+// checkSaleWindow memastikan category sedang dalam jendela penjualan. Pesan error memakai
+// jam lokal zona event supaya sesuai dengan yang ditampilkan ke pembeli.
+func checkSaleWindow(db *gorm.DB, tc models.TicketCategory) error {
+	var timeZone string
+	db.Model(&models.Event{}).Where("event_id = ?", tc.EventID).Pluck("time_zone", &timeZone)
+	loc := utils.TimeZoneOrDefault(timeZone)
+
+	now := time.Now()
+	if !tc.DateTimeStart.IsZero() && now.Before(tc.DateTimeStart) {
+		return fmt.Errorf("sales for %s open at %s", tc.Name, tc.DateTimeStart.In(loc).Format("02 Jan 2006 15:04 MST"))
+	}
+	if !tc.DateTimeEnd.IsZero() && now.After(tc.DateTimeEnd) {
+		return fmt.Errorf("sales for %s closed at %s", tc.Name, tc.DateTimeEnd.In(loc).Format("02 Jan 2006 15:04 MST"))
+	}
+	return nil
+}
+
+// parseTicketCategoryDates mengurai jendela penjualan; waktu tanpa offset diartikan dalam zona event
+func parseTicketCategoryDates(req TicketCategoryRequest, loc *time.Location) (time.Time, time.Time, error) {
+	dateTimeStart, err := utils.ParseEventTime(req.DateTimeStart, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid date_time_start format for %s", errInvalidTicketCategory, req.Name)
+	}
+	dateTimeEnd, err := utils.ParseEventTime(req.DateTimeEnd, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid date_time_end format for %s", errInvalidTicketCategory, req.Name)
+	}
+	return dateTimeStart, dateTimeEnd, nil
+}
+
+// updateTicketCategoryInPlace mengubah ticket category tanpa menghapus row-nya,
+// sehingga cart, tiket dan transaksi yang sudah mereferensikan ID-nya tetap valid
+func updateTicketCategoryInPlace(tx *gorm.DB, ticketCategoryID string, req TicketCategoryRequest, loc *time.Location) error {
+	var current models.TicketCategory
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("ticket_category_id = ?", ticketCategoryID).
+		First(&current).Error; err != nil {
+		return err
+	}
+
+	if err := checkTicketCategoryQuota(current, req.Quota, req.CompQuota); err != nil {
+		return err
+	}
+
+	dateTimeStart, dateTimeEnd, err := parseTicketCategoryDates(req, loc)
+	if err != nil {
+		return err
+	}
+
+	return tx.Model(&models.TicketCategory{}).
+		Where("ticket_category_id = ?", current.TicketCategoryID).
+		Updates(map[string]interface{}{
+			"name":            req.Name,
+			"price":           req.Price,
+			"quota":           req.Quota,
+			"comp_quota":      req.CompQuota,
+			"is_hidden":       req.IsHidden,
+			"description":     req.Description,
+			"date_time_start": dateTimeStart,
+			"date_time_end":   dateTimeEnd,
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+func createTicketCategory(tx *gorm.DB, eventID string, req TicketCategoryRequest, loc *time.Location) (models.TicketCategory, error) {
+	dateTimeStart, dateTimeEnd, err := parseTicketCategoryDates(req, loc)
+	if err != nil {
+		return models.TicketCategory{}, err
+	}
+
+	ticketCategory := models.TicketCategory{
+		TicketCategoryID: utils.GenerateTicketCategoryID(),
+		EventID:          eventID,
+		Name:             req.Name,
+		Price:            req.Price,
+		Quota:            req.Quota,
+		CompQuota:        req.CompQuota,
+		IsHidden:         req.IsHidden,
+		Description:      req.Description,
+		DateTimeStart:    dateTimeStart,
+		DateTimeEnd:      dateTimeEnd,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+
+	return ticketCategory, tx.Create(&ticketCategory).Error
+}
+
+// syncTicketCategories menyamakan ticket category event dengan daftar request.
+// Category dicocokkan lewat ticket_category_id lalu nama; yang tidak ada di daftar
+// hanya dihapus jika belum punya tiket, cart, atau transaksi.
+func syncTicketCategories(tx *gorm.DB, eventID string, reqs []TicketCategoryRequest, loc *time.Location) error {
+	var existing []models.TicketCategory
+	if err := tx.Where("event_id = ?", eventID).Find(&existing).Error; err != nil {
+		return err
+	}
+
+	byID := make(map[string]models.TicketCategory)
+	byName := make(map[string]models.TicketCategory)
+	for _, tc := range existing {
+		byID[tc.TicketCategoryID] = tc
+		byName[tc.Name] = tc
+	}
+
+	kept := make(map[string]bool)
+	names := make(map[string]bool)
+	for _, req := range reqs {
+		if req.Name == "" {
+			return fmt.Errorf("%w: ticket category name is required", errInvalidTicketCategory)
+		}
+		if names[req.Name] {
+			return fmt.Errorf("%w: duplicate ticket category name %s", errInvalidTicketCategory, req.Name)
+		}
+		names[req.Name] = true
+
+		current, ok := byID[req.TicketCategoryID]
+		if !ok && req.TicketCategoryID != "" {
+			return fmt.Errorf("%w: ticket category %s does not belong to this event", errInvalidTicketCategory, req.TicketCategoryID)
+		}
+		if !ok {
+			current, ok = byName[req.Name]
+		}
+
+		if !ok {
+			if _, err := createTicketCategory(tx, eventID, req, loc); err != nil {
+				return err
+			}
+			continue
+		}
+
+		kept[current.TicketCategoryID] = true
+		if err := updateTicketCategoryInPlace(tx, current.TicketCategoryID, req, loc); err != nil {
+			return err
+		}
+	}
+
+	for _, tc := range existing {
+		if kept[tc.TicketCategoryID] {
+			continue
+		}
+		if err := deleteUnusedTicketCategory(tx, tc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deleteUnusedTicketCategory(tx *gorm.DB, tc models.TicketCategory) error {
+	if tc.Sold > 0 || tc.CompIssued > 0 {
+		return fmt.Errorf("%w: %s already has tickets and cannot be removed", errInvalidTicketCategory, tc.Name)
+	}
+
+	var references int64
+	for _, model := range []interface{}{&models.Cart{}, &models.Ticket{}, &models.TransactionDetail{}, &models.CompTicketClaim{}} {
+		var count int64
+		if err := tx.Model(model).Where("ticket_category_id = ?", tc.TicketCategoryID).Count(&count).Error; err != nil {
+			return err
+		}
+		references += count
+	}
+	if references > 0 {
+		return fmt.Errorf("%w: %s is referenced by carts or transactions and cannot be removed", errInvalidTicketCategory, tc.Name)
+	}
+
+	if err := tx.Exec("DELETE FROM access_code_categories WHERE ticket_category_id = ?", tc.TicketCategoryID).Error; err != nil {
+		return err
+	}
+	// Pengumuman tanpa kategori tersisa akan berlaku untuk semua pemegang tiket,
+	// jadi pengumuman yang hanya menargetkan kategori ini ikut dihapus
+	var announcementIDs []string
+	if err := tx.Table("announcement_categories").
+		Where("ticket_category_id = ?", tc.TicketCategoryID).
+		Pluck("announcement_id", &announcementIDs).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("DELETE FROM announcement_categories WHERE ticket_category_id = ?", tc.TicketCategoryID).Error; err != nil {
+		return err
+	}
+	if len(announcementIDs) > 0 {
+		if err := tx.Where("announcement_id IN ?", announcementIDs).
+			Where("NOT EXISTS (SELECT 1 FROM announcement_categories ac WHERE ac.announcement_id = event_announcements.announcement_id)").
+			Delete(&models.EventAnnouncement{}).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Where("ticket_category_id = ?", tc.TicketCategoryID).Delete(&models.TicketCategoryTranslation{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("ticket_category_id = ?", tc.TicketCategoryID).Delete(&models.TicketCategory{}).Error
+}
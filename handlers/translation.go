@@ -0,0 +1,402 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TicketCategoryTranslationRequest struct {
+	TicketCategoryID string `json:"ticket_category_id"`
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+}
+
+type EventTranslationRequest struct {
+	Name             string                             `json:"name"`
+	Description      string                             `json:"description"`
+	Rules            string                             `json:"rules"`
+	TicketCategories []TicketCategoryTranslationRequest `json:"ticket_categories"`
+}
+
+// requestedLocales menentukan urutan bahasa yang diminta: ?lang= lalu Accept-Language,
+// selalu diakhiri bahasa default sebagai fallback.
+func requestedLocales(c *fiber.Ctx) []string {
+	var locales []string
+	if lang := utils.NormalizeLocale(c.Query("lang")); lang != "" {
+		locales = append(locales, lang)
+	}
+	locales = append(locales, utils.ParseAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))...)
+
+	defaultLocale := utils.DefaultLocale()
+	result := make([]string, 0, len(locales)+1)
+	for _, locale := range locales {
+		if locale == defaultLocale {
+			break
+		}
+		if !containsString(result, locale) {
+			result = append(result, locale)
+		}
+	}
+	return append(result, defaultLocale)
+}
+
+// localizeEvents mengganti teks event dan ticket category dengan terjemahan terbaik untuk request.
+// Event tanpa terjemahan yang cocok tetap memakai teks default.
+func localizeEvents(db *gorm.DB, c *fiber.Ctx, events []models.Event) error {
+	locales := requestedLocales(c)
+	defaultLocale := locales[len(locales)-1]
+	c.Vary(fiber.HeaderAcceptLanguage)
+	c.Set(fiber.HeaderContentLanguage, locales[0])
+
+	translated := locales[:len(locales)-1]
+	if len(events) == 0 || len(translated) == 0 {
+		for i := range events {
+			setDefaultLocale(&events[i], defaultLocale)
+		}
+		return nil
+	}
+
+	eventIDs := make([]string, 0, len(events))
+	var categoryIDs []string
+	for _, event := range events {
+		eventIDs = append(eventIDs, event.EventID)
+		for _, tc := range event.TicketCategories {
+			categoryIDs = append(categoryIDs, tc.TicketCategoryID)
+		}
+	}
+
+	var eventTranslations []models.EventTranslation
+	if err := db.Where("event_id IN ? AND locale IN ?", eventIDs, translated).Find(&eventTranslations).Error; err != nil {
+		return err
+	}
+	byEvent := make(map[string]models.EventTranslation)
+	for _, t := range eventTranslations {
+		byEvent[t.EventID+"|"+t.Locale] = t
+	}
+
+	byCategory := make(map[string]models.TicketCategoryTranslation)
+	if len(categoryIDs) > 0 {
+		var categoryTranslations []models.TicketCategoryTranslation
+		if err := db.Where("ticket_category_id IN ? AND locale IN ?", categoryIDs, translated).Find(&categoryTranslations).Error; err != nil {
+			return err
+		}
+		for _, t := range categoryTranslations {
+			byCategory[t.TicketCategoryID+"|"+t.Locale] = t
+		}
+	}
+
+	for i := range events {
+		event := &events[i]
+		setDefaultLocale(event, defaultLocale)
+		for _, locale := range translated {
+			if t, ok := byEvent[event.EventID+"|"+locale]; ok {
+				event.Name = firstNonEmpty(t.Name, event.Name)
+				event.Description = firstNonEmpty(t.Description, event.Description)
+				event.Rules = firstNonEmpty(t.Rules, event.Rules)
+				event.Locale = locale
+				break
+			}
+		}
+		for j := range event.TicketCategories {
+			tc := &event.TicketCategories[j]
+			for _, locale := range translated {
+				if t, ok := byCategory[tc.TicketCategoryID+"|"+locale]; ok {
+					tc.Name = firstNonEmpty(t.Name, tc.Name)
+					tc.Description = firstNonEmpty(t.Description, tc.Description)
+					tc.Locale = locale
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func setDefaultLocale(event *models.Event, locale string) {
+	event.Locale = locale
+	for j := range event.TicketCategories {
+		event.TicketCategories[j].Locale = locale
+	}
+}
+
+// localizeEvent adalah localizeEvents untuk satu event
+func localizeEvent(db *gorm.DB, c *fiber.Ctx, event *models.Event) error {
+	events := []models.Event{*event}
+	if err := localizeEvents(db, c, events); err != nil {
+		return err
+	}
+	*event = events[0]
+	return nil
+}
+
+// loadTranslatableEvent memuat event beserta ticket category dan mengecek izin organizer
+func loadTranslatableEvent(c *fiber.Ctx, perm string) (models.Event, int, string) {
+	user := c.Locals("user").(models.User)
+
+	var event models.Event
+	if err := config.DB.Preload("TicketCategories").Where("event_id = ?", c.Params("id")).First(&event).Error; err != nil {
+		return event, fiber.StatusNotFound, "Event not found"
+	}
+	if !canAccessEvent(config.DB, user, event, perm) {
+		return event, fiber.StatusForbidden, "Not authorized to manage translations for this event"
+	}
+	return event, 0, ""
+}
+
+// GetEventTranslations - Semua terjemahan event dan ticket category-nya untuk organizer
+func GetEventTranslations(c *fiber.Ctx) error {
+	event, status, message := loadTranslatableEvent(c, permEventView)
+	if status != 0 {
+		return c.Status(status).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+
+	var eventTranslations []models.EventTranslation
+	if err := config.DB.Where("event_id = ?", event.EventID).Order("locale ASC").Find(&eventTranslations).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch translations",
+		})
+	}
+	var categoryTranslations []models.TicketCategoryTranslation
+	if err := config.DB.Where("event_id = ?", event.EventID).Order("locale ASC").Find(&categoryTranslations).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch translations",
+		})
+	}
+
+	translations := make([]fiber.Map, 0, len(eventTranslations))
+	for _, t := range eventTranslations {
+		categories := make([]models.TicketCategoryTranslation, 0)
+		for _, ct := range categoryTranslations {
+			if ct.Locale == t.Locale {
+				categories = append(categories, ct)
+			}
+		}
+		translations = append(translations, fiber.Map{
+			"locale":            t.Locale,
+			"name":              t.Name,
+			"description":       t.Description,
+			"rules":             t.Rules,
+			"ticket_categories": categories,
+			"updated_by":        t.UpdatedBy,
+			"updated_at":        t.UpdatedAt,
+		})
+	}
+
+	defaultCategories := make([]TicketCategoryTranslationRequest, 0, len(event.TicketCategories))
+	for _, tc := range event.TicketCategories {
+		defaultCategories = append(defaultCategories, TicketCategoryTranslationRequest{
+			TicketCategoryID: tc.TicketCategoryID,
+			Name:             tc.Name,
+			Description:      tc.Description,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":        "Translations retrieved successfully",
+		"default_locale": utils.DefaultLocale(),
+		"default": fiber.Map{
+			"name":              event.Name,
+			"description":       event.Description,
+			"rules":             event.Rules,
+			"ticket_categories": defaultCategories,
+		},
+		"translations": translations,
+	})
+}
+
+// UpsertEventTranslation - Membuat atau mengganti terjemahan satu bahasa. Teks bahasa default
+// tetap diubah lewat update event biasa.
+func UpsertEventTranslation(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	event, status, message := loadTranslatableEvent(c, permEventManage)
+	if status != 0 {
+		return c.Status(status).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+
+	locale := utils.NormalizeLocale(c.Params("locale"))
+	if locale == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid locale, use a language code such as en or id",
+		})
+	}
+	if locale == utils.DefaultLocale() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Default locale text is edited through the event itself",
+		})
+	}
+
+	var req EventTranslationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" && req.Description == "" && req.Rules == "" && len(req.TicketCategories) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "At least one translated field is required",
+		})
+	}
+	if len(req.Name) > 100 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "name must be at most 100 characters",
+		})
+	}
+
+	eventCategories := make(map[string]bool)
+	for _, tc := range event.TicketCategories {
+		eventCategories[tc.TicketCategoryID] = true
+	}
+	for _, tc := range req.TicketCategories {
+		if !eventCategories[tc.TicketCategoryID] {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Ticket category " + tc.TicketCategoryID + " does not belong to this event",
+			})
+		}
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	translation := models.EventTranslation{
+		EventID:     event.EventID,
+		Locale:      locale,
+		Name:        req.Name,
+		Description: req.Description,
+		Rules:       req.Rules,
+		UpdatedBy:   user.UserID,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "description", "rules", "updated_by", "updated_at"}),
+	}).Create(&translation).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save translation",
+		})
+	}
+
+	// Daftar ticket category menggantikan terjemahan category sebelumnya untuk bahasa ini
+	if err := tx.Where("event_id = ? AND locale = ?", event.EventID, locale).Delete(&models.TicketCategoryTranslation{}).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save ticket category translations",
+		})
+	}
+	for _, tc := range req.TicketCategories {
+		if strings.TrimSpace(tc.Name) == "" && tc.Description == "" {
+			continue
+		}
+		if err := tx.Create(&models.TicketCategoryTranslation{
+			TicketCategoryID: tc.TicketCategoryID,
+			Locale:           locale,
+			EventID:          event.EventID,
+			Name:             strings.TrimSpace(tc.Name),
+			Description:      tc.Description,
+			CreatedAt:        time.Now(),
+			UpdatedAt:        time.Now(),
+		}).Error; err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to save ticket category translations",
+			})
+		}
+	}
+
+	if err := recordAudit(tx, c, user.UserID, "event.translation_update", "event", event.EventID, fiber.Map{
+		"locale": locale,
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":     "Translation saved successfully",
+		"translation": translation,
+	})
+}
+
+// DeleteEventTranslation - Menghapus terjemahan satu bahasa beserta terjemahan ticket category-nya
+func DeleteEventTranslation(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+	event, status, message := loadTranslatableEvent(c, permEventManage)
+	if status != 0 {
+		return c.Status(status).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+
+	locale := utils.NormalizeLocale(c.Params("locale"))
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	result := tx.Where("event_id = ? AND locale = ?", event.EventID, locale).Delete(&models.EventTranslation{})
+	if result.Error != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete translation",
+		})
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Translation not found",
+		})
+	}
+	if err := tx.Where("event_id = ? AND locale = ?", event.EventID, locale).Delete(&models.TicketCategoryTranslation{}).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete translation",
+		})
+	}
+
+	if err := recordAudit(tx, c, user.UserID, "event.translation_delete", "event", event.EventID, fiber.Map{
+		"locale": locale,
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Translation deleted successfully",
+	})
+}
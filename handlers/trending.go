@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	jobTypeTrending = "trending_recompute"
+	trendingJobKey  = "trending_recompute"
+
+	// Bobot per interaksi sebelum peluruhan; penjualan tiket paling kuat, view paling lemah
+	trendingLikeWeight = 1.0
+	trendingSaleWeight = 3.0
+	trendingViewWeight = 0.1
+
+	defaultTrendingWindowDays      = 7
+	defaultTrendingIntervalMinutes = 15
+	defaultPopularEventsLimit      = 6
+	maxPopularEventsLimit          = 50
+
+	defaultViewDedupeMinutes = 30
+	eventViewFlushInterval   = 30 * time.Second
+)
+
+// eventViewKey mengelompokkan view tertunda per event per hari (UTC)
+type eventViewKey struct {
+	EventID string
+	Day     time.Time
+}
+
+// eventViews menampung view yang belum ditulis ke database. View dari viewer yang sama
+// (user atau IP) untuk event yang sama hanya dihitung sekali per jendela dedupe.
+var eventViews = struct {
+	mu      sync.Mutex
+	seen    map[string]time.Time // eventID|viewer -> kedaluwarsa dedupe
+	pending map[eventViewKey]uint
+}{
+	seen:    map[string]time.Time{},
+	pending: map[eventViewKey]uint{},
+}
+
+func init() {
+	RegisterJobHandler(jobTypeTrending, handleTrendingJob)
+}
+
+// trendingWindow adalah rentang interaksi yang dihitung (TRENDING_WINDOW_DAYS, default 7 hari)
+func trendingWindow() time.Duration {
+	days := defaultTrendingWindowDays
+	if v, err := strconv.Atoi(os.Getenv("TRENDING_WINDOW_DAYS")); err == nil && v > 0 {
+		days = v
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// trendingInterval adalah jeda antar perhitungan ulang (TRENDING_INTERVAL_MINUTES, default 15 menit)
+func trendingInterval() time.Duration {
+	minutes := defaultTrendingIntervalMinutes
+	if v, err := strconv.Atoi(os.Getenv("TRENDING_INTERVAL_MINUTES")); err == nil && v > 0 {
+		minutes = v
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// viewDedupeWindow adalah jeda sebelum viewer yang sama dihitung lagi (EVENT_VIEW_DEDUPE_MINUTES, default 30)
+func viewDedupeWindow() time.Duration {
+	minutes := defaultViewDedupeMinutes
+	if v, err := strconv.Atoi(os.Getenv("EVENT_VIEW_DEDUPE_MINUTES")); err == nil && v > 0 {
+		minutes = v
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// recordEventView mencatat view detail event di memori. Viewer dikenali dari user yang login,
+// atau IP untuk pengunjung anonim. Penulisan ke database dilakukan oleh StartEventViewFlusher.
+func recordEventView(c *fiber.Ctx, eventID string) {
+	viewer := "ip:" + c.IP()
+	if user, ok := c.Locals("user").(models.User); ok {
+		viewer = "user:" + user.UserID
+	}
+
+	now := time.Now()
+	key := eventID + "|" + viewer
+
+	eventViews.mu.Lock()
+	defer eventViews.mu.Unlock()
+	if expires, ok := eventViews.seen[key]; ok && now.Before(expires) {
+		return
+	}
+	eventViews.seen[key] = now.Add(viewDedupeWindow())
+	eventViews.pending[eventViewKey{EventID: eventID, Day: now.UTC().Truncate(24 * time.Hour)}]++
+}
+
+// StartEventViewFlusher menulis view yang tertunda ke event_view_dailies secara berkala
+// dan membuang entri dedupe yang sudah kedaluwarsa.
+func StartEventViewFlusher(db *gorm.DB) {
+	go func() {
+		ticker := time.NewTicker(eventViewFlushInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			flushEventViews(db)
+		}
+	}()
+}
+
+func flushEventViews(db *gorm.DB) {
+	now := time.Now()
+
+	eventViews.mu.Lock()
+	pending := eventViews.pending
+	eventViews.pending = map[eventViewKey]uint{}
+	for key, expires := range eventViews.seen {
+		if !now.Before(expires) {
+			delete(eventViews.seen, key)
+		}
+	}
+	eventViews.mu.Unlock()
+
+	for key, views := range pending {
+		view := models.EventViewDaily{
+			EventID: key.EventID,
+			Day:     key.Day,
+			Views:   views,
+		}
+		// Best effort, kegagalan hanya di-log
+		if err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "event_id"}, {Name: "day"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"views": gorm.Expr("views + ?", views)}),
+		}).Create(&view).Error; err != nil {
+			log.Printf("Failed to record %d views for event %s: %v", views, key.EventID, err)
+		}
+	}
+}
+
+// InitTrendingJob memastikan job perhitungan trending terjadwal, termasuk menghidupkan
+// kembali job yang sempat gagal permanen.
+func InitTrendingJob(db *gorm.DB) error {
+	if err := EnsureJob(db, jobTypeTrending, trendingJobKey, time.Now(), nil); err != nil {
+		return err
+	}
+	return db.Model(&models.ScheduledJob{}).
+		Where("job_key = ? AND status IN ?", trendingJobKey, []string{"done", "failed", "cancelled"}).
+		Updates(map[string]interface{}{
+			"status":     "pending",
+			"run_at":     time.Now().UTC(),
+			"attempts":   0,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func handleTrendingJob(db *gorm.DB, job models.ScheduledJob) error {
+	if err := recomputeTrendingScores(db); err != nil {
+		return err
+	}
+	return ScheduleJob(db, jobTypeTrending, trendingJobKey, time.Now().Add(trendingInterval()), nil)
+}
+
+// recomputeTrendingScores menghitung skor trending event live: like, penjualan tiket, dan view
+// dalam rolling window, masing-masing meluruh per hari dengan half-life setengah window.
+func recomputeTrendingScores(db *gorm.DB) error {
+	now := time.Now().UTC()
+	window := trendingWindow()
+	since := now.Add(-window)
+	halfLifeDays := window.Hours() / 24 / 2
+
+	type dailyCount struct {
+		EventID string
+		Day     time.Time
+		Total   float64
+	}
+	decay := func(day time.Time) float64 {
+		ageDays := math.Max(now.Sub(day).Hours()/24, 0)
+		return math.Pow(0.5, ageDays/halfLifeDays)
+	}
+
+	var eventIDs []string
+	if err := db.Model(&models.Event{}).
+		Where("status IN ? AND date_end >= ?", liveEventStatuses, now).
+		Pluck("event_id", &eventIDs).Error; err != nil {
+		return err
+	}
+
+	scores := make(map[string]float64, len(eventIDs))
+	if len(eventIDs) > 0 {
+		var likes []dailyCount
+		if err := db.Model(&models.EventLike{}).
+			Select("event_id, DATE(created_at) AS day, COUNT(*) AS total").
+			Where("event_id IN ? AND created_at >= ?", eventIDs, since).
+			Group("event_id, DATE(created_at)").
+			Scan(&likes).Error; err != nil {
+			return err
+		}
+		var sales []dailyCount
+		if err := db.Model(&models.Ticket{}).
+			Select("event_id, DATE(created_at) AS day, COUNT(*) AS total").
+			Where("event_id IN ? AND created_at >= ? AND status IN ? AND is_complimentary = ?", eventIDs, since, purchasedTicketStatuses, false).
+			Group("event_id, DATE(created_at)").
+			Scan(&sales).Error; err != nil {
+			return err
+		}
+		var views []dailyCount
+		if err := db.Model(&models.EventViewDaily{}).
+			Select("event_id, day, views AS total").
+			Where("event_id IN ? AND day >= ?", eventIDs, since.Truncate(24*time.Hour)).
+			Scan(&views).Error; err != nil {
+			return err
+		}
+
+		for _, row := range likes {
+			scores[row.EventID] += trendingLikeWeight * row.Total * decay(row.Day)
+		}
+		for _, row := range sales {
+			scores[row.EventID] += trendingSaleWeight * row.Total * decay(row.Day)
+		}
+		for _, row := range views {
+			scores[row.EventID] += trendingViewWeight * row.Total * decay(row.Day)
+		}
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// Event yang tidak lagi live atau tanpa interaksi di window kembali ke nol
+		reset := tx.Model(&models.Event{}).Where("trending_score <> ?", 0)
+		if len(scores) > 0 {
+			ids := make([]string, 0, len(scores))
+			for eventID := range scores {
+				ids = append(ids, eventID)
+			}
+			reset = reset.Where("event_id NOT IN ?", ids)
+		}
+		if err := reset.UpdateColumn("trending_score", 0).Error; err != nil {
+			return err
+		}
+
+		for eventID, score := range scores {
+			if err := tx.Model(&models.Event{}).
+				Where("event_id = ?", eventID).
+				UpdateColumn("trending_score", math.Round(score*1000)/1000).Error; err != nil {
+				return err
+			}
+		}
+
+		// Data view di luar window tidak dipakai lagi
+		return tx.Where("day < ?", since.Add(-24*time.Hour)).Delete(&models.EventViewDaily{}).Error
+	})
+}
+
+// GetEventsPopular - Event live dengan skor trending tertinggi.
+// Filter: category / child_category (ID atau nama), category_id, child_category_id, district.
+// ?limit= (default 6, max 50)
+func GetEventsPopular(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", strconv.Itoa(defaultPopularEventsLimit)))
+	if err != nil || limit < 1 || limit > maxPopularEventsLimit {
+		limit = defaultPopularEventsLimit
+	}
+
+	query := config.DB.Preload("Owner").Preload("TicketCategories", publicTicketCategories).
+		Where("status IN ? AND date_end >= ?", liveEventStatuses, time.Now())
+	// Filter kategori menerima ID maupun nama, sama seperti listing event
+	if value := c.Query("category"); value != "" {
+		query = query.Where("(category_id = ? OR category = ?)", value, value)
+	}
+	if value := c.Query("child_category"); value != "" {
+		query = query.Where("(child_category_id = ? OR child_category = ?)", value, value)
+	}
+	for param, column := range map[string]string{
+		"category_id":       "category_id",
+		"child_category_id": "child_category_id",
+		"district":          "district",
+	} {
+		if value := c.Query(param); value != "" {
+			query = query.Where(column+" = ?", value)
+		}
+	}
+
+	var events []models.Event
+	if err := query.Order("trending_score DESC").
+		Order("total_likes DESC").
+		Order("date_start ASC").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch events",
+		})
+	}
+	if err := localizeEvents(config.DB, c, events); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch events",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"events": events,
+	})
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Tsaniii18/Ticketing-Backend/config"
@@ -124,6 +125,7 @@ func GetUserByID(c *fiber.Ctx) error {
 }
 
 func VerifyUser(c *fiber.Ctx) error {
+	admin := c.Locals("user").(models.User)
 	userID := c.Params("id")
 
 	var user models.User
@@ -141,8 +143,9 @@ func VerifyUser(c *fiber.Ctx) error {
 	}
 
 	var req struct {
-		Status  string `json:"status"`
-		Comment string `json:"comment,omitempty"`
+		Status     string `json:"status"`
+		Comment    string `json:"comment,omitempty"`
+		ReasonCode string `json:"reason_code,omitempty"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -151,15 +154,51 @@ func VerifyUser(c *fiber.Ctx) error {
 		})
 	}
 
-	user.RegisterStatus = req.Status
-	user.RegisterComment = req.Comment
+	// Client lama belum mengirim reason_code, anggap "other" dengan comment sebagai alasan
+	if req.Status == "rejected" && req.ReasonCode == "" {
+		req.ReasonCode = "other"
+	}
+	status, err := validateModerationDecision(req.Status, req.ReasonCode, req.Comment)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	item, err := lockOpenModerationItem(tx, moderationEntityOrganizer, user.UserID, user.UserID)
+	if err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load moderation item",
+		})
+	}
 
-	if err := config.DB.Save(&user).Error; err != nil {
+	if err := decideModeration(tx, c, item, admin.UserID, status, req.ReasonCode, req.Comment); err != nil {
+		tx.Rollback()
+		if errors.Is(err, errModerationAssigned) {
+			return moderationAssignedResponse(c, item)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to verify user",
 		})
 	}
 
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	notifyModerationResult(*item)
+	config.DB.First(&user, "user_id = ?", user.UserID)
+
 	return c.JSON(fiber.Map{
 		"message": "Organizer verification updated",
 		"user": fiber.Map{
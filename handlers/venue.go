@@ -0,0 +1,964 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Tsaniii18/Ticketing-Backend/config"
+	"github.com/Tsaniii18/Ticketing-Backend/models"
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultVenuePageSize = 20
+	maxVenuePageSize     = 50
+	maxVenueFacilities   = 30
+
+	// Kelompok venue dengan koordinat sedekat ini dianggap tempat yang sama jika namanya mirip
+	venueClusterRadiusKm = 0.15
+)
+
+// genericVenueWords diabaikan saat normalisasi nama supaya "Stadion GBK" dan "GBK Stadium" sama
+var genericVenueWords = map[string]bool{
+	"the": true, "gedung": true, "gd": true, "stadion": true, "stadium": true,
+	"lapangan": true, "venue": true, "di": true, "at": true,
+}
+
+type VenueRequest struct {
+	Name       string   `json:"name"`
+	Address    string   `json:"address"`
+	District   string   `json:"district"`
+	Latitude   *float64 `json:"latitude"`
+	Longitude  *float64 `json:"longitude"`
+	Capacity   uint     `json:"capacity"`
+	Facilities []string `json:"facilities"`
+}
+
+type VenueMergeRequest struct {
+	TargetVenueID string `json:"target_venue_id"`
+}
+
+// venueNameTokens memecah nama venue menjadi token ternormalisasi tanpa kata generik
+func venueNameTokens(name string) []string {
+	var tokens []string
+	for _, token := range strings.Split(utils.Slugify(name), "-") {
+		if token != "" && !genericVenueWords[token] {
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) == 0 && utils.Slugify(name) != "" {
+		tokens = strings.Split(utils.Slugify(name), "-")
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
+// normalizeVenueKey adalah kunci deduplikasi: token nama yang diurutkan ditambah district
+func normalizeVenueKey(name, district string) string {
+	return strings.Join(venueNameTokens(name), "-") + "@" + utils.Slugify(district)
+}
+
+// venueNamesSimilar mengecek apakah dua nama berbagi token atau salah satunya singkatan
+// dari yang lain (misal "GBK" dan "Gelora Bung Karno")
+func venueNamesSimilar(a, b string) bool {
+	tokensA, tokensB := venueNameTokens(a), venueNameTokens(b)
+	for _, ta := range tokensA {
+		if containsString(tokensB, ta) {
+			return true
+		}
+	}
+	acronym := func(name string) string {
+		var b strings.Builder
+		for _, token := range strings.Split(utils.Slugify(name), "-") {
+			if token != "" {
+				b.WriteByte(token[0])
+			}
+		}
+		return b.String()
+	}
+	acronymA, acronymB := acronym(a), acronym(b)
+	return (len(acronymA) > 1 && containsString(tokensB, acronymA)) ||
+		(len(acronymB) > 1 && containsString(tokensA, acronymB))
+}
+
+// validateVenueRequest merapikan input venue dan mengisi koordinat lewat geocoding jika kosong
+func validateVenueRequest(req *VenueRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Address = strings.TrimSpace(req.Address)
+	req.District = strings.TrimSpace(req.District)
+	if req.Name == "" || req.Address == "" || req.District == "" {
+		return errors.New("name, address and district are required")
+	}
+	if len(req.Name) > 100 || len(req.District) > 100 || len(req.Address) > 255 {
+		return errors.New("name and district must be at most 100 characters, address at most 255")
+	}
+
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return errors.New("latitude and longitude must be provided together")
+	}
+	if req.Latitude != nil {
+		if *req.Latitude < -90 || *req.Latitude > 90 || *req.Longitude < -180 || *req.Longitude > 180 {
+			return errors.New("coordinates are out of range")
+		}
+	} else {
+		req.Latitude, req.Longitude = geocodeAddress(req.Name, req.Address, req.District)
+	}
+
+	facilities := make([]string, 0, len(req.Facilities))
+	for _, facility := range req.Facilities {
+		facility = strings.ToLower(strings.TrimSpace(facility))
+		if facility == "" || containsString(facilities, facility) {
+			continue
+		}
+		if len(facility) > 50 {
+			return errors.New("each facility must be at most 50 characters")
+		}
+		facilities = append(facilities, facility)
+	}
+	if len(facilities) > maxVenueFacilities {
+		return fmt.Errorf("a venue can list at most %d facilities", maxVenueFacilities)
+	}
+	req.Facilities = facilities
+	return nil
+}
+
+// findDuplicateVenue mencari venue approved/pending dengan kunci nama yang sama
+func findDuplicateVenue(db *gorm.DB, key, excludeID string) (*models.Venue, error) {
+	var venue models.Venue
+	query := db.Where("normalized_key = ? AND status IN ?", key, []string{"approved", "pending"})
+	if excludeID != "" {
+		query = query.Where("venue_id <> ?", excludeID)
+	}
+	err := query.Order("status ASC").First(&venue).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &venue, nil
+}
+
+// resolveEventVenue memuat venue yang dipilih organizer untuk event. Venue approved bisa dipakai
+// siapa saja; venue pending hanya oleh pengusulnya.
+func resolveEventVenue(db *gorm.DB, user models.User, venueID string) (models.Venue, int, error) {
+	var venue models.Venue
+	if err := db.Where("venue_id = ?", venueID).First(&venue).Error; err != nil {
+		return venue, fiber.StatusBadRequest, errors.New("venue not found")
+	}
+	switch {
+	case venue.Status == "approved":
+	case venue.Status == "pending" && venue.ProposedBy != nil && *venue.ProposedBy == user.UserID:
+	default:
+		return venue, fiber.StatusBadRequest, errors.New("venue is not available in the directory")
+	}
+	return venue, 0, nil
+}
+
+const venueBackfillMigration = "venue_directory_backfill"
+
+// InitVenueDirectory mengelompokkan venue teks bebas dari event lama ke direktori venue.
+// Hanya berjalan sekali (ditandai di data_migrations); event baru dengan venue teks bebas
+// tidak otomatis membuat venue approved.
+func InitVenueDirectory(db *gorm.DB) error {
+	var done int64
+	if err := db.Model(&models.DataMigration{}).Where("name = ?", venueBackfillMigration).Count(&done).Error; err != nil {
+		return err
+	}
+	if done > 0 {
+		return nil
+	}
+	if err := backfillVenueDirectory(db); err != nil {
+		return err
+	}
+	return db.Create(&models.DataMigration{Name: venueBackfillMigration, AppliedAt: time.Now().UTC()}).Error
+}
+
+func backfillVenueDirectory(db *gorm.DB) error {
+	var events []models.Event
+	if err := db.Select("event_id", "venue", "location", "district", "latitude", "longitude").
+		Where("venue_id IS NULL AND venue <> ?", "").
+		Find(&events).Error; err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	clusters := clusterEventVenues(events)
+
+	var directory []models.Venue
+	if err := db.Where("status IN ?", []string{"approved", "pending"}).Find(&directory).Error; err != nil {
+		return err
+	}
+
+	created := 0
+	for _, cluster := range clusters {
+		venue := matchDirectoryVenue(directory, cluster)
+		if venue == nil {
+			newVenue := cluster.toVenue()
+			if err := db.Create(&newVenue).Error; err != nil {
+				return err
+			}
+			directory = append(directory, newVenue)
+			venue = &directory[len(directory)-1]
+			created++
+		}
+
+		if err := db.Model(&models.Event{}).
+			Where("event_id IN ?", cluster.eventIDs).
+			UpdateColumn("venue_id", venue.VenueID).Error; err != nil {
+			return err
+		}
+	}
+
+	log.Printf(" --  Venue directory: %d events linked, %d venues created from %d clusters", len(events), created, len(clusters))
+	return nil
+}
+
+type venueCluster struct {
+	key       string
+	district  string
+	names     map[string]int
+	addresses map[string]int
+	districts map[string]int
+	latSum    float64
+	lngSum    float64
+	coords    int
+	eventIDs  []string
+}
+
+func (vc *venueCluster) name() string         { return mostFrequent(vc.names) }
+func (vc *venueCluster) hasCoordinates() bool { return vc.coords > 0 }
+func (vc *venueCluster) centroid() (float64, float64) {
+	return vc.latSum / float64(vc.coords), vc.lngSum / float64(vc.coords)
+}
+
+func (vc *venueCluster) merge(other *venueCluster) {
+	for name, n := range other.names {
+		vc.names[name] += n
+	}
+	for address, n := range other.addresses {
+		vc.addresses[address] += n
+	}
+	for district, n := range other.districts {
+		vc.districts[district] += n
+	}
+	vc.latSum += other.latSum
+	vc.lngSum += other.lngSum
+	vc.coords += other.coords
+	vc.eventIDs = append(vc.eventIDs, other.eventIDs...)
+}
+
+// toVenue membentuk venue dari ejaan yang paling sering dipakai di cluster
+func (vc *venueCluster) toVenue() models.Venue {
+	name := vc.name()
+	district := mostFrequent(vc.districts)
+	venue := models.Venue{
+		VenueID:       utils.GenerateVenueID(),
+		Name:          name,
+		NormalizedKey: normalizeVenueKey(name, district),
+		Address:       mostFrequent(vc.addresses),
+		District:      district,
+		Facilities:    []string{},
+		Status:        "approved",
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	if vc.hasCoordinates() {
+		lat, lng := vc.centroid()
+		venue.Latitude, venue.Longitude = &lat, &lng
+	}
+	return venue
+}
+
+// mostFrequent memilih nilai dengan hitungan terbanyak; seri dipecah secara alfabetis
+// supaya hasil migrasi deterministik
+func mostFrequent(counts map[string]int) string {
+	best, bestCount := "", 0
+	for value, count := range counts {
+		if count > bestCount || (count == bestCount && value < best) {
+			best, bestCount = value, count
+		}
+	}
+	return best
+}
+
+// clusterEventVenues mengelompokkan event berdasarkan kunci nama ternormalisasi, lalu
+// menggabungkan kelompok di district yang sama yang berdekatan dan bernama mirip.
+func clusterEventVenues(events []models.Event) []*venueCluster {
+	byKey := make(map[string]*venueCluster)
+	var clusters []*venueCluster
+	for _, event := range events {
+		key := normalizeVenueKey(event.Venue, event.District)
+		cluster, ok := byKey[key]
+		if !ok {
+			cluster = &venueCluster{
+				key:       key,
+				district:  utils.Slugify(event.District),
+				names:     map[string]int{},
+				addresses: map[string]int{},
+				districts: map[string]int{},
+			}
+			byKey[key] = cluster
+			clusters = append(clusters, cluster)
+		}
+		cluster.names[strings.TrimSpace(event.Venue)]++
+		if address := strings.TrimSpace(event.Location); address != "" {
+			cluster.addresses[address]++
+		}
+		cluster.districts[strings.TrimSpace(event.District)]++
+		if event.Latitude != nil && event.Longitude != nil {
+			cluster.latSum += *event.Latitude
+			cluster.lngSum += *event.Longitude
+			cluster.coords++
+		}
+		cluster.eventIDs = append(cluster.eventIDs, event.EventID)
+	}
+
+	// Kelompok terbesar lebih dulu supaya menjadi induk penggabungan
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return len(clusters[i].eventIDs) > len(clusters[j].eventIDs)
+	})
+
+	var merged []*venueCluster
+	for _, cluster := range clusters {
+		var target *venueCluster
+		for _, candidate := range merged {
+			if clustersNearby(candidate, cluster) {
+				target = candidate
+				break
+			}
+		}
+		if target != nil {
+			target.merge(cluster)
+			continue
+		}
+		merged = append(merged, cluster)
+	}
+	return merged
+}
+
+func clustersNearby(a, b *venueCluster) bool {
+	if a.district != b.district || !a.hasCoordinates() || !b.hasCoordinates() {
+		return false
+	}
+	latA, lngA := a.centroid()
+	latB, lngB := b.centroid()
+	return haversineKm(latA, lngA, latB, lngB) <= venueClusterRadiusKm && venueNamesSimilar(a.name(), b.name())
+}
+
+// matchDirectoryVenue mencari venue direktori yang sama dengan cluster: kunci identik,
+// atau berdekatan di district yang sama dengan nama mirip
+func matchDirectoryVenue(directory []models.Venue, cluster *venueCluster) *models.Venue {
+	for i := range directory {
+		if directory[i].NormalizedKey == cluster.key {
+			return &directory[i]
+		}
+	}
+	if !cluster.hasCoordinates() {
+		return nil
+	}
+	lat, lng := cluster.centroid()
+	for i := range directory {
+		venue := &directory[i]
+		if venue.Latitude == nil || venue.Longitude == nil || utils.Slugify(venue.District) != cluster.district {
+			continue
+		}
+		if haversineKm(lat, lng, *venue.Latitude, *venue.Longitude) <= venueClusterRadiusKm && venueNamesSimilar(venue.Name, cluster.name()) {
+			return venue
+		}
+	}
+	return nil
+}
+
+// syncVenueEvents menyalin data venue ke event mendatang yang belum live (pending/rejected).
+// Event live tidak diubah diam-diam; perubahan lokasinya harus lewat change request organizer.
+func syncVenueEvents(tx *gorm.DB, venue models.Venue) error {
+	updateData := map[string]interface{}{
+		"venue":      venue.Name,
+		"location":   venue.Address,
+		"district":   venue.District,
+		"updated_at": time.Now(),
+	}
+	if venue.Latitude != nil && venue.Longitude != nil {
+		updateData["latitude"] = *venue.Latitude
+		updateData["longitude"] = *venue.Longitude
+	}
+	return tx.Model(&models.Event{}).
+		Where("venue_id = ? AND date_end >= ? AND status NOT IN ?", venue.VenueID, time.Now(),
+			append([]string{"ended", "cancelled"}, liveEventStatuses...)).
+		Updates(updateData).Error
+}
+
+// GetVenues - Direktori venue approved. Filter: q, district, min_capacity, facility. ?page=&limit=
+func GetVenues(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", defaultVenuePageSize)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > maxVenuePageSize {
+		limit = defaultVenuePageSize
+	}
+
+	query := config.DB.Model(&models.Venue{}).Where("status = ?", "approved")
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		like := "%" + strings.ToLower(q) + "%"
+		query = query.Where("(LOWER(name) LIKE ? OR LOWER(address) LIKE ?)", like, like)
+	}
+	if district := c.Query("district"); district != "" {
+		query = query.Where("district = ?", district)
+	}
+	if minCapacity := c.Query("min_capacity"); minCapacity != "" {
+		v, err := strconv.Atoi(minCapacity)
+		if err != nil || v < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid min_capacity",
+			})
+		}
+		query = query.Where("capacity >= ?", v)
+	}
+	if facility := strings.ToLower(strings.TrimSpace(c.Query("facility"))); facility != "" {
+		// Facilities disimpan sebagai array JSON, cocokkan elemen lengkap beserta tanda kutipnya
+		query = query.Where("facilities LIKE ?", `%"`+strings.NewReplacer("%", `\%`, "_", `\_`, `"`, "").Replace(facility)+`"%`)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch venues",
+		})
+	}
+
+	var venues []models.Venue
+	if err := query.Order("name ASC").Offset((page - 1) * limit).Limit(limit).Find(&venues).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch venues",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Venues retrieved successfully",
+		"venues":  venues,
+		"pagination": fiber.Map{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetVenue - Detail venue approved beserta event mendatang di venue tersebut
+func GetVenue(c *fiber.Ctx) error {
+	var venue models.Venue
+	if err := config.DB.Where("venue_id = ? AND status = ?", c.Params("id"), "approved").First(&venue).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Venue not found",
+		})
+	}
+
+	var events []models.Event
+	if err := config.DB.Preload("TicketCategories", publicTicketCategories).
+		Where("venue_id = ? AND status IN ? AND date_end >= ?", venue.VenueID, liveEventStatuses, time.Now()).
+		Order("date_start ASC").
+		Limit(20).
+		Find(&events).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch venue events",
+		})
+	}
+	if err := localizeEvents(config.DB, c, events); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch venue events",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"venue":           venue,
+		"upcoming_events": events,
+	})
+}
+
+// ProposeVenue - Organizer mengusulkan venue baru; masuk antrian moderasi admin.
+// Venue pending sudah bisa dipakai di event milik pengusulnya.
+func ProposeVenue(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req VenueRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if err := validateVenueRequest(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	key := normalizeVenueKey(req.Name, req.District)
+	duplicate, err := findDuplicateVenue(config.DB, key, "")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to check venue directory",
+		})
+	}
+	if duplicate != nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "This venue already exists in the directory",
+			"venue": duplicate,
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	venue := models.Venue{
+		VenueID:       utils.GenerateVenueID(),
+		Name:          req.Name,
+		NormalizedKey: key,
+		Address:       req.Address,
+		District:      req.District,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		Capacity:      req.Capacity,
+		Facilities:    req.Facilities,
+		Status:        "pending",
+		ProposedBy:    &user.UserID,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	if err := tx.Create(&venue).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to propose venue",
+		})
+	}
+
+	if err := enqueueModeration(tx, moderationEntityVenue, venue.VenueID, user.UserID); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to queue venue for review",
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Venue proposed successfully and is waiting for review",
+		"venue":   venue,
+	})
+}
+
+// GetMyVenueProposals - Venue yang diusulkan user beserta status kurasinya
+func GetMyVenueProposals(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var venues []models.Venue
+	if err := config.DB.Where("proposed_by = ?", user.UserID).Order("created_at DESC").Find(&venues).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch venue proposals",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"venues": venues,
+	})
+}
+
+// CreateVenue - Admin menambahkan venue langsung ke direktori
+func CreateVenue(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req VenueRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if err := validateVenueRequest(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	key := normalizeVenueKey(req.Name, req.District)
+	duplicate, err := findDuplicateVenue(config.DB, key, "")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to check venue directory",
+		})
+	}
+	if duplicate != nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "This venue already exists in the directory",
+			"venue": duplicate,
+		})
+	}
+
+	venue := models.Venue{
+		VenueID:       utils.GenerateVenueID(),
+		Name:          req.Name,
+		NormalizedKey: key,
+		Address:       req.Address,
+		District:      req.District,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		Capacity:      req.Capacity,
+		Facilities:    req.Facilities,
+		Status:        "approved",
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	if err := tx.Create(&venue).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create venue",
+		})
+	}
+	if err := recordAudit(tx, c, user.UserID, "venue.create", "venue", venue.VenueID, req); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Venue created successfully",
+		"venue":   venue,
+	})
+}
+
+// UpdateVenue - Admin mengubah data venue. Event mendatang di venue ini ikut diperbarui.
+func UpdateVenue(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req VenueRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+	if err := validateVenueRequest(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var venue models.Venue
+	if err := config.DB.Where("venue_id = ?", c.Params("id")).First(&venue).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Venue not found",
+		})
+	}
+
+	key := normalizeVenueKey(req.Name, req.District)
+	duplicate, err := findDuplicateVenue(config.DB, key, venue.VenueID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to check venue directory",
+		})
+	}
+	if duplicate != nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Another venue with this name already exists. Merge the venues instead",
+			"venue": duplicate,
+		})
+	}
+
+	venue.Name = req.Name
+	venue.NormalizedKey = key
+	venue.Address = req.Address
+	venue.District = req.District
+	venue.Latitude = req.Latitude
+	venue.Longitude = req.Longitude
+	venue.Capacity = req.Capacity
+	venue.Facilities = req.Facilities
+	venue.UpdatedAt = time.Now()
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	if err := tx.Save(&venue).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update venue",
+		})
+	}
+	if err := syncVenueEvents(tx, venue); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update events at this venue",
+		})
+	}
+	if err := recordAudit(tx, c, user.UserID, "venue.update", "venue", venue.VenueID, req); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Venue updated successfully",
+		"venue":   venue,
+	})
+}
+
+// MergeVenue - Admin menggabungkan venue duplikat ke venue target. Semua event dipindahkan
+// ke target dan venue sumber dihapus.
+func MergeVenue(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var req VenueMergeRequest
+	if err := c.BodyParser(&req); err != nil || req.TargetVenueID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "target_venue_id is required",
+		})
+	}
+	if req.TargetVenueID == c.Params("id") {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "A venue cannot be merged into itself",
+		})
+	}
+
+	var source, target models.Venue
+	if err := config.DB.Where("venue_id = ?", c.Params("id")).First(&source).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Venue not found",
+		})
+	}
+	if err := config.DB.Where("venue_id = ? AND status = ?", req.TargetVenueID, "approved").First(&target).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Target venue not found or not approved",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+
+	moved := tx.Model(&models.Event{}).Where("venue_id = ?", source.VenueID).UpdateColumn("venue_id", target.VenueID)
+	if moved.Error != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to move events to the target venue",
+		})
+	}
+	if err := syncVenueEvents(tx, target); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update events at the target venue",
+		})
+	}
+	if err := withdrawModeration(tx, moderationEntityVenue, source.VenueID, user.UserID); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to close venue review",
+		})
+	}
+	if err := tx.Delete(&source).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete merged venue",
+		})
+	}
+	if err := recordAudit(tx, c, user.UserID, "venue.merge", "venue", target.VenueID, fiber.Map{
+		"merged_venue_id": source.VenueID,
+		"merged_name":     source.Name,
+		"events_moved":    moved.RowsAffected,
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	if source.SeatMapPublicID != "" && source.SeatMapPublicID != target.SeatMapPublicID {
+		if err := config.DeleteImage(context.Background(), source.SeatMapPublicID); err != nil {
+			log.Printf("Failed to clean up seat map %s: %v", source.SeatMapPublicID, err)
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message":      "Venues merged successfully",
+		"venue":        target,
+		"events_moved": moved.RowsAffected,
+	})
+}
+
+// DeleteVenue - Admin menghapus venue yang belum dipakai event mana pun
+func DeleteVenue(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var venue models.Venue
+	if err := config.DB.Where("venue_id = ?", c.Params("id")).First(&venue).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Venue not found",
+		})
+	}
+
+	var eventCount int64
+	config.DB.Model(&models.Event{}).Where("venue_id = ?", venue.VenueID).Count(&eventCount)
+	if eventCount > 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Venue is used by events. Merge it into another venue instead",
+		})
+	}
+
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	if err := withdrawModeration(tx, moderationEntityVenue, venue.VenueID, user.UserID); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to close venue review",
+		})
+	}
+	if err := tx.Delete(&venue).Error; err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete venue",
+		})
+	}
+	if err := recordAudit(tx, c, user.UserID, "venue.delete", "venue", venue.VenueID, fiber.Map{
+		"name": venue.Name,
+	}); err != nil {
+		tx.Rollback()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record audit log",
+		})
+	}
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to commit transaction",
+		})
+	}
+
+	if venue.SeatMapPublicID != "" {
+		if err := config.DeleteImage(context.Background(), venue.SeatMapPublicID); err != nil {
+			log.Printf("Failed to clean up seat map %s: %v", venue.SeatMapPublicID, err)
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Venue deleted successfully",
+	})
+}
+
+// UploadVenueSeatMap - Mengunggah denah kursi (form file seat_map). Admin untuk semua venue,
+// pengusul untuk venue usulannya yang masih pending.
+func UploadVenueSeatMap(c *fiber.Ctx) error {
+	user := c.Locals("user").(models.User)
+
+	var venue models.Venue
+	if err := config.DB.Where("venue_id = ?", c.Params("id")).First(&venue).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Venue not found",
+		})
+	}
+	isProposer := venue.Status == "pending" && venue.ProposedBy != nil && *venue.ProposedBy == user.UserID
+	if user.Role != "admin" && !isProposer {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Not authorized to update this venue",
+		})
+	}
+
+	file, err := c.FormFile("seat_map")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "seat_map file is required",
+		})
+	}
+	if err := validateImageUpload(file); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	fileHeader, err := file.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to open file",
+		})
+	}
+	defer fileHeader.Close()
+
+	url, publicID, err := config.UploadImageWithPublicID(context.Background(), fileHeader, "ticketing-app/venues/"+venue.VenueID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to upload seat map",
+		})
+	}
+
+	oldPublicID := venue.SeatMapPublicID
+	if err := config.DB.Model(&venue).Updates(map[string]interface{}{
+		"seat_map_url":       url,
+		"seat_map_public_id": publicID,
+		"updated_at":         time.Now(),
+	}).Error; err != nil {
+		config.DeleteImage(context.Background(), publicID)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save seat map",
+		})
+	}
+	if oldPublicID != "" {
+		if err := config.DeleteImage(context.Background(), oldPublicID); err != nil {
+			log.Printf("Failed to clean up seat map %s: %v", oldPublicID, err)
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Seat map uploaded successfully",
+		"venue":   venue,
+	})
+}
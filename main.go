@@ -1,8 +1,10 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/Tsaniii18/Ticketing-Backend/config"
 	"github.com/Tsaniii18/Ticketing-Backend/handlers"
@@ -21,6 +23,9 @@ func main() {
 	// Initialize Cloudinary
 	config.InitCloudinary()
 
+	// Initialize geocoding provider
+	config.InitGeocoder()
+
 	err := migrateDatabase(config.DB)
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
@@ -44,10 +49,37 @@ func main() {
 		log.Fatal("Failed to setup default category event:", err)
 	}
 
-	if err := handlers.InitialScheduleEventEnd(config.DB); err != nil {
-		log.Fatal("Failed to start event_auto_status goroutine:", err)
+	if err := handlers.InitEventCategoryReferences(config.DB); err != nil {
+		log.Fatal("Failed to link event categories:", err)
+	}
+
+	if err := handlers.InitOrganizations(config.DB); err != nil {
+		log.Fatal("Failed to migrate organizer accounts to organizations:", err)
+	}
+
+	if err := handlers.InitModerationQueue(config.DB); err != nil {
+		log.Fatal("Failed to backfill moderation queue:", err)
+	}
+
+	if err := handlers.InitEventLifecycleJobs(config.DB); err != nil {
+		log.Fatal("Failed to schedule event lifecycle jobs:", err)
+	}
+
+	if err := handlers.InitTrendingJob(config.DB); err != nil {
+		log.Fatal("Failed to schedule trending job:", err)
 	}
 
+	if err := handlers.InitVenueDirectory(config.DB); err != nil {
+		log.Fatal("Failed to cluster event venues into the venue directory:", err)
+	}
+
+	if err := handlers.InitEventSlugs(config.DB); err != nil {
+		log.Fatal("Failed to generate event slugs:", err)
+	}
+
+	handlers.StartScheduler(config.DB)
+	handlers.StartEventViewFlusher(config.DB)
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -68,16 +100,66 @@ func migrateDatabase(db *gorm.DB) error {
 		return err
 	}
 
+	err = db.AutoMigrate(&models.Organization{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.OrganizationMember{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.Venue{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.EventSeries{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.EventTemplate{})
+	if err != nil {
+		return err
+	}
+
 	err = db.AutoMigrate(&models.Event{})
 	if err != nil {
 		return err
 	}
 
+	err = db.AutoMigrate(&models.EventMedia{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.EventSlug{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.EventTranslation{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.TicketCategoryTranslation{})
+	if err != nil {
+		return err
+	}
+
 	err = db.AutoMigrate(&models.TicketCategory{})
 	if err != nil {
 		return err
 	}
 
+	err = db.AutoMigrate(&models.AccessCode{})
+	if err != nil {
+		return err
+	}
+
 	err = db.AutoMigrate(&models.Cart{})
 	if err != nil {
 		return err
@@ -93,16 +175,81 @@ func migrateDatabase(db *gorm.DB) error {
 		return err
 	}
 
+	err = db.AutoMigrate(&models.CompTicketClaim{})
+	if err != nil {
+		return err
+	}
+
 	err = db.AutoMigrate(&models.TransactionDetail{})
 	if err != nil {
 		return err
 	}
 
+	err = db.AutoMigrate(&models.EventAnnouncement{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.EventChangeRequest{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.EventChangeRequestItem{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.ScheduledJob{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.Refund{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.Notification{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.AuditLog{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.EventReview{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.ReviewReport{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.ModerationItem{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.ModerationAction{})
+	if err != nil {
+		return err
+	}
+
 	err = db.AutoMigrate(&models.Feedback{})
 	if err != nil {
 		return err
 	}
 
+	err = db.AutoMigrate(&models.EventViewDaily{})
+	if err != nil {
+		return err
+	}
+
 	err = db.AutoMigrate(&models.EventLike{})
 	if err != nil {
 		return err
@@ -118,8 +265,83 @@ func migrateDatabase(db *gorm.DB) error {
 		return err
 	}
 
+	err = db.AutoMigrate(&models.RegistrationQuestion{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.RegistrationAnswer{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&models.DataMigration{})
+	if err != nil {
+		return err
+	}
+
 	db.Exec("SET FOREIGN_KEY_CHECKS = 1")
 
+	err = migrateTimestampsToUTC(db)
+	if err != nil {
+		return err
+	}
+
 	log.Println("Database migrated successfully")
 	return nil
 }
+
+const utcTimestampsMigration = "datetime_columns_to_utc"
+
+// migrateTimestampsToUTC mengonversi sekali semua kolom DATETIME yang ditulis dengan loc=Local
+// (zona server lama, DB_LEGACY_TIME_ZONE atau zona lokal proses) menjadi UTC.
+// Harus berjalan sebelum ada data baru yang ditulis dalam UTC.
+func migrateTimestampsToUTC(db *gorm.DB) error {
+	var done int64
+	if err := db.Model(&models.DataMigration{}).Where("name = ?", utcTimestampsMigration).Count(&done).Error; err != nil {
+		return err
+	}
+	if done > 0 {
+		return nil
+	}
+
+	legacy := time.Local
+	if name := os.Getenv("DB_LEGACY_TIME_ZONE"); name != "" {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return fmt.Errorf("invalid DB_LEGACY_TIME_ZONE %q: %w", name, err)
+		}
+		legacy = loc
+	}
+
+	// Nama zona butuh tabel time zone MySQL; tanpa itu pakai offset tetap saat ini
+	fromZone := legacy.String()
+	var probe *time.Time
+	if err := db.Raw("SELECT CONVERT_TZ('2000-01-01 00:00:00', ?, '+00:00')", fromZone).Scan(&probe).Error; err != nil || probe == nil {
+		fromZone = time.Now().In(legacy).Format("-07:00")
+		log.Printf("MySQL time zone tables not loaded, converting timestamps from fixed offset %s", fromZone)
+	}
+
+	var columns []struct {
+		TableName  string
+		ColumnName string
+	}
+	if err := db.Raw("SELECT table_name AS table_name, column_name AS column_name FROM information_schema.columns"+
+		" WHERE table_schema = DATABASE() AND data_type = 'datetime' AND table_name <> ?",
+		"data_migrations").Scan(&columns).Error; err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if fromZone != "UTC" && fromZone != "+00:00" {
+			for _, column := range columns {
+				if err := tx.Exec(fmt.Sprintf("UPDATE `%s` SET `%s` = CONVERT_TZ(`%s`, ?, '+00:00') WHERE `%s` IS NOT NULL",
+					column.TableName, column.ColumnName, column.ColumnName, column.ColumnName), fromZone).Error; err != nil {
+					return err
+				}
+			}
+			log.Printf("Converted %d datetime columns from %s to UTC", len(columns), fromZone)
+		}
+		return tx.Create(&models.DataMigration{Name: utcTimestampsMigration, AppliedAt: time.Now().UTC()}).Error
+	})
+}
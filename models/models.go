@@ -2,6 +2,9 @@ package models
 
 import (
 	"time"
+
+	"github.com/Tsaniii18/Ticketing-Backend/utils"
+	"gorm.io/gorm"
 )
 
 type User struct {
@@ -32,35 +35,225 @@ type User struct {
 	LikedEvents          []Event              `gorm:"many2many:event_likes;foreignKey:UserID;joinForeignKey:user_id;references:EventID;joinReferences:event_id" json:"liked_events,omitempty"`
 }
 
+// Organization memiliki event, laporan, dan refund. User menjadi anggota lewat OrganizationMember.
+type Organization struct {
+	OrganizationID string    `gorm:"primaryKey;type:char(60)" json:"organization_id"`
+	Name           string    `gorm:"size:100" json:"name"`
+	Type           string    `gorm:"size:50" json:"type"`
+	Description    string    `gorm:"type:text" json:"description"`
+	RatingAverage  float64   `gorm:"type:decimal(3,2);default:0" json:"rating_average"` // rata-rata review seluruh event organisasi
+	RatingCount    uint      `gorm:"default:0" json:"rating_count"`
+	CreatedBy      string    `gorm:"type:char(60);not null" json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	// Relationships
+	Members []OrganizationMember `gorm:"foreignKey:OrganizationID" json:"members,omitempty"`
+}
+
+// OrganizationMember - Role: owner, manager, finance, viewer. Status: invited, active
+type OrganizationMember struct {
+	MemberID       string     `gorm:"primaryKey;type:char(60)" json:"member_id"`
+	OrganizationID string     `gorm:"type:char(60);not null;uniqueIndex:idx_org_member" json:"organization_id"`
+	UserID         string     `gorm:"type:char(60);not null;uniqueIndex:idx_org_member;index" json:"user_id"`
+	Role           string     `gorm:"size:20;not null" json:"role"`
+	Status         string     `gorm:"size:20;default:invited" json:"status"`
+	InvitedBy      string     `gorm:"type:char(60)" json:"invited_by"`
+	JoinedAt       *time.Time `json:"joined_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Relationships
+	User         User          `gorm:"foreignKey:UserID;references:UserID" json:"user"`
+	Organization *Organization `gorm:"foreignKey:OrganizationID;references:OrganizationID" json:"organization,omitempty"`
+}
+
 type Event struct {
-	EventID          string    `gorm:"primaryKey;type:char(60)" json:"event_id"`
+	EventID            string     `gorm:"primaryKey;type:char(60)" json:"event_id"`
+	Name               string     `gorm:"size:100" json:"name"`
+	Slug               string     `gorm:"size:160;index" json:"slug"` // slug aktif, riwayatnya di EventSlug
+	OwnerID            string     `gorm:"type:char(60);not null" json:"owner_id"`
+	OrganizationID     *string    `gorm:"type:char(60);index" json:"organization_id"`
+	Status             string     `gorm:"size:20;default:pending" json:"status"`
+	ApprovalComment    string     `gorm:"type:text" json:"approval_comment"`
+	DateStart          time.Time  `json:"date_start"` // disimpan dalam UTC
+	DateEnd            time.Time  `json:"date_end"`
+	TimeZone           string     `gorm:"size:64;default:Asia/Jakarta" json:"time_zone"` // nama zona IANA lokasi event
+	Location           string     `gorm:"size:255" json:"location"`
+	Venue              string     `gorm:"size:100" json:"venue"`
+	District           string     `gorm:"size:100" json:"district"`
+	VenueID            *string    `gorm:"type:char(60);index" json:"venue_id"` // venue direktori; Venue/Location/District disalin darinya
+	Latitude           *float64   `gorm:"type:decimal(10,7);index:idx_event_coordinates" json:"latitude"`
+	Longitude          *float64   `gorm:"type:decimal(10,7);index:idx_event_coordinates" json:"longitude"`
+	Description        string     `gorm:"type:text" json:"description"`
+	Rules              string     `gorm:"type:text" json:"rules"`
+	Image              string     `gorm:"size:255" json:"image"`
+	Flyer              string     `gorm:"size:255" json:"flyer"`
+	CategoryID         *string    `gorm:"type:char(60);index" json:"category_id"`
+	ChildCategoryID    *string    `gorm:"type:char(60);index" json:"child_category_id"`
+	Category           string     `gorm:"size:50" json:"category"`       // nama kategori, disalin dari CategoryID
+	ChildCategory      string     `gorm:"size:50" json:"child_category"` // nama sub kategori, disalin dari ChildCategoryID
+	TotalAttendant     uint       `gorm:"default:0" json:"total_attendant"`
+	TotalLikes         uint       `gorm:"default:0" json:"total_likes"`
+	TotalSales         float64    `gorm:"type:decimal(10,2);default:0" json:"total_sales"`
+	TotalTicketsSold   uint       `gorm:"default:0" json:"total_tickets_sold"`
+	TrendingScore      float64    `gorm:"default:0;index" json:"trending_score"` // dihitung ulang berkala oleh job trending
+	RatingAverage      float64    `gorm:"type:decimal(3,2);default:0" json:"rating_average"`
+	RatingCount        uint       `gorm:"default:0" json:"rating_count"`
+	SeriesID           *string    `gorm:"type:char(60);index" json:"series_id"`
+	SeriesException    bool       `gorm:"default:false" json:"series_exception"`
+	CancelledAt        *time.Time `json:"cancelled_at"`
+	CancellationReason string     `gorm:"type:text" json:"cancellation_reason"`
+	OriginalDateStart  *time.Time `json:"original_date_start"` // tanggal awal sebelum ditunda pertama kali
+	PostponedAt        *time.Time `json:"postponed_at"`
+	PostponeReason     string     `gorm:"type:text" json:"postpone_reason"`
+	RefundWindowEnd    *time.Time `json:"refund_window_end"` // batas pemegang tiket memilih refund setelah penundaan
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+
+	// Jarak (km) dari titik pencarian, hanya terisi pada query nearby
+	Distance *float64 `gorm:"->;-:migration" json:"distance_km,omitempty"`
+
+	// Waktu lokal sesuai TimeZone, diisi oleh LocalizeTimes
+	DateStartLocal string `gorm:"-" json:"date_start_local,omitempty"`
+	DateEndLocal   string `gorm:"-" json:"date_end_local,omitempty"`
+
+	// Bahasa teks name/description/rules pada respons ini (default atau terjemahan)
+	Locale string `gorm:"-" json:"locale,omitempty"`
+
+	// Relationships
+	Owner            User                `gorm:"foreignKey:OwnerID;references:UserID" json:"owner"`
+	Organization     *Organization       `gorm:"foreignKey:OrganizationID;references:OrganizationID" json:"organization,omitempty"`
+	VenueDetail      *Venue              `gorm:"foreignKey:VenueID;references:VenueID" json:"venue_detail,omitempty"`
+	CategoryRef      *EventCategory      `gorm:"foreignKey:CategoryID;references:EventCategoryID" json:"-"`
+	ChildCategoryRef *ChildEventCategory `gorm:"foreignKey:ChildCategoryID;references:ChildEventCategoryID" json:"-"`
+	TicketCategories []TicketCategory    `gorm:"foreignKey:EventID" json:"ticket_categories,omitempty"`
+	Gallery          []EventMedia        `gorm:"foreignKey:EventID" json:"gallery,omitempty"`
+	Tickets          []Ticket            `gorm:"foreignKey:EventID" json:"tickets,omitempty"`
+	LikedBy          []User              `gorm:"many2many:event_likes;foreignKey:EventID;joinForeignKey:event_id;references:UserID;joinReferences:user_id" json:"liked_by,omitempty"`
+}
+
+// LocalizeTimes mengisi representasi waktu lokal event dan kategori tiketnya
+func (e *Event) LocalizeTimes() {
+	loc := utils.TimeZoneOrDefault(e.TimeZone)
+	e.DateStartLocal = utils.FormatLocal(e.DateStart, loc)
+	e.DateEndLocal = utils.FormatLocal(e.DateEnd, loc)
+	for i := range e.TicketCategories {
+		e.TicketCategories[i].LocalizeTimes(loc)
+	}
+}
+
+// AfterFind dijalankan GORM setelah query (termasuk preload), sehingga respons selalu
+// membawa waktu UTC sekaligus waktu lokal
+func (e *Event) AfterFind(tx *gorm.DB) error {
+	e.LocalizeTimes()
+	return nil
+}
+
+// Venue adalah tempat acara di direktori venue. Venue usulan organizer berstatus pending
+// sampai dikurasi admin lewat antrian moderasi.
+type Venue struct {
+	VenueID         string    `gorm:"primaryKey;type:char(60)" json:"venue_id"`
+	Name            string    `gorm:"size:100;not null" json:"name"`
+	NormalizedKey   string    `gorm:"size:160;index" json:"-"` // nama ternormalisasi untuk deteksi duplikat
+	Address         string    `gorm:"size:255" json:"address"`
+	District        string    `gorm:"size:100;index" json:"district"`
+	Latitude        *float64  `gorm:"type:decimal(10,7)" json:"latitude"`
+	Longitude       *float64  `gorm:"type:decimal(10,7)" json:"longitude"`
+	Capacity        uint      `gorm:"default:0" json:"capacity"`
+	Facilities      []string  `gorm:"serializer:json;type:text" json:"facilities"`
+	SeatMapURL      string    `gorm:"size:255" json:"seat_map_url"`
+	SeatMapPublicID string    `gorm:"size:255" json:"-"`
+	Status          string    `gorm:"size:20;default:approved;index" json:"status"` // approved, pending, rejected
+	ProposedBy      *string   `gorm:"type:char(60);index" json:"proposed_by"`
+	ReviewComment   string    `gorm:"type:text" json:"review_comment"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// EventTemplate adalah cetakan event bernama yang bisa dipakai ulang organizer atau organisasinya
+type EventTemplate struct {
+	TemplateID     string    `gorm:"primaryKey;type:char(60)" json:"template_id"`
+	OwnerID        string    `gorm:"type:char(60);not null;index" json:"owner_id"`
+	OrganizationID *string   `gorm:"type:char(60);index" json:"organization_id"`
+	Name           string    `gorm:"size:100;not null" json:"name"`
+	SourceEventID  *string   `gorm:"type:char(60)" json:"source_event_id"`
+	Data           string    `gorm:"type:text" json:"-"` // JSON EventTemplateData, jadwal relatif terhadap waktu mulai
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// EventTranslation adalah teks event dalam bahasa selain bahasa default.
+// Field kosong jatuh kembali ke teks default.
+type EventTranslation struct {
+	EventID     string    `gorm:"primaryKey;type:char(60)" json:"event_id"`
+	Locale      string    `gorm:"primaryKey;size:10" json:"locale"`
+	Name        string    `gorm:"size:100" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	Rules       string    `gorm:"type:text" json:"rules"`
+	UpdatedBy   string    `gorm:"type:char(60)" json:"updated_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TicketCategoryTranslation adalah nama dan deskripsi ticket category dalam bahasa lain
+type TicketCategoryTranslation struct {
+	TicketCategoryID string    `gorm:"primaryKey;type:char(60)" json:"ticket_category_id"`
+	Locale           string    `gorm:"primaryKey;size:10" json:"locale"`
+	EventID          string    `gorm:"type:char(60);not null;index" json:"event_id"`
 	Name             string    `gorm:"size:100" json:"name"`
-	OwnerID          string    `gorm:"type:char(60);not null" json:"owner_id"`
-	Status           string    `gorm:"size:20;default:pending" json:"status"`
-	ApprovalComment  string    `gorm:"type:text" json:"approval_comment"`
-	DateStart        time.Time `json:"date_start"`
-	DateEnd          time.Time `json:"date_end"`
-	Location         string    `gorm:"size:255" json:"location"`
-	Venue            string    `gorm:"size:100" json:"venue"`
-	District         string    `gorm:"size:100" json:"district"`
 	Description      string    `gorm:"type:text" json:"description"`
-	Rules            string    `gorm:"type:text" json:"rules"`
-	Image            string    `gorm:"size:255" json:"image"`
-	Flyer            string    `gorm:"size:255" json:"flyer"`
-	Category         string    `gorm:"size:50" json:"category"`
-	ChildCategory    string    `gorm:"size:50" json:"child_category"`
-	TotalAttendant   uint      `gorm:"default:0" json:"total_attendant"`
-	TotalLikes       uint      `gorm:"default:0" json:"total_likes"`
-	TotalSales       float64   `gorm:"type:decimal(10,2);default:0" json:"total_sales"`
-	TotalTicketsSold uint      `gorm:"default:0" json:"total_tickets_sold"`
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// EventSlug menyimpan setiap slug yang pernah dipakai event agar URL lama tetap bisa di-redirect
+type EventSlug struct {
+	Slug      string    `gorm:"primaryKey;size:160" json:"slug"`
+	EventID   string    `gorm:"type:char(60);not null;index" json:"event_id"`
+	Base      string    `gorm:"size:160" json:"base"` // slug dasar saat slug ini dibuat; kosong untuk data lama
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EventMedia adalah gambar pada galeri event, diurutkan berdasarkan Position
+type EventMedia struct {
+	MediaID   string    `gorm:"primaryKey;type:char(60)" json:"media_id"`
+	EventID   string    `gorm:"type:char(60);not null;index" json:"event_id"`
+	URL       string    `gorm:"size:255" json:"url"`
+	PublicID  string    `gorm:"size:255" json:"-"`
+	Caption   string    `gorm:"size:255" json:"caption"`
+	Position  int       `gorm:"default:0" json:"position"`
+	IsCover   bool      `gorm:"default:false" json:"is_cover"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type EventSeries struct {
+	SeriesID        string    `gorm:"primaryKey;type:char(60)" json:"series_id"`
+	OwnerID         string    `gorm:"type:char(60);not null;index" json:"owner_id"`
+	OrganizationID  *string   `gorm:"type:char(60);index" json:"organization_id"`
+	Name            string    `gorm:"size:100" json:"name"`
+	Description     string    `gorm:"type:text" json:"description"`
+	Rules           string    `gorm:"type:text" json:"rules"`
+	Location        string    `gorm:"size:255" json:"location"`
+	Venue           string    `gorm:"size:100" json:"venue"`
+	District        string    `gorm:"size:100" json:"district"`
+	Image           string    `gorm:"size:255" json:"image"`
+	Flyer           string    `gorm:"size:255" json:"flyer"`
+	CategoryID      *string   `gorm:"type:char(60);index" json:"category_id"`
+	ChildCategoryID *string   `gorm:"type:char(60);index" json:"child_category_id"`
+	Category        string    `gorm:"size:50" json:"category"`
+	ChildCategory   string    `gorm:"size:50" json:"child_category"`
+	RecurrenceRule  string    `gorm:"size:255" json:"recurrence_rule"`
+	TimeZone        string    `gorm:"size:64;default:Asia/Jakarta" json:"time_zone"` // aturan pengulangan dihitung pada jam lokal zona ini
+	DurationMinutes uint      `json:"duration_minutes"`
+	TicketTemplate  string    `gorm:"type:text" json:"ticket_template"` // JSON []SeriesTicketTemplate
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 
 	// Relationships
-	Owner            User             `gorm:"foreignKey:OwnerID;references:UserID" json:"owner"`
-	TicketCategories []TicketCategory `gorm:"foreignKey:EventID" json:"ticket_categories,omitempty"`
-	Tickets          []Ticket         `gorm:"foreignKey:EventID" json:"tickets,omitempty"`
-	LikedBy          []User           `gorm:"many2many:event_likes;foreignKey:EventID;joinForeignKey:event_id;references:UserID;joinReferences:user_id" json:"liked_by,omitempty"`
+	Owner  User    `gorm:"foreignKey:OwnerID;references:UserID" json:"owner"`
+	Events []Event `gorm:"foreignKey:SeriesID" json:"events,omitempty"`
 }
 
 type TicketCategory struct {
@@ -70,6 +263,9 @@ type TicketCategory struct {
 	Price            float64   `gorm:"type:decimal(10,2)" json:"price"`
 	Quota            uint      `json:"quota"`
 	Sold             uint      `gorm:"default:0" json:"sold"`
+	CompQuota        uint      `gorm:"default:0" json:"comp_quota"`
+	CompIssued       uint      `gorm:"default:0" json:"comp_issued"`
+	IsHidden         bool      `gorm:"default:false" json:"is_hidden"`
 	Description      string    `gorm:"type:text" json:"description"`
 	DateTimeStart    time.Time `json:"date_time_start"`
 	DateTimeEnd      time.Time `json:"date_time_end"`
@@ -77,32 +273,153 @@ type TicketCategory struct {
 	UpdatedAt        time.Time `json:"updated_at"`
 	Attendant        uint      `gorm:"default:0" json:"attendant"`
 
+	// Waktu lokal sesuai zona event, diisi lewat Event.LocalizeTimes
+	DateTimeStartLocal string `gorm:"-" json:"date_time_start_local,omitempty"`
+	DateTimeEndLocal   string `gorm:"-" json:"date_time_end_local,omitempty"`
+	Locale             string `gorm:"-" json:"locale,omitempty"`
+
 	// Relationships
 	Tickets            []Ticket            `gorm:"foreignKey:TicketCategoryID" json:"tickets,omitempty"`
 	Carts              []Cart              `gorm:"foreignKey:TicketCategoryID" json:"carts,omitempty"`
 	TransactionDetails []TransactionDetail `gorm:"foreignKey:TicketCategoryID" json:"transaction_details,omitempty"`
 }
 
+// LocalizeTimes mengisi waktu penjualan dalam zona loc
+func (tc *TicketCategory) LocalizeTimes(loc *time.Location) {
+	tc.DateTimeStartLocal = utils.FormatLocal(tc.DateTimeStart, loc)
+	tc.DateTimeEndLocal = utils.FormatLocal(tc.DateTimeEnd, loc)
+}
+
 type Ticket struct {
-	TicketID         string    `gorm:"primaryKey;type:char(60)" json:"ticket_id"`
-	EventID          string    `gorm:"type:char(60);not null" json:"event_id"`
-	TicketCategoryID string    `gorm:"type:char(60);not null" json:"ticket_category_id"`
-	OwnerID          string    `gorm:"type:char(60);not null" json:"owner_id"`
-	Status           string    `gorm:"size:20;default:active" json:"status"`
-	Code             string    `gorm:"size:100;uniqueIndex" json:"code"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
-	ExpiresAt        time.Time `json:"expires_at"`
-	Tag              string    `gorm:"size:100" json:"tag" default:"My Ticket"`
+	TicketID         string     `gorm:"primaryKey;type:char(60)" json:"ticket_id"`
+	EventID          string     `gorm:"type:char(60);not null" json:"event_id"`
+	TicketCategoryID string     `gorm:"type:char(60);not null" json:"ticket_category_id"`
+	OwnerID          string     `gorm:"type:char(60);not null" json:"owner_id"`
+	Status           string     `gorm:"size:20;default:active" json:"status"`
+	Code             string     `gorm:"size:100;uniqueIndex" json:"code"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	Tag              string     `gorm:"size:100" json:"tag" default:"My Ticket"`
+	IsComplimentary  bool       `gorm:"default:false" json:"is_complimentary"`
+	CheckedInAt      *time.Time `json:"checked_in_at"` // kosong untuk check-in sebelum kolom ini ada (pakai updated_at)
 
 	// Relationships
 	Owner User `gorm:"foreignKey:OwnerID" json:"owner"`
 }
 
+type CompTicketClaim struct {
+	ClaimID          string     `gorm:"primaryKey;type:char(60)" json:"claim_id"`
+	EventID          string     `gorm:"type:char(60);not null;index" json:"event_id"`
+	TicketCategoryID string     `gorm:"type:char(60);not null" json:"ticket_category_id"`
+	IssuedBy         string     `gorm:"type:char(60);not null" json:"issued_by"`
+	Email            string     `gorm:"size:100;index" json:"email"`
+	Quantity         uint       `gorm:"default:1" json:"quantity"`
+	Note             string     `gorm:"size:255" json:"note"`
+	Status           string     `gorm:"size:20;default:pending" json:"status"`
+	ClaimedBy        string     `gorm:"type:char(60)" json:"claimed_by"`
+	ClaimedAt        *time.Time `json:"claimed_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+type AccessCode struct {
+	AccessCodeID string     `gorm:"primaryKey;type:char(60)" json:"access_code_id"`
+	EventID      string     `gorm:"type:char(60);not null;index" json:"event_id"`
+	Code         string     `gorm:"size:50;uniqueIndex" json:"code"`
+	UsageType    string     `gorm:"size:20;default:multi" json:"usage_type"` // single, multi
+	UsageLimit   uint       `gorm:"default:0" json:"usage_limit"`            // 0 = tanpa batas (multi)
+	UsedCount    uint       `gorm:"default:0" json:"used_count"`
+	IsActive     bool       `gorm:"default:true" json:"is_active"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Relationships
+	TicketCategories []TicketCategory `gorm:"many2many:access_code_categories;foreignKey:AccessCodeID;joinForeignKey:access_code_id;references:TicketCategoryID;joinReferences:ticket_category_id" json:"ticket_categories,omitempty"`
+}
+
+// RegistrationQuestion adalah pertanyaan pendaftaran yang dijawab pemegang tiket event.
+// TicketCategoryIDs kosong berarti berlaku untuk semua kategori.
+type RegistrationQuestion struct {
+	QuestionID        string    `gorm:"primaryKey;type:char(60)" json:"question_id"`
+	EventID           string    `gorm:"type:char(60);not null;index" json:"event_id"`
+	Label             string    `gorm:"size:200;not null" json:"label"`
+	Type              string    `gorm:"size:20;default:text" json:"type"` // text, choice
+	Options           []string  `gorm:"serializer:json;type:text" json:"options"`
+	Required          bool      `gorm:"default:false" json:"required"`
+	Position          int       `gorm:"default:0" json:"position"`
+	TicketCategoryIDs []string  `gorm:"serializer:json;type:text" json:"ticket_category_ids"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// RegistrationAnswer adalah jawaban satu tiket untuk satu pertanyaan pendaftaran
+type RegistrationAnswer struct {
+	TicketID   string    `gorm:"primaryKey;type:char(60)" json:"ticket_id"`
+	QuestionID string    `gorm:"primaryKey;type:char(60)" json:"question_id"`
+	EventID    string    `gorm:"type:char(60);not null;index" json:"event_id"`
+	Value      string    `gorm:"type:text" json:"value"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// EventAnnouncement adalah pengumuman organizer untuk pemegang tiket event.
+// Tanpa TicketCategories berarti untuk semua kategori.
+type EventAnnouncement struct {
+	AnnouncementID string     `gorm:"primaryKey;type:char(60)" json:"announcement_id"`
+	EventID        string     `gorm:"type:char(60);not null;index" json:"event_id"`
+	AuthorID       string     `gorm:"type:char(60);not null" json:"author_id"`
+	Title          string     `gorm:"size:150" json:"title"`
+	Message        string     `gorm:"type:text" json:"message"`
+	RecipientCount uint       `gorm:"default:0" json:"recipient_count"`
+	NotifiedAt     *time.Time `json:"notified_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Relationships
+	TicketCategories []TicketCategory `gorm:"many2many:announcement_categories;foreignKey:AnnouncementID;joinForeignKey:announcement_id;references:TicketCategoryID;joinReferences:ticket_category_id" json:"ticket_categories,omitempty"`
+}
+
+// EventChangeRequest menampung perubahan yang diajukan organizer untuk event yang sudah live.
+// Versi live tetap berjalan sampai admin menyetujui item perubahan satu per satu.
+type EventChangeRequest struct {
+	ChangeRequestID string     `gorm:"primaryKey;type:char(60)" json:"change_request_id"`
+	EventID         string     `gorm:"type:char(60);not null;index" json:"event_id"`
+	RequestedBy     string     `gorm:"type:char(60);not null" json:"requested_by"`
+	Status          string     `gorm:"size:20;default:pending;index" json:"status"` // pending, approved, partially_approved, rejected, cancelled
+	Note            string     `gorm:"type:text" json:"note"`
+	ReviewedBy      *string    `gorm:"type:char(60)" json:"reviewed_by"`
+	ReviewComment   string     `gorm:"type:text" json:"review_comment"`
+	ReviewedAt      *time.Time `json:"reviewed_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// Relationships
+	Event     Event                    `gorm:"foreignKey:EventID;references:EventID" json:"event,omitempty"`
+	Requester User                     `gorm:"foreignKey:RequestedBy;references:UserID" json:"requester,omitempty"`
+	Items     []EventChangeRequestItem `gorm:"foreignKey:ChangeRequestID" json:"items"`
+}
+
+// EventChangeRequestItem adalah satu perubahan field (atau satu ticket category) yang diputuskan terpisah
+type EventChangeRequestItem struct {
+	ItemID           string    `gorm:"primaryKey;type:char(60)" json:"item_id"`
+	ChangeRequestID  string    `gorm:"type:char(60);not null;index" json:"change_request_id"`
+	Field            string    `gorm:"size:50" json:"field"`                    // nama kolom event atau "ticket_category"
+	TicketCategoryID string    `gorm:"type:char(60)" json:"ticket_category_id"` // kosong = category baru
+	OldValue         string    `gorm:"type:text" json:"old_value"`
+	NewValue         string    `gorm:"type:text" json:"new_value"`
+	Status           string    `gorm:"size:20;default:pending" json:"status"` // pending, approved, rejected, failed
+	Reason           string    `gorm:"type:text" json:"reason"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
 type Cart struct {
 	CartID           string    `gorm:"primaryKey;type:char(60)" json:"cart_id"`
 	TicketCategoryID string    `gorm:"type:char(60);not null" json:"ticket_category_id"`
 	OwnerID          string    `gorm:"type:char(60);not null" json:"owner_id"`
+	AccessCodeID     string    `gorm:"type:char(60)" json:"access_code_id"`
 	Quantity         uint      `gorm:"default:1" json:"quantity"`
 	PriceTotal       float64   `gorm:"type:decimal(10,2)" json:"price_total"`
 	CreatedAt        time.Time `json:"created_at"`
@@ -131,6 +448,7 @@ type TransactionDetail struct {
 	TicketCategoryID    string  `gorm:"type:char(60);not null" json:"ticket_category_id"`
 	TransactionID       string  `gorm:"type:char(60);not null" json:"transaction_id"`
 	OwnerID             string  `gorm:"type:char(60);not null" json:"owner_id"`
+	AccessCodeID        string  `gorm:"type:char(60)" json:"access_code_id"`
 	Quantity            uint    `json:"quantity"`
 	Subtotal            float64 `gorm:"type:decimal(10,2)" json:"subtotal"`
 
@@ -138,14 +456,168 @@ type TransactionDetail struct {
 	Owner User `gorm:"foreignKey:OwnerID" json:"owner"`
 }
 
+// EventViewDaily menyimpan jumlah view detail event per hari (UTC) untuk skor trending
+type EventViewDaily struct {
+	EventID string    `gorm:"primaryKey;type:char(60)" json:"event_id"`
+	Day     time.Time `gorm:"primaryKey;type:date" json:"day"`
+	Views   uint      `gorm:"default:0" json:"views"`
+}
+
 type EventLike struct {
-	UserID  string `gorm:"primaryKey;type:char(60);not null" json:"user_id"`
-	EventID string `gorm:"primaryKey;type:char(60);not null" json:"event_id"`
+	UserID    string     `gorm:"primaryKey;type:char(60);not null" json:"user_id"`
+	EventID   string     `gorm:"primaryKey;type:char(60);not null" json:"event_id"`
+	CreatedAt *time.Time `json:"created_at"` // kosong untuk like sebelum kolom ini ada
 
 	User  User  `gorm:"foreignKey:UserID;references:UserID" json:"user"`
 	Event Event `gorm:"foreignKey:EventID;references:EventID" json:"event"`
 }
 
+// ScheduledJob adalah job terjadwal yang disimpan di database dan dieksekusi oleh scheduler.
+// JobKey unik per job (misal "event_end:<event_id>") sehingga penjadwalan ulang cukup mengganti run_at.
+type ScheduledJob struct {
+	JobID       string     `gorm:"primaryKey;type:char(60)" json:"job_id"`
+	Type        string     `gorm:"size:50;index" json:"type"`
+	JobKey      string     `gorm:"size:150;uniqueIndex" json:"job_key"`
+	Payload     string     `gorm:"type:text" json:"payload"`
+	RunAt       time.Time  `gorm:"index:idx_job_due" json:"run_at"`
+	Status      string     `gorm:"size:20;default:pending;index:idx_job_due" json:"status"` // pending, running, done, failed, cancelled
+	Attempts    uint       `gorm:"default:0" json:"attempts"`
+	MaxAttempts uint       `gorm:"default:5" json:"max_attempts"`
+	LockedBy    string     `gorm:"size:100" json:"locked_by"`
+	LockedUntil *time.Time `json:"locked_until"`
+	LastError   string     `gorm:"type:text" json:"last_error"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Refund mencatat pengembalian dana untuk bagian transaksi yang terkait satu event
+type Refund struct {
+	RefundID        string     `gorm:"primaryKey;type:char(60)" json:"refund_id"`
+	TransactionID   string     `gorm:"type:char(60);not null;uniqueIndex:idx_refund_transaction_event" json:"transaction_id"`
+	EventID         string     `gorm:"type:char(60);not null;uniqueIndex:idx_refund_transaction_event;index" json:"event_id"`
+	OwnerID         string     `gorm:"type:char(60);not null;index" json:"owner_id"`
+	Amount          float64    `gorm:"type:decimal(10,2)" json:"amount"`
+	TicketCount     uint       `json:"ticket_count"`
+	Reason          string     `gorm:"size:50" json:"reason"`                       // event_cancelled, event_postponed
+	Status          string     `gorm:"size:20;default:pending;index" json:"status"` // pending, succeeded, failed, not_required
+	GatewayResponse string     `gorm:"type:text" json:"-"`
+	LastError       string     `gorm:"type:text" json:"last_error"`
+	RefundedAt      *time.Time `json:"refunded_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// Relationships
+	Event Event `gorm:"foreignKey:EventID;references:EventID" json:"event,omitempty"`
+}
+
+// Notification adalah notifikasi in-app untuk user
+type Notification struct {
+	NotificationID string     `gorm:"primaryKey;type:char(60)" json:"notification_id"`
+	UserID         string     `gorm:"type:char(60);not null;index" json:"user_id"`
+	Type           string     `gorm:"size:50" json:"type"`
+	Title          string     `gorm:"size:255" json:"title"`
+	Message        string     `gorm:"type:text" json:"message"`
+	EventID        string     `gorm:"type:char(60);index" json:"event_id"`
+	ReadAt         *time.Time `json:"read_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// DataMigration menandai migrasi data satu kali yang sudah dijalankan
+type DataMigration struct {
+	Name      string    `gorm:"primaryKey;size:100" json:"name"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// AuditLog mencatat aksi penting (siapa, apa, terhadap entitas mana) untuk keperluan audit
+type AuditLog struct {
+	AuditLogID string    `gorm:"primaryKey;type:char(60)" json:"audit_log_id"`
+	ActorID    string    `gorm:"type:char(60);index" json:"actor_id"`
+	Action     string    `gorm:"size:100;index" json:"action"`
+	EntityType string    `gorm:"size:50;index:idx_audit_entity" json:"entity_type"`
+	EntityID   string    `gorm:"type:char(60);index:idx_audit_entity" json:"entity_id"`
+	Details    string    `gorm:"type:text" json:"details"`
+	IPAddress  string    `gorm:"size:64" json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// EventReview adalah rating (1-5) dan ulasan dari pemegang tiket yang sudah check-in.
+// Status: visible, hidden
+type EventReview struct {
+	ReviewID       string     `gorm:"primaryKey;type:char(60)" json:"review_id"`
+	EventID        string     `gorm:"type:char(60);not null;uniqueIndex:idx_review_event_user" json:"event_id"`
+	UserID         string     `gorm:"type:char(60);not null;uniqueIndex:idx_review_event_user;index" json:"user_id"`
+	OrganizationID *string    `gorm:"type:char(60);index" json:"organization_id"`
+	Rating         uint       `gorm:"not null" json:"rating"`
+	Comment        string     `gorm:"type:text" json:"comment"`
+	Status         string     `gorm:"size:20;default:visible;index" json:"status"`
+	HiddenReason   string     `gorm:"type:text" json:"hidden_reason,omitempty"`
+	HiddenBy       *string    `gorm:"type:char(60)" json:"hidden_by,omitempty"`
+	HiddenAt       *time.Time `json:"hidden_at,omitempty"`
+	Reply          string     `gorm:"type:text" json:"reply"`
+	ReplyBy        *string    `gorm:"type:char(60)" json:"reply_by"`
+	RepliedAt      *time.Time `json:"replied_at"`
+	ReportCount    uint       `gorm:"default:0" json:"report_count"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;references:UserID" json:"user"`
+}
+
+// ReviewReport adalah laporan penyalahgunaan sebuah review. Status: open, resolved, dismissed
+type ReviewReport struct {
+	ReportID   string     `gorm:"primaryKey;type:char(60)" json:"report_id"`
+	ReviewID   string     `gorm:"type:char(60);not null;uniqueIndex:idx_review_reporter" json:"review_id"`
+	ReporterID string     `gorm:"type:char(60);not null;uniqueIndex:idx_review_reporter" json:"reporter_id"`
+	Reason     string     `gorm:"size:30;not null" json:"reason"`
+	Comment    string     `gorm:"type:text" json:"comment"`
+	Status     string     `gorm:"size:20;default:open;index" json:"status"`
+	ResolvedBy *string    `gorm:"type:char(60)" json:"resolved_by"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	// Relationships
+	Review EventReview `gorm:"foreignKey:ReviewID;references:ReviewID" json:"review,omitempty"`
+}
+
+// ModerationItem adalah satu pengajuan di antrian moderasi admin (event atau organizer).
+// Status: pending, approved, rejected, withdrawn
+type ModerationItem struct {
+	ItemID      string     `gorm:"primaryKey;type:char(60)" json:"item_id"`
+	EntityType  string     `gorm:"size:20;not null;index:idx_moderation_entity" json:"entity_type"` // event, organizer
+	EntityID    string     `gorm:"type:char(60);not null;index:idx_moderation_entity" json:"entity_id"`
+	SubmittedBy string     `gorm:"type:char(60)" json:"submitted_by"`
+	Status      string     `gorm:"size:20;default:pending;index" json:"status"`
+	AssignedTo  *string    `gorm:"type:char(60);index" json:"assigned_to"`
+	AssignedAt  *time.Time `json:"assigned_at"`
+	SubmittedAt time.Time  `gorm:"index" json:"submitted_at"`
+	DecidedBy   *string    `gorm:"type:char(60)" json:"decided_by"`
+	DecidedAt   *time.Time `json:"decided_at"`
+	ReasonCode  string     `gorm:"size:50" json:"reason_code"`
+	Comment     string     `gorm:"type:text" json:"comment"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Ringkasan entity (event/organizer), hanya diisi di response
+	Entity interface{} `gorm:"-" json:"entity,omitempty"`
+
+	// Relationships
+	History []ModerationAction `gorm:"foreignKey:ItemID" json:"history,omitempty"`
+}
+
+// ModerationAction adalah riwayat setiap aksi pada ModerationItem.
+// Action: submitted, resubmitted, claimed, assigned, released, approved, rejected, withdrawn
+type ModerationAction struct {
+	ActionID   string    `gorm:"primaryKey;type:char(60)" json:"action_id"`
+	ItemID     string    `gorm:"type:char(60);not null;index" json:"item_id"`
+	ActorID    string    `gorm:"type:char(60)" json:"actor_id"`
+	Action     string    `gorm:"size:20;not null" json:"action"`
+	AssignedTo *string   `gorm:"type:char(60)" json:"assigned_to,omitempty"`
+	ReasonCode string    `gorm:"size:50" json:"reason_code,omitempty"`
+	Comment    string    `gorm:"type:text" json:"comment,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 type Feedback struct {
 	FeedbackID       string    `gorm:"primaryKey;type:char(60);not null" json:"feedback_id"`
 	OwnerID          string    `gorm:"column:owner_id;type:char(60);not null" json:"owner_id"`
@@ -160,18 +632,30 @@ type Feedback struct {
 	User User `gorm:"foreignKey:OwnerID;reference:UserID" json:"user,omitempty"`
 }
 
+// EventCategory adalah master kategori event. Kategori nonaktif tidak bisa dipilih untuk event baru,
+// tetapi event lama tetap menyimpannya.
 type EventCategory struct {
-	EventCategoryID   string `gorm:"primaryKey;type:char(60);not null" json:"event_category_id"`
-	EventCategoryName string `gorm:"size:50;default:active" json:"event_category_name"`
+	EventCategoryID   string    `gorm:"primaryKey;type:char(60);not null" json:"event_category_id"`
+	EventCategoryName string    `gorm:"size:50;default:active" json:"event_category_name"`
+	Icon              string    `gorm:"size:255" json:"icon"`
+	SortOrder         int       `gorm:"default:0" json:"sort_order"`
+	IsActive          bool      `gorm:"default:true" json:"is_active"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 
 	ChildEventCategory []ChildEventCategory `gorm:"foreignKey:ParentCategoryID;reference:EventCategoryID" json:"child_event_category,omitempty"`
 }
 
 type ChildEventCategory struct {
-	ChildEventCategoryID   string `gorm:"primaryKey;type:char(60);not null" json:"child_event_category_id"`
-	ParentCategoryID       string `gorm:";type:char(60);not null" json:"event_category_id"`
-	ParentCategoryName     string `gorm:";type:char(60);not null" json:"event_category_name"`
-	ChildEventCategoryName string `gorm:"size:50;default:active" json:"child_event_category_name"`
+	ChildEventCategoryID   string    `gorm:"primaryKey;type:char(60);not null" json:"child_event_category_id"`
+	ParentCategoryID       string    `gorm:";type:char(60);not null" json:"event_category_id"`
+	ParentCategoryName     string    `gorm:";type:char(60);not null" json:"event_category_name"`
+	ChildEventCategoryName string    `gorm:"size:50;default:active" json:"child_event_category_name"`
+	Icon                   string    `gorm:"size:255" json:"icon"`
+	SortOrder              int       `gorm:"default:0" json:"sort_order"`
+	IsActive               bool      `gorm:"default:true" json:"is_active"`
+	CreatedAt              time.Time `json:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at"`
 
 	// Parent EventCategory `gorm:"foreignKey:ParentCategoryID;reference:EventCategoryID" json:"parent"`
 }
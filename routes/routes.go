@@ -25,36 +25,101 @@ func SetupRoutes(app *fiber.App) {
 	user.Get("/", middleware.AdminMiddleware, handlers.GetUsers)
 	user.Post("/:id/verify", middleware.AdminMiddleware, handlers.VerifyUser)
 
+	// Organization routes
+	app.Get("/api/organizations/:id/reviews", handlers.GetOrganizationReviews)
+	organization := app.Group("/api/organizations", middleware.AuthMiddleware)
+	organization.Post("/", handlers.CreateOrganization)
+	organization.Get("/mine", handlers.GetMyOrganizations)
+	organization.Post("/invitations/:member_id", handlers.RespondToOrganizationInvitation)
+	organization.Get("/:id", handlers.GetOrganization)
+	organization.Put("/:id", handlers.UpdateOrganization)
+	organization.Get("/:id/events", handlers.GetOrganizationEvents)
+	organization.Get("/:id/report", handlers.GetOrganizationReport)
+	organization.Post("/:id/members", handlers.InviteOrganizationMember)
+	organization.Patch("/:id/members/:member_id", handlers.UpdateOrganizationMember)
+	organization.Delete("/:id/members/:member_id", handlers.RemoveOrganizationMember)
+
 	// Event routes
 	app.Get("/api/events", handlers.GetApprovedEvents)
 	app.Get("/api/event/:id", handlers.GetEvent)
+	app.Get("/api/event/:id/gallery", handlers.GetEventGallery)
+	app.Get("/api/event/:id/reviews", handlers.GetEventReviews)
 	app.Get("/api/events/popular", handlers.GetEventsPopular)
+	app.Get("/api/events/nearby", handlers.GetNearbyEvents)
+	app.Get("/api/events/map", handlers.GetEventsInBounds)
 	app.Get("/api/events/category", handlers.GetEventCategories)
+	app.Get("/api/events/slug/:slug", handlers.GetEventBySlug)
+	app.Get("/api/events/slug/:slug/meta", handlers.GetEventShareMetadata)
+	app.Get("/api/series/:id", handlers.GetEventSeries)
 	event := app.Group("/api/events", middleware.AuthMiddleware)
 	event.Get("/all", handlers.GetEvents)
 	event.Get("/my-events", handlers.GetMyEvents)
+	event.Get("/recommendations", handlers.GetRecommendedEvents)
 	event.Post("/", middleware.OrganizerApprovalMiddleware, handlers.CreateEvent)
+	event.Post("/series", middleware.OrganizerApprovalMiddleware, handlers.CreateEventSeries)
+	event.Get("/series/mine", handlers.GetMyEventSeries)
+	event.Post("/import", middleware.AdminMiddleware, handlers.ImportEvents)
+	event.Get("/export", handlers.ExportEvents)
+	event.Put("/series/:id", handlers.UpdateEventSeries)
 	event.Put("/:id", handlers.UpdateEvent)
 	event.Delete("/:id", handlers.DeleteEvent)
+	event.Post("/:id/cancel", handlers.CancelEvent)
+	event.Post("/:id/postpone", handlers.PostponeEvent)
+	event.Post("/:id/duplicate", middleware.OrganizerApprovalMiddleware, handlers.DuplicateEvent)
+	event.Get("/:id/refunds", handlers.GetEventRefunds)
+	event.Post("/:id/gallery", handlers.AddEventMedia)
+	event.Put("/:id/gallery/order", handlers.ReorderEventMedia)
+	event.Patch("/:id/gallery/:media_id", handlers.UpdateEventMedia)
+	event.Delete("/:id/gallery/:media_id", handlers.DeleteEventMedia)
 	event.Get("/:id/report", handlers.GetEventReport)
 	event.Get("/:id/report/download", handlers.DownloadEventReport)
+	event.Get("/:id/report/timeseries", handlers.GetEventSalesAnalytics)
+	event.Get("/:id/attendees/export", handlers.ExportEventAttendees)
+	event.Post("/:id/registration-questions", handlers.CreateRegistrationQuestion)
+	event.Get("/:id/registration-questions", handlers.GetRegistrationQuestions)
+	event.Put("/:id/registration-questions/:question_id", handlers.UpdateRegistrationQuestion)
+	event.Delete("/:id/registration-questions/:question_id", handlers.DeleteRegistrationQuestion)
 	event.Patch("/:id/verify", middleware.AdminMiddleware, handlers.VerifyEvent)
+	event.Get("/change-requests", middleware.AdminMiddleware, handlers.GetPendingChangeRequests)
+	event.Patch("/change-requests/:request_id/review", middleware.AdminMiddleware, handlers.ReviewEventChangeRequest)
+	event.Post("/:id/change-requests", handlers.SubmitEventChangeRequest)
+	event.Get("/:id/change-requests", handlers.GetEventChangeRequests)
+	event.Delete("/:id/change-requests/:request_id", handlers.CancelEventChangeRequest)
+	event.Post("/:id/comp-tickets", handlers.IssueCompTickets)
+	event.Get("/:id/comp-tickets", handlers.GetCompTickets)
+	event.Post("/:id/access-codes", handlers.CreateAccessCode)
+	event.Get("/:id/access-codes", handlers.GetAccessCodes)
+	event.Patch("/:id/access-codes/:code_id", handlers.UpdateAccessCode)
+	event.Delete("/:id/access-codes/:code_id", handlers.DeleteAccessCode)
+	event.Get("/reviews/reports", middleware.AdminMiddleware, handlers.GetReviewReports)
+	event.Post("/:id/reviews", handlers.CreateEventReview)
+	event.Put("/:id/reviews/:review_id", handlers.UpdateEventReview)
+	event.Delete("/:id/reviews/:review_id", handlers.DeleteEventReview)
+	event.Post("/:id/reviews/:review_id/reply", handlers.ReplyToEventReview)
+	event.Post("/:id/reviews/:review_id/report", handlers.ReportEventReview)
+	event.Patch("/:id/reviews/:review_id/moderation", middleware.AdminMiddleware, handlers.ModerateEventReview)
+	event.Post("/:id/announcements", handlers.CreateAnnouncement)
+	event.Get("/:id/announcements", handlers.GetAnnouncements)
+	event.Put("/:id/announcements/:announcement_id", handlers.UpdateAnnouncement)
+	event.Delete("/:id/announcements/:announcement_id", handlers.DeleteAnnouncement)
+	event.Get("/:id/translations", handlers.GetEventTranslations)
+	event.Put("/:id/translations/:locale", handlers.UpsertEventTranslation)
+	event.Delete("/:id/translations/:locale", handlers.DeleteEventTranslation)
 	event.Post("/:id/like", handlers.AddLike)
 	event.Get("/like", handlers.MyLikedEvent)
-	event.Post("/category/", middleware.AdminMiddleware, handlers.AddEventCategoryAll)
-	event.Post("/category/new", middleware.AdminMiddleware, handlers.AddEventCategory)
-	event.Post("/category/new/sub", middleware.AdminMiddleware, handlers.AddSubEventCategory)
-	event.Delete("/category/delete", middleware.AdminMiddleware, handlers.DeleteCategoryEvent)
-	event.Delete("/category/delete/sub", middleware.AdminMiddleware, handlers.DeleteSubCategoryEvent)
 
 	// Ticket routes
 	ticket := app.Group("/api/tickets", middleware.AuthMiddleware)
 	ticket.Get("/", handlers.GetTickets)
 	ticket.Get("/stats", handlers.GetTicketStats)
+	ticket.Post("/claims", handlers.RedeemCompClaims)
+	ticket.Post("/postponements/:event_id", handlers.RespondToPostponement)
 	ticket.Get("/:id", handlers.GetEvent)
 	ticket.Patch("/:event_id/:id/checkin", handlers.CheckInTicket)
 	ticket.Get("/:id/code", handlers.GetTicketCode)
 	ticket.Patch("/:id/tag", handlers.UpdateTagTicket)
+	ticket.Get("/:id/registration", handlers.GetTicketRegistration)
+	ticket.Put("/:id/registration", handlers.SubmitTicketRegistration)
 
 	// Cart routes
 	cart := app.Group("/api/cart", middleware.AuthMiddleware)
@@ -73,6 +138,63 @@ func SetupRoutes(app *fiber.App) {
 	transaction.Get("/", handlers.GetTransactionHistory)
 	transaction.Get("/:id", handlers.GetTransactionDetail)
 
+	// Refund routes
+	refund := app.Group("/api/refunds", middleware.AuthMiddleware)
+	refund.Get("/", handlers.GetMyRefunds)
+	refund.Post("/:id/retry", middleware.AdminMiddleware, handlers.RetryRefund)
+
+	// Event template routes
+	eventTemplate := app.Group("/api/event-templates", middleware.AuthMiddleware, middleware.OrganizerApprovalMiddleware)
+	eventTemplate.Get("/", handlers.GetEventTemplates)
+	eventTemplate.Post("/", handlers.CreateEventTemplate)
+	eventTemplate.Get("/:id", handlers.GetEventTemplate)
+	eventTemplate.Put("/:id", handlers.UpdateEventTemplate)
+	eventTemplate.Delete("/:id", handlers.DeleteEventTemplate)
+	eventTemplate.Post("/:id/events", handlers.CreateEventFromTemplate)
+
+	// Event category routes
+	app.Get("/api/categories", handlers.GetEventCategories)
+	category := app.Group("/api/categories", middleware.AuthMiddleware, middleware.AdminMiddleware)
+	category.Get("/all", handlers.GetAllEventCategories)
+	category.Post("/", handlers.CreateEventCategory)
+	category.Put("/order", handlers.ReorderEventCategories)
+	category.Put("/children/:id", handlers.UpdateChildEventCategory)
+	category.Delete("/children/:id", handlers.DeleteChildEventCategory)
+	category.Put("/:id", handlers.UpdateEventCategory)
+	category.Delete("/:id", handlers.DeleteEventCategory)
+	category.Post("/:id/children", handlers.CreateChildEventCategory)
+
+	// Venue directory routes
+	app.Get("/api/venues", handlers.GetVenues)
+	app.Get("/api/venues/:id", handlers.GetVenue)
+	venue := app.Group("/api/venues", middleware.AuthMiddleware)
+	venue.Post("/proposals", middleware.OrganizerApprovalMiddleware, handlers.ProposeVenue)
+	venue.Get("/proposals/mine", handlers.GetMyVenueProposals)
+	venue.Post("/:id/seat-map", handlers.UploadVenueSeatMap)
+	venue.Post("/", middleware.AdminMiddleware, handlers.CreateVenue)
+	venue.Put("/:id", middleware.AdminMiddleware, handlers.UpdateVenue)
+	venue.Post("/:id/merge", middleware.AdminMiddleware, handlers.MergeVenue)
+	venue.Delete("/:id", middleware.AdminMiddleware, handlers.DeleteVenue)
+
+	// Notification routes
+	notification := app.Group("/api/notifications", middleware.AuthMiddleware)
+	notification.Get("/", handlers.GetNotifications)
+	notification.Patch("/read-all", handlers.MarkAllNotificationsRead)
+	notification.Patch("/:id/read", handlers.MarkNotificationRead)
+
+	// Moderation routes
+	moderation := app.Group("/api/moderation", middleware.AuthMiddleware, middleware.AdminMiddleware)
+	moderation.Get("/", handlers.GetModerationQueue)
+	moderation.Get("/reasons", handlers.GetModerationReasons)
+	moderation.Get("/metrics", handlers.GetModerationMetrics)
+	moderation.Get("/:id", handlers.GetModerationItem)
+	moderation.Post("/:id/claim", handlers.ClaimModerationItem)
+	moderation.Post("/:id/assign", handlers.AssignModerationItem)
+	moderation.Post("/:id/decision", handlers.DecideModerationItem)
+
+	// Audit log routes
+	app.Get("/api/audit-logs", middleware.AuthMiddleware, middleware.AdminMiddleware, handlers.GetAuditLogs)
+
 	// Feedback routes
 	feedback := app.Group("/api/feedback", middleware.AuthMiddleware)
 	feedback.Post("/", handlers.CreateFeedback)
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale adalah bahasa teks utama event (kolom name/description/rules). DEFAULT_LOCALE, default "id".
+func DefaultLocale() string {
+	if locale := NormalizeLocale(os.Getenv("DEFAULT_LOCALE")); locale != "" {
+		return locale
+	}
+	return "id"
+}
+
+// NormalizeLocale mengambil subtag bahasa utama dari tag BCP 47 (misal "en-US" menjadi "en").
+// Mengembalikan string kosong jika tag tidak valid.
+func NormalizeLocale(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if len(tag) < 2 || len(tag) > 3 {
+		return ""
+	}
+	for _, r := range tag {
+		if r < 'a' || r > 'z' {
+			return ""
+		}
+	}
+	return tag
+}
+
+// ParseAcceptLanguage mengurai header Accept-Language menjadi daftar locale berurutan
+// dari preferensi tertinggi. Wildcard dan q=0 diabaikan.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var entries []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		locale := NormalizeLocale(fields[0])
+		if locale == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		entries = append(entries, weighted{locale: locale, q: q})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].q > entries[j].q
+	})
+
+	seen := make(map[string]bool)
+	locales := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !seen[entry.locale] {
+			seen[entry.locale] = true
+			locales = append(locales, entry.locale)
+		}
+	}
+	return locales
+}
@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxRecurrenceOccurrences membatasi jumlah occurrence yang bisa dihasilkan satu rule
+const MaxRecurrenceOccurrences = 100
+
+// RecurrenceRule adalah subset RRULE (RFC 5545): FREQ, INTERVAL, COUNT, UNTIL dan BYDAY
+type RecurrenceRule struct {
+	Freq     string // DAILY, WEEKLY, MONTHLY
+	Interval int
+	Count    int
+	Until    *time.Time
+	ByDay    []time.Weekday
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// ParseRecurrenceRule membaca string seperti "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,WE;COUNT=8".
+// UNTIL tanpa offset diartikan sebagai jam lokal loc (zona waktu series).
+func ParseRecurrenceRule(rule string, loc *time.Location) (RecurrenceRule, error) {
+	r := RecurrenceRule{Interval: 1}
+
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return r, errors.New("recurrence rule is empty")
+	}
+
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return r, fmt.Errorf("invalid rule part %q", part)
+		}
+		key, value := strings.ToUpper(strings.TrimSpace(kv[0])), strings.TrimSpace(kv[1])
+
+		switch key {
+		case "FREQ":
+			r.Freq = strings.ToUpper(value)
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return r, errors.New("INTERVAL must be a positive number")
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return r, errors.New("COUNT must be a positive number")
+			}
+			r.Count = n
+		case "UNTIL":
+			until, err := parseRRuleTime(value, loc)
+			if err != nil {
+				return r, errors.New("UNTIL must be YYYYMMDD, YYYYMMDDTHHMMSS(Z), RFC3339 or local time without offset")
+			}
+			r.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := rruleWeekdays[strings.ToUpper(strings.TrimSpace(day))]
+				if !ok {
+					return r, fmt.Errorf("invalid BYDAY value %q", day)
+				}
+				r.ByDay = append(r.ByDay, weekday)
+			}
+		default:
+			return r, fmt.Errorf("unsupported rule part %q", key)
+		}
+	}
+
+	switch r.Freq {
+	case "DAILY", "WEEKLY", "MONTHLY":
+	default:
+		return r, errors.New("FREQ must be DAILY, WEEKLY or MONTHLY")
+	}
+	if len(r.ByDay) > 0 && r.Freq != "WEEKLY" {
+		return r, errors.New("BYDAY is only supported with FREQ=WEEKLY")
+	}
+	if r.Count > MaxRecurrenceOccurrences {
+		return r, fmt.Errorf("COUNT cannot exceed %d", MaxRecurrenceOccurrences)
+	}
+
+	return r, nil
+}
+
+// Occurrences menghasilkan waktu mulai setiap occurrence, dimulai dari start (inklusif).
+// Tanpa COUNT dan UNTIL, hasil dibatasi MaxRecurrenceOccurrences.
+func (r RecurrenceRule) Occurrences(start time.Time) []time.Time {
+	limit := MaxRecurrenceOccurrences
+	if r.Count > 0 && r.Count < limit {
+		limit = r.Count
+	}
+
+	var result []time.Time
+	add := func(t time.Time) bool {
+		if r.Until != nil && t.After(*r.Until) {
+			return false
+		}
+		result = append(result, t)
+		return len(result) < limit
+	}
+
+	switch r.Freq {
+	case "DAILY":
+		for t := start; add(t); t = t.AddDate(0, 0, r.Interval) {
+		}
+	case "WEEKLY":
+		if len(r.ByDay) == 0 {
+			for t := start; add(t); t = t.AddDate(0, 0, 7*r.Interval) {
+			}
+			break
+		}
+		// Mulai dari hari Minggu pada minggu yang sama dengan start
+		weekStart := start.AddDate(0, 0, -int(start.Weekday()))
+		for week := 0; week < MaxRecurrenceOccurrences*7; week += r.Interval {
+			base := weekStart.AddDate(0, 0, 7*week)
+			if r.Until != nil && base.After(*r.Until) {
+				return result
+			}
+			for day := time.Sunday; day <= time.Saturday; day++ {
+				if !containsWeekday(r.ByDay, day) {
+					continue
+				}
+				t := base.AddDate(0, 0, int(day))
+				if t.Before(start) {
+					continue
+				}
+				if !add(t) {
+					return result
+				}
+			}
+		}
+	case "MONTHLY":
+		for i := 0; len(result) < limit && i < MaxRecurrenceOccurrences*12; i += r.Interval {
+			t := start.AddDate(0, i, 0)
+			// Lewati bulan yang tidak punya tanggal yang sama (misal tanggal 31)
+			if t.Day() != start.Day() {
+				continue
+			}
+			if !add(t) {
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRRuleTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		// UNTIL tanggal saja berarti sampai akhir hari lokal
+		return t.AddDate(0, 0, 1).Add(-time.Second).UTC(), nil
+	}
+	return ParseEventTime(value, loc)
+}
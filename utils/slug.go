@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+const maxSlugLength = 80
+
+// slugTransliterations mengganti huruf Latin beraksen yang umum dengan padanan ASCII
+var slugTransliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'ç': "c", 'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ñ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'œ': "oe",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ý': "y", 'ÿ': "y", 'ß': "ss",
+	'&': "and",
+}
+
+// Slugify mengubah teks bebas menjadi slug URL: huruf kecil ASCII, angka, dan tanda hubung.
+// Mengembalikan string kosong jika tidak ada karakter yang bisa dipakai.
+func Slugify(text string) string {
+	var b strings.Builder
+	lastDash := true
+	for _, r := range strings.ToLower(text) {
+		if replacement, ok := slugTransliterations[r]; ok {
+			if r == '&' && !lastDash {
+				b.WriteByte('-')
+			}
+			b.WriteString(replacement)
+			lastDash = false
+			if r == '&' {
+				b.WriteByte('-')
+				lastDash = true
+			}
+			continue
+		}
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			lastDash = false
+			continue
+		}
+		if !lastDash {
+			b.WriteByte('-')
+			lastDash = true
+		}
+	}
+
+	slug := strings.Trim(b.String(), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	// Database zona waktu IANA ikut di-embed supaya tidak bergantung pada tzdata di server
+	_ "time/tzdata"
+)
+
+// DefaultTimeZone dipakai untuk event yang tidak menyebutkan zona waktu (WIB)
+const DefaultTimeZone = "Asia/Jakarta"
+
+var ErrInvalidTimeZone = errors.New("invalid time_zone, use an IANA name such as Asia/Jakarta, Asia/Makassar or Asia/Jayapura")
+
+// wallClockLayouts adalah format tanpa offset yang diartikan sebagai jam lokal zona event
+var wallClockLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// LoadTimeZone memvalidasi nama zona waktu IANA. Nama kosong berarti DefaultTimeZone;
+// "Local" ditolak karena bergantung pada zona server.
+func LoadTimeZone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = DefaultTimeZone
+	}
+	if name == "Local" {
+		return nil, ErrInvalidTimeZone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimeZone
+	}
+	return loc, nil
+}
+
+// TimeZoneOrDefault seperti LoadTimeZone tetapi jatuh ke DefaultTimeZone untuk nama yang tidak valid
+func TimeZoneOrDefault(name string) *time.Location {
+	if loc, err := LoadTimeZone(name); err == nil {
+		return loc
+	}
+	loc, _ := time.LoadLocation(DefaultTimeZone)
+	return loc
+}
+
+// ParseEventTime mengurai waktu dari client dan mengembalikannya dalam UTC.
+// RFC3339 dengan offset (2024-07-01T18:00:00+08:00 atau ...Z) dipakai apa adanya,
+// sedangkan waktu tanpa offset (2024-07-01T18:00) diartikan sebagai jam lokal di loc.
+func ParseEventTime(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	for _, layout := range wallClockLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.New("use RFC3339 (e.g. 2024-07-01T18:00:00+07:00) or local time without offset (e.g. 2024-07-01T18:00)")
+}
+
+// FormatLocal memformat waktu sebagai RFC3339 dengan offset zona loc
+func FormatLocal(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(loc).Format(time.RFC3339)
+}
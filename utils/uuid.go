@@ -19,6 +19,10 @@ func GenerateEventID() string {
 	return GeneratePrefixedUUID("event")
 }
 
+func GenerateSeriesID() string {
+	return GeneratePrefixedUUID("series")
+}
+
 func GenerateTicketCategoryID() string {
 	return GeneratePrefixedUUID("tcat")
 }
@@ -34,6 +38,20 @@ func GenerateTicketCode() string {
 	return code
 }
 
+func GenerateCompClaimID() string {
+	return GeneratePrefixedUUID("claim")
+}
+
+func GenerateAccessCodeID() string {
+	return GeneratePrefixedUUID("acode")
+}
+
+func GenerateAccessCode() string {
+	uuidStr := uuid.New().String()
+	cleanUUID := strings.ReplaceAll(uuidStr, "-", "")
+	return strings.ToUpper(cleanUUID[:8])
+}
+
 func GenerateCartID() string {
 	return GeneratePrefixedUUID("cart")
 }
@@ -65,3 +83,71 @@ func GenerateEventCategoryID() string {
 func GenerateChildEventCategoryID() string {
 	return GeneratePrefixedUUID("child")
 }
+
+func GenerateChangeRequestID() string {
+	return GeneratePrefixedUUID("chreq")
+}
+
+func GenerateChangeRequestItemID() string {
+	return GeneratePrefixedUUID("chitem")
+}
+
+func GenerateJobID() string {
+	return GeneratePrefixedUUID("job")
+}
+
+func GenerateRefundID() string {
+	return GeneratePrefixedUUID("refund")
+}
+
+func GenerateNotificationID() string {
+	return GeneratePrefixedUUID("notif")
+}
+
+func GenerateAuditLogID() string {
+	return GeneratePrefixedUUID("audit")
+}
+
+func GenerateMediaID() string {
+	return GeneratePrefixedUUID("media")
+}
+
+func GenerateOrganizationID() string {
+	return GeneratePrefixedUUID("org")
+}
+
+func GenerateOrganizationMemberID() string {
+	return GeneratePrefixedUUID("orgmem")
+}
+
+func GenerateModerationItemID() string {
+	return GeneratePrefixedUUID("modq")
+}
+
+func GenerateModerationActionID() string {
+	return GeneratePrefixedUUID("modact")
+}
+
+func GenerateReviewID() string {
+	return GeneratePrefixedUUID("review")
+}
+
+func GenerateReviewReportID() string {
+	return GeneratePrefixedUUID("rvreport")
+}
+
+func GenerateAnnouncementID() string {
+	return GeneratePrefixedUUID("announce")
+}
+
+func GenerateVenueID() string {
+	return GeneratePrefixedUUID("venue")
+}
+
+func GenerateEventTemplateID() string {
+	return GeneratePrefixedUUID("tmpl")
+}
+
+func GenerateRegistrationQuestionID() string {
+	return GeneratePrefixedUUID("regq")
+}